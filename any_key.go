@@ -0,0 +1,189 @@
+package hashmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+)
+
+// ErrNotComparableKey ...
+//
+// nolint: gochecknoglobals
+//
+var ErrNotComparableKey = errors.New("value isn't comparable")
+
+type anyKey struct {
+	value interface{}
+	// it's an encoding of the value that is hashed instead of the value itself;
+	// equal values have equal encodings
+	data []byte
+}
+
+// AnyKey ...
+//
+// It adapts any comparable value (e.g. a struct, an array, a pointer
+// or a string) to the key interface. Values are hashed by the keyed SipHash
+// function via their encoding and are compared by the == operator, so keys
+// of different types are never equal.
+//
+// Pointers, channels and interfaces inside the value are hashed by their
+// addresses and dynamic values respectively, like the == operator compares
+// them. The value is encoded once, so its modification via pointers inside
+// doesn't affect the key.
+//
+// It returns an error if the value isn't comparable (e.g. a slice, a map,
+// a function or a struct with such a field). The error details
+// the ErrNotComparableKey error, which is returned by its Cause() and Unwrap()
+// methods.
+//
+func AnyKey(value interface{}) (Key, error) {
+	if data, ok := appendBasicValue(nil, value); ok {
+		return anyKey{value: value, data: data}, nil
+	}
+
+	data, err := appendValue(nil, reflect.ValueOf(value))
+	if err != nil {
+		return nil, newDetailedError(ErrNotComparableKey, "%T", value)
+	}
+
+	return anyKey{value: value, data: data}, nil
+}
+
+// Hash ...
+func (key anyKey) Hash() int {
+	return truncateHash(key.Hash64())
+}
+
+// Hash64 ...
+func (key anyKey) Hash64() uint64 {
+	return key.SeededHash(builtinHashKey0)
+}
+
+// SeededHash ...
+func (key anyKey) SeededHash(seed uint64) uint64 {
+	return sipHash(seed, builtinHashKey1, key.data)
+}
+
+// Equals ...
+func (key anyKey) Equals(other Key) bool {
+	otherKey, ok := other.(anyKey)
+	return ok && key.value == otherKey.value
+}
+
+// it's a fast path for basic types that avoids reflection
+func appendBasicValue(data []byte, value interface{}) ([]byte, bool) {
+	switch value := value.(type) {
+	case string:
+		return appendString(data, value), true
+	case int:
+		return appendUint64(data, uint64(value)), true
+	case int64:
+		return appendUint64(data, uint64(value)), true
+	case int32:
+		return appendUint64(data, uint64(value)), true
+	case uint:
+		return appendUint64(data, uint64(value)), true
+	case uint64:
+		return appendUint64(data, value), true
+	case uint32:
+		return appendUint64(data, uint64(value)), true
+	case float64:
+		return appendFloat(data, value), true
+	case bool:
+		return appendBool(data, value), true
+	}
+
+	return data, false
+}
+
+func appendValue(data []byte, value reflect.Value) ([]byte, error) {
+	// the zero value corresponds to nil
+	if !value.IsValid() {
+		return append(data, 0), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return appendBool(data, value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendUint64(data, uint64(value.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return appendUint64(data, value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(data, value.Float()), nil
+	case reflect.Complex64, reflect.Complex128:
+		number := value.Complex()
+		data = appendFloat(data, real(number))
+		return appendFloat(data, imag(number)), nil
+	case reflect.String:
+		return appendString(data, value.String()), nil
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return appendUint64(data, uint64(value.Pointer())), nil
+	case reflect.Interface:
+		if value.IsNil() {
+			return append(data, 0), nil
+		}
+
+		// values of different dynamic types aren't equal, but their encodings
+		// may be equal; it's only a collision of hashes
+		return appendValue(append(data, 1), value.Elem())
+	case reflect.Array:
+		for index := 0; index < value.Len(); index++ {
+			var err error
+			if data, err = appendValue(data, value.Index(index)); err != nil {
+				return nil, err
+			}
+		}
+
+		return data, nil
+	case reflect.Struct:
+		valueType := value.Type()
+		for index := 0; index < value.NumField(); index++ {
+			// blank fields are ignored by the == operator
+			if valueType.Field(index).Name == "_" {
+				continue
+			}
+
+			var err error
+			if data, err = appendValue(data, value.Field(index)); err != nil {
+				return nil, err
+			}
+		}
+
+		return data, nil
+	default:
+		return nil, ErrNotComparableKey
+	}
+}
+
+func appendBool(data []byte, value bool) []byte {
+	if value {
+		return append(data, 1)
+	}
+
+	return append(data, 0)
+}
+
+func appendUint64(data []byte, value uint64) []byte {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], value)
+
+	return append(data, buffer[:]...)
+}
+
+func appendFloat(data []byte, value float64) []byte {
+	// positive and negative zeros are equal, so they should be encoded equally
+	if value == 0 {
+		value = 0
+	}
+
+	return appendUint64(data, math.Float64bits(value))
+}
+
+// the length prefix separates adjacent strings, e.g. inside a struct
+func appendString(data []byte, value string) []byte {
+	data = appendUint64(data, uint64(len(value)))
+	return append(data, value...)
+}
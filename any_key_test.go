@@ -0,0 +1,170 @@
+package hashmap
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type anyKeyPoint struct {
+	x, y int
+	_    int
+	name string
+	tag  interface{}
+}
+
+func TestAnyKey(test *testing.T) {
+	one, two := 1, 1
+
+	for _, data := range []struct {
+		name      string
+		valueOne  interface{}
+		valueTwo  interface{}
+		wantEqual bool
+	}{
+		{
+			name:      "with equal strings",
+			valueOne:  "one",
+			valueTwo:  "one",
+			wantEqual: true,
+		},
+		{
+			name:      "with different strings",
+			valueOne:  "one",
+			valueTwo:  "two",
+			wantEqual: false,
+		},
+		{
+			name:      "with equal integers",
+			valueOne:  23,
+			valueTwo:  23,
+			wantEqual: true,
+		},
+		{
+			name:      "with integers of different types",
+			valueOne:  23,
+			valueTwo:  int64(23),
+			wantEqual: false,
+		},
+		{
+			name:      "with zeros of different signs",
+			valueOne:  0.0,
+			valueTwo:  math.Copysign(0, -1),
+			wantEqual: true,
+		},
+		{
+			name:      "with equal structs",
+			valueOne:  anyKeyPoint{x: 1, y: 2, name: "one", tag: "tag"},
+			valueTwo:  anyKeyPoint{x: 1, y: 2, name: "one", tag: "tag"},
+			wantEqual: true,
+		},
+		{
+			name:      "with structs different in a nested interface",
+			valueOne:  anyKeyPoint{x: 1, y: 2, name: "one", tag: "tag"},
+			valueTwo:  anyKeyPoint{x: 1, y: 2, name: "one", tag: nil},
+			wantEqual: false,
+		},
+		{
+			name:      "with equal arrays",
+			valueOne:  [3]int8{1, 2, 3},
+			valueTwo:  [3]int8{1, 2, 3},
+			wantEqual: true,
+		},
+		{
+			name:      "with different arrays",
+			valueOne:  [2]string{"ab", "c"},
+			valueTwo:  [2]string{"a", "bc"},
+			wantEqual: false,
+		},
+		{
+			name:      "with equal pointers",
+			valueOne:  &one,
+			valueTwo:  &one,
+			wantEqual: true,
+		},
+		{
+			name:      "with pointers to equal values",
+			valueOne:  &one,
+			valueTwo:  &two,
+			wantEqual: false,
+		},
+		{
+			name:      "with nils",
+			valueOne:  nil,
+			valueTwo:  nil,
+			wantEqual: true,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			keyOne, err := AnyKey(data.valueOne)
+			require.NoError(test, err)
+
+			keyTwo, err := AnyKey(data.valueTwo)
+			require.NoError(test, err)
+
+			assert.Equal(test, data.wantEqual, keyOne.Equals(keyTwo))
+			assert.Equal(test, data.wantEqual, keyTwo.Equals(keyOne))
+			if data.wantEqual {
+				assert.Equal(test, keyOne.Hash(), keyTwo.Hash())
+				assert.Equal(
+					test,
+					keyOne.(SeededKey).SeededHash(23),
+					keyTwo.(SeededKey).SeededHash(23),
+				)
+			}
+		})
+	}
+}
+
+func TestAnyKey_withNotComparableValue(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name:  "with a slice",
+			value: []int{1, 2},
+		},
+		{
+			name:  "with a map",
+			value: map[string]int{"one": 1},
+		},
+		{
+			name:  "with a function",
+			value: func() {},
+		},
+		{
+			name:  "with a struct with a slice in a nested interface",
+			value: anyKeyPoint{tag: []int{1, 2}},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := AnyKey(data.value)
+
+			assert.Nil(test, got)
+			assert.Equal(test, ErrNotComparableKey, errorCause(err))
+		})
+	}
+}
+
+func TestAnyKey_withHashMap(test *testing.T) {
+	newKey := func(value interface{}) Key {
+		key, err := AnyKey(value)
+		require.NoError(test, err)
+
+		return key
+	}
+
+	hashMap := NewHashMap()
+	hashMap.Set(newKey(anyKeyPoint{x: 1, y: 2}), "one")
+	hashMap.Set(newKey(anyKeyPoint{x: 2, y: 1}), "two")
+	hashMap.Set(newKey("one"), "three")
+	hashMap.Set(newKey(anyKeyPoint{x: 1, y: 2}), "four")
+
+	gotValue, gotOk := hashMap.Get(newKey(anyKeyPoint{x: 1, y: 2}))
+	assert.Equal(test, "four", gotValue)
+	assert.True(test, gotOk)
+	assert.Equal(test, 3, hashMap.Size())
+}
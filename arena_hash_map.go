@@ -0,0 +1,326 @@
+package hashmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	// a record consists of a key length, a value length, a key and a value
+	arenaHeaderSize    = 8
+	arenaMinRecordSize = 16
+)
+
+// it's a slot of the index; it has no pointers, so the GC doesn't scan
+// the index
+type arenaSlot struct {
+	hash uint64
+	// it's a slab index in high bits and an offset in low bits, plus one;
+	// zero means an empty slot
+	location uint64
+}
+
+// ArenaByteHandler ...
+//
+// Passed slices refer to memory of the map, so they are valid only during
+// the call and shouldn't be modified.
+//
+type ArenaByteHandler func(key []byte, value []byte) bool
+
+// ArenaHashMap ...
+//
+// It stores byte-slice keys and values in large pointer-free slabs instead
+// of separate objects, so the GC doesn't scan its items. Items are referenced
+// by offsets in slabs. Records of deleted items are reused via free lists
+// of size classes (powers of two).
+//
+// It's not safe for concurrent access.
+//
+type ArenaHashMap struct {
+	config ArenaConfig
+	slots  []arenaSlot
+	size   int
+	seed   uint64
+	slabs  [][]byte
+	// it's an offset of a free space in the last shared slab
+	slabOffset int
+	// they are indexed by a binary logarithm of a record size
+	freeLists [][]uint64
+}
+
+// NewArenaHashMap ...
+func NewArenaHashMap(options ...ArenaOption) *ArenaHashMap {
+	config := newArenaConfig(options)
+
+	seed := config.hashSeed
+	if seed == 0 {
+		seed = newHashSeed()
+	}
+
+	return &ArenaHashMap{
+		config:    config,
+		slots:     make([]arenaSlot, config.initialCapacity),
+		seed:      seed,
+		freeLists: make([][]uint64, bits.UintSize),
+	}
+}
+
+// Get ...
+//
+// It returns a copy of the value.
+//
+func (hashMap *ArenaHashMap) Get(key []byte) (value []byte, ok bool) {
+	index, ok := hashMap.find(key, hashMap.hash(key))
+	if !ok {
+		return nil, false
+	}
+
+	_, value = hashMap.record(hashMap.slots[index].location)
+	return append([]byte(nil), value...), true
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It randomizes of an iteration start.
+//
+// The map shouldn't be modified during iteration.
+//
+func (hashMap *ArenaHashMap) Iterate(handler ArenaByteHandler) bool {
+	capacity := len(hashMap.slots)
+	start := rand.Intn(capacity)
+	for step := 0; step < capacity; step++ {
+		slot := hashMap.slots[(start+step)%capacity]
+		if slot.location == 0 {
+			continue
+		}
+
+		if ok := handler(hashMap.record(slot.location)); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Set ...
+//
+// The key and the value are copied into the map.
+//
+func (hashMap *ArenaHashMap) Set(key []byte, value []byte) {
+	hash := hashMap.hash(key)
+	index, ok := hashMap.find(key, hash)
+	if ok {
+		slot := &hashMap.slots[index]
+		// the record is rewritten in place if the new one fits into it
+		class := arenaSizeClass(arenaHeaderSize + len(key) + len(value))
+		if class != hashMap.recordClass(slot.location) {
+			hashMap.free(slot.location)
+			slot.location = hashMap.allocate(class)
+		}
+
+		hashMap.writeRecord(slot.location, key, value)
+		return
+	}
+
+	class := arenaSizeClass(arenaHeaderSize + len(key) + len(value))
+	location := hashMap.allocate(class)
+	hashMap.writeRecord(location, key, value)
+
+	hashMap.slots[index] = arenaSlot{hash: hash, location: location}
+	hashMap.size++
+
+	loadFactor := float64(hashMap.size) / float64(len(hashMap.slots))
+	if loadFactor > hashMap.config.maxLoadFactor {
+		hashMap.grow()
+	}
+}
+
+// Delete ...
+func (hashMap *ArenaHashMap) Delete(key []byte) {
+	index, ok := hashMap.find(key, hashMap.hash(key))
+	if !ok {
+		return
+	}
+
+	hashMap.free(hashMap.slots[index].location)
+	hashMap.slots[index] = arenaSlot{}
+	hashMap.size--
+
+	hashMap.shiftBackward(index)
+}
+
+// Size ...
+func (hashMap *ArenaHashMap) Size() int {
+	return hashMap.size
+}
+
+// SlabCount ...
+func (hashMap *ArenaHashMap) SlabCount() int {
+	return len(hashMap.slabs)
+}
+
+func (hashMap *ArenaHashMap) hash(key []byte) uint64 {
+	return sipHash(hashMap.seed, builtinHashKey1, key)
+}
+
+func (hashMap *ArenaHashMap) homeIndex(hash uint64) int {
+	return int(hash % uint64(len(hashMap.slots)))
+}
+
+func (hashMap *ArenaHashMap) find(key []byte, hash uint64) (
+	index int,
+	ok bool,
+) {
+	capacity := len(hashMap.slots)
+	for index := hashMap.homeIndex(hash); ; index = (index + 1) % capacity {
+		slot := hashMap.slots[index]
+		if slot.location == 0 {
+			return index, false
+		}
+		if slot.hash != hash {
+			continue
+		}
+
+		slotKey, _ := hashMap.record(slot.location)
+		if bytes.Equal(slotKey, key) {
+			return index, true
+		}
+	}
+}
+
+// see the HashMap.shiftBackward() method
+func (hashMap *ArenaHashMap) shiftBackward(emptyIndex int) {
+	capacity := len(hashMap.slots)
+	for index := (emptyIndex + 1) % capacity; ; index = (index + 1) % capacity {
+		slot := hashMap.slots[index]
+		if slot.location == 0 {
+			return
+		}
+
+		homeIndex := hashMap.homeIndex(slot.hash)
+		homeDistance := (index - homeIndex + capacity) % capacity
+		emptyDistance := (index - emptyIndex + capacity) % capacity
+		if homeDistance < emptyDistance {
+			continue
+		}
+
+		hashMap.slots[emptyIndex] = slot
+		hashMap.slots[index] = arenaSlot{}
+		emptyIndex = index
+	}
+}
+
+// records aren't moved, the index is rebuilt by stored hashes
+func (hashMap *ArenaHashMap) grow() {
+	oldSlots := hashMap.slots
+	hashMap.slots = make([]arenaSlot, len(oldSlots)*2)
+
+	capacity := len(hashMap.slots)
+	for _, slot := range oldSlots {
+		if slot.location == 0 {
+			continue
+		}
+
+		index := hashMap.homeIndex(slot.hash)
+		for hashMap.slots[index].location != 0 {
+			index = (index + 1) % capacity
+		}
+
+		hashMap.slots[index] = slot
+	}
+}
+
+// it returns a location of a record of the size class
+func (hashMap *ArenaHashMap) allocate(class uint) uint64 {
+	if freeList := hashMap.freeLists[class]; len(freeList) != 0 {
+		location := freeList[len(freeList)-1]
+		hashMap.freeLists[class] = freeList[:len(freeList)-1]
+
+		return location
+	}
+
+	recordSize := 1 << class
+	if recordSize > hashMap.config.slabSize {
+		hashMap.slabs = append(hashMap.slabs, make([]byte, recordSize))
+		// the dedicated slab isn't used for next allocations
+		hashMap.slabOffset = hashMap.config.slabSize
+		return encodeArenaLocation(len(hashMap.slabs)-1, 0)
+	}
+
+	// the last slab is either full or dedicated
+	if len(hashMap.slabs) == 0 ||
+		hashMap.slabOffset+recordSize > hashMap.config.slabSize {
+		slab := make([]byte, hashMap.config.slabSize)
+		hashMap.slabs = append(hashMap.slabs, slab)
+		hashMap.slabOffset = 0
+	}
+
+	location := encodeArenaLocation(len(hashMap.slabs)-1, hashMap.slabOffset)
+	hashMap.slabOffset += recordSize
+
+	return location
+}
+
+func (hashMap *ArenaHashMap) free(location uint64) {
+	class := hashMap.recordClass(location)
+	hashMap.freeLists[class] = append(hashMap.freeLists[class], location)
+}
+
+func (hashMap *ArenaHashMap) recordClass(location uint64) uint {
+	key, value := hashMap.record(location)
+	return arenaSizeClass(arenaHeaderSize + len(key) + len(value))
+}
+
+func (hashMap *ArenaHashMap) record(location uint64) (
+	key []byte,
+	value []byte,
+) {
+	slabIndex, offset := decodeArenaLocation(location)
+	slab := hashMap.slabs[slabIndex]
+
+	keyLength := int(binary.LittleEndian.Uint32(slab[offset:]))
+	valueLength := int(binary.LittleEndian.Uint32(slab[offset+4:]))
+	keyStart := offset + arenaHeaderSize
+	valueStart := keyStart + keyLength
+
+	key = slab[keyStart:valueStart:valueStart]
+	value = slab[valueStart : valueStart+valueLength : valueStart+valueLength]
+	return key, value
+}
+
+func (hashMap *ArenaHashMap) writeRecord(
+	location uint64,
+	key []byte,
+	value []byte,
+) {
+	slabIndex, offset := decodeArenaLocation(location)
+	slab := hashMap.slabs[slabIndex]
+
+	binary.LittleEndian.PutUint32(slab[offset:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(slab[offset+4:], uint32(len(value)))
+	keyStart := offset + arenaHeaderSize
+	copy(slab[keyStart:], key)
+	copy(slab[keyStart+len(key):], value)
+}
+
+// it returns a binary logarithm of a record size rounded up to a power of two
+func arenaSizeClass(recordSize int) uint {
+	if recordSize < arenaMinRecordSize {
+		recordSize = arenaMinRecordSize
+	}
+
+	return uint(bits.Len(uint(recordSize - 1)))
+}
+
+func encodeArenaLocation(slabIndex int, offset int) uint64 {
+	return (uint64(slabIndex)<<32 | uint64(offset)) + 1
+}
+
+func decodeArenaLocation(location uint64) (slabIndex int, offset int) {
+	location--
+	return int(location >> 32), int(location & (1<<32 - 1))
+}
@@ -0,0 +1,152 @@
+package hashmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+const sizeForArenaBench = 1e6
+
+func BenchmarkArenaHashMap(benchmark *testing.B) {
+	for _, data := range []struct {
+		name      string
+		prepare   func(size int) *ArenaHashMap
+		benchmark func(size int, hashMap *ArenaHashMap)
+	}{
+		{
+			name: "Get",
+			prepare: func(size int) *ArenaHashMap {
+				hashMap := NewArenaHashMap()
+				for i := 0; i < size; i++ {
+					hashMap.Set(arenaBenchKey(i), arenaBenchKey(i))
+				}
+
+				return hashMap
+			},
+			benchmark: func(size int, hashMap *ArenaHashMap) {
+				hashMap.Get(arenaBenchKey(rand.Intn(size)))
+			},
+		},
+		{
+			name: "Iterate",
+			prepare: func(size int) *ArenaHashMap {
+				hashMap := NewArenaHashMap()
+				for i := 0; i < size; i++ {
+					hashMap.Set(arenaBenchKey(i), arenaBenchKey(i))
+				}
+
+				return hashMap
+			},
+			benchmark: func(size int, hashMap *ArenaHashMap) {
+				hashMap.Iterate(func(key []byte, value []byte) bool { return true })
+			},
+		},
+		{
+			name:    "Set",
+			prepare: func(size int) *ArenaHashMap { return NewArenaHashMap() },
+			benchmark: func(size int, hashMap *ArenaHashMap) {
+				for i := 0; i < size; i++ {
+					hashMap.Set(arenaBenchKey(i), arenaBenchKey(i))
+				}
+			},
+		},
+		{
+			name: "Delete",
+			prepare: func(size int) *ArenaHashMap {
+				hashMap := NewArenaHashMap()
+				for i := 0; i < size; i++ {
+					hashMap.Set(arenaBenchKey(i), arenaBenchKey(i))
+				}
+
+				return hashMap
+			},
+			benchmark: func(size int, hashMap *ArenaHashMap) {
+				hashMap.Delete(arenaBenchKey(rand.Intn(size)))
+			},
+		},
+	} {
+		for size := 10; size <= 1e6; size *= 10 {
+			name := fmt.Sprintf("%s/%d", data.name, size)
+			benchmark.Run(name, func(benchmark *testing.B) {
+				hashMap := data.prepare(size)
+				benchmark.ResetTimer()
+
+				for i := 0; i < benchmark.N; i++ {
+					data.benchmark(size, hashMap)
+				}
+			})
+		}
+	}
+}
+
+// it measures stop-the-world pauses of GC cycles while the map is alive;
+// the pauses are read from the runtime.MemStats structure, so time/op
+// is a duration of a full GC cycle and isn't the pause time
+func BenchmarkArenaHashMap_gcPause(benchmark *testing.B) {
+	for _, data := range []struct {
+		name    string
+		prepare func() interface{}
+	}{
+		{
+			name: "HashMap",
+			prepare: func() interface{} {
+				hashMap := NewHashMap()
+				for i := 0; i < sizeForArenaBench; i++ {
+					hashMap.Set(StringKey(arenaBenchKey(i)), arenaBenchKey(i))
+				}
+
+				return hashMap
+			},
+		},
+		{
+			name: "ArenaHashMap",
+			prepare: func() interface{} {
+				hashMap := NewArenaHashMap()
+				for i := 0; i < sizeForArenaBench; i++ {
+					hashMap.Set(arenaBenchKey(i), arenaBenchKey(i))
+				}
+
+				return hashMap
+			},
+		},
+	} {
+		benchmark.Run(data.name, func(benchmark *testing.B) {
+			hashMap := data.prepare()
+			runtime.GC()
+
+			var statsBefore runtime.MemStats
+			runtime.ReadMemStats(&statsBefore)
+			benchmark.ResetTimer()
+
+			for i := 0; i < benchmark.N; i++ {
+				runtime.GC()
+			}
+
+			benchmark.StopTimer()
+			runtime.KeepAlive(hashMap)
+
+			var statsAfter runtime.MemStats
+			runtime.ReadMemStats(&statsAfter)
+
+			cycles := statsAfter.NumGC - statsBefore.NumGC
+			pauses := statsAfter.PauseTotalNs - statsBefore.PauseTotalNs
+			// custom metrics aren't supported by Go 1.11, so they are logged;
+			// logs of benchmarks are printed regardless of the -v flag
+			benchmark.Logf(
+				"%d GC cycles, %.0f pause-ns/cycle",
+				cycles,
+				float64(pauses)/float64(cycles),
+			)
+		})
+	}
+}
+
+func arenaBenchKey(number int) []byte {
+	var key [8]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(number))
+
+	return key[:]
+}
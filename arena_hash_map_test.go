@@ -0,0 +1,285 @@
+package hashmap
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewArenaHashMap(test *testing.T) {
+	type args struct {
+		options []ArenaOption
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want ArenaConfig
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			want: defaultArenaConfig,
+		},
+		{
+			name: "with the set config",
+			args: args{
+				options: []ArenaOption{
+					WithArenaInitialCapacity(23),
+					WithArenaMaxLoadFactor(0.5),
+					WithSlabSize(42),
+					WithArenaHashSeed(100),
+				},
+			},
+			want: ArenaConfig{
+				initialCapacity: 23,
+				maxLoadFactor:   0.5,
+				slabSize:        42,
+				hashSeed:        100,
+			},
+		},
+		{
+			name: "with the invalid config",
+			args: args{
+				options: []ArenaOption{
+					WithArenaInitialCapacity(0),
+					WithArenaMaxLoadFactor(1),
+					WithSlabSize(0),
+				},
+			},
+			want: ArenaConfig{
+				initialCapacity: 1,
+				maxLoadFactor:   maxValidLoadFactor,
+				slabSize:        1,
+			},
+		},
+		{
+			name: "with the negative config",
+			args: args{
+				options: []ArenaOption{
+					WithArenaInitialCapacity(-23),
+					WithArenaMaxLoadFactor(-0.5),
+					WithSlabSize(-42),
+				},
+			},
+			want: ArenaConfig{
+				initialCapacity: 1,
+				maxLoadFactor:   defaultArenaConfig.maxLoadFactor,
+				slabSize:        1,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewArenaHashMap(data.args.options...)
+
+			assert.Equal(test, data.want, got.config)
+			assert.Len(test, got.slots, data.want.initialCapacity)
+			assert.NotZero(test, got.seed)
+			assert.Equal(test, 0, got.size)
+			assert.Empty(test, got.slabs)
+		})
+	}
+}
+
+func TestArenaHashMap(test *testing.T) {
+	hashMap := NewArenaHashMap()
+	hashMap.Set([]byte("one"), []byte("1"))
+	hashMap.Set([]byte("two"), []byte("2"))
+	hashMap.Set([]byte("one"), []byte("uno"))
+	hashMap.Set([]byte(""), []byte("empty"))
+	hashMap.Delete([]byte("two"))
+	hashMap.Delete([]byte("three"))
+
+	value, ok := hashMap.Get([]byte("one"))
+	assert.Equal(test, []byte("uno"), value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get([]byte(""))
+	assert.Equal(test, []byte("empty"), value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get([]byte("two"))
+	assert.Nil(test, value)
+	assert.False(test, ok)
+
+	assert.Equal(test, 2, hashMap.Size())
+}
+
+func TestNewArenaHashMap_largeSlabSize(test *testing.T) {
+	if bits.UintSize == 32 {
+		test.Skip("a slab size over 4 GiB isn't representable by int")
+	}
+
+	largeSlabSize := uint64(maxArenaSlabSize) << 1
+	hashMap := NewArenaHashMap(WithSlabSize(int(largeSlabSize)))
+
+	assert.Equal(test, uint64(maxArenaSlabSize), uint64(hashMap.config.slabSize))
+}
+
+func TestArenaHashMap_invalidConfig(test *testing.T) {
+	hashMap := NewArenaHashMap(
+		WithArenaInitialCapacity(0),
+		WithArenaMaxLoadFactor(1),
+		WithSlabSize(0),
+	)
+	for i := 0; i < 10; i++ {
+		hashMap.Set([]byte(fmt.Sprint(i)), []byte(fmt.Sprint(i*i)))
+	}
+
+	var count int
+	hashMap.Iterate(func(key []byte, value []byte) bool {
+		count++
+		return true
+	})
+	assert.Equal(test, 10, count)
+
+	got, ok := hashMap.Get([]byte("missing"))
+	assert.Nil(test, got)
+	assert.False(test, ok)
+}
+
+func TestArenaHashMap_Get_copy(test *testing.T) {
+	hashMap := NewArenaHashMap()
+	key, value := []byte("key"), []byte("value")
+	hashMap.Set(key, value)
+	key[0], value[0] = 'K', 'V'
+
+	got, _ := hashMap.Get([]byte("key"))
+	got[1] = 'A'
+
+	got, ok := hashMap.Get([]byte("key"))
+	assert.Equal(test, []byte("value"), got)
+	assert.True(test, ok)
+}
+
+func TestArenaHashMap_Iterate(test *testing.T) {
+	test.Run("with full iteration", func(test *testing.T) {
+		hashMap := NewArenaHashMap()
+		for i := 0; i < 100; i++ {
+			hashMap.Set([]byte(fmt.Sprint(i)), []byte(fmt.Sprint(i*i)))
+		}
+
+		items := make(map[string]string)
+		ok := hashMap.Iterate(func(key []byte, value []byte) bool {
+			items[string(key)] = string(value)
+			return true
+		})
+
+		assert.True(test, ok)
+		assert.Len(test, items, 100)
+		for i := 0; i < 100; i++ {
+			assert.Equal(test, fmt.Sprint(i*i), items[fmt.Sprint(i)])
+		}
+	})
+
+	test.Run("with interrupted iteration", func(test *testing.T) {
+		hashMap := NewArenaHashMap()
+		for i := 0; i < 100; i++ {
+			hashMap.Set([]byte(fmt.Sprint(i)), []byte(fmt.Sprint(i*i)))
+		}
+
+		var count int
+		ok := hashMap.Iterate(func(key []byte, value []byte) bool {
+			count++
+			return count < 23
+		})
+
+		assert.False(test, ok)
+		assert.Equal(test, 23, count)
+	})
+}
+
+func TestArenaHashMap_reuse(test *testing.T) {
+	hashMap := NewArenaHashMap(WithSlabSize(1024))
+	modify := func() {
+		for i := 0; i < 64; i++ {
+			hashMap.Set([]byte{byte(i)}, []byte("value"))
+		}
+		for i := 0; i < 64; i += 2 {
+			hashMap.Delete([]byte{byte(i)})
+		}
+		// a record of another size class replaces the original one
+		for i := 1; i < 64; i += 2 {
+			hashMap.Set([]byte{byte(i)}, bytes.Repeat([]byte("v"), 20))
+		}
+	}
+
+	modify()
+	slabCount := hashMap.SlabCount()
+	for i := 0; i < 10; i++ {
+		modify()
+	}
+
+	assert.Equal(test, 32, hashMap.Size())
+	assert.Equal(test, slabCount, hashMap.SlabCount())
+}
+
+func TestArenaHashMap_dedicatedSlab(test *testing.T) {
+	hashMap := NewArenaHashMap(WithSlabSize(64))
+	hashMap.Set([]byte("small"), []byte("value"))
+	hashMap.Set([]byte("large"), bytes.Repeat([]byte("value"), 100))
+	hashMap.Set([]byte("next"), []byte("value"))
+
+	assert.Equal(test, 3, hashMap.SlabCount())
+
+	value, _ := hashMap.Get([]byte("large"))
+	assert.Equal(test, bytes.Repeat([]byte("value"), 100), value)
+	value, _ = hashMap.Get([]byte("next"))
+	assert.Equal(test, []byte("value"), value)
+}
+
+func TestArenaHashMap_random(test *testing.T) {
+	hashMap := NewArenaHashMap(WithArenaInitialCapacity(2), WithSlabSize(256))
+	expectedItems := make(map[string]string)
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprint(rand.Intn(500))
+		if rand.Intn(3) == 0 {
+			hashMap.Delete([]byte(key))
+			delete(expectedItems, key)
+
+			continue
+		}
+
+		value := fmt.Sprint(rand.Intn(1 << uint(rand.Intn(60))))
+		hashMap.Set([]byte(key), []byte(value))
+		expectedItems[key] = value
+	}
+
+	items := make(map[string]string)
+	hashMap.Iterate(func(key []byte, value []byte) bool {
+		items[string(key)] = string(value)
+		return true
+	})
+
+	assert.Equal(test, len(expectedItems), hashMap.Size())
+	assert.Equal(test, expectedItems, items)
+	for key, value := range expectedItems {
+		got, ok := hashMap.Get([]byte(key))
+		assert.Equal(test, []byte(value), got)
+		assert.True(test, ok)
+	}
+}
+
+func Test_arenaSizeClass(test *testing.T) {
+	for _, data := range []struct {
+		recordSize int
+		want       uint
+	}{
+		{recordSize: 8, want: 4},
+		{recordSize: 16, want: 4},
+		{recordSize: 17, want: 5},
+		{recordSize: 32, want: 5},
+		{recordSize: 1000, want: 10},
+	} {
+		test.Run(fmt.Sprint(data.recordSize), func(test *testing.T) {
+			got := arenaSizeClass(data.recordSize)
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
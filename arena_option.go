@@ -0,0 +1,114 @@
+package hashmap
+
+// ArenaConfig ...
+type ArenaConfig struct {
+	initialCapacity int
+	maxLoadFactor   float64
+	slabSize        int
+	hashSeed        uint64
+}
+
+// an offset in a slab is stored in 32 bits
+// (see the encodeArenaLocation() function)
+const maxArenaSlabSize = 1 << 32
+
+// nolint: gochecknoglobals
+var (
+	defaultArenaConfig = ArenaConfig{
+		initialCapacity: 16,
+		maxLoadFactor:   0.75,
+		slabSize:        1 << 20,
+	}
+)
+
+// ArenaOption ...
+type ArenaOption func(options *ArenaConfig)
+
+// WithArenaInitialCapacity ...
+//
+// It's an initial count of slots of the index, not of slabs. It should
+// be positive, a non-positive one is replaced by 1.
+//
+// Default: 16.
+//
+func WithArenaInitialCapacity(initialCapacity int) ArenaOption {
+	return func(options *ArenaConfig) {
+		options.initialCapacity = initialCapacity
+	}
+}
+
+// WithArenaMaxLoadFactor ...
+//
+// It should be in the (0, 1) range; a factor of 1 or higher is replaced
+// by 0.95, a non-positive one is replaced by the default one.
+//
+// Default: 0.75.
+//
+func WithArenaMaxLoadFactor(maxLoadFactor float64) ArenaOption {
+	return func(options *ArenaConfig) {
+		options.maxLoadFactor = maxLoadFactor
+	}
+}
+
+// WithSlabSize ...
+//
+// Items that don't fit into a slab of this size get dedicated slabs.
+// It should be in the [1 B, 4 GiB] range; a non-positive size is replaced
+// by 1 B, a size over 4 GiB is replaced by 4 GiB (an offset in a slab
+// is stored in 32 bits, see the encodeArenaLocation() function).
+//
+// Default: 1 MiB.
+//
+func WithSlabSize(slabSize int) ArenaOption {
+	return func(options *ArenaConfig) {
+		options.slabSize = slabSize
+	}
+}
+
+// WithArenaHashSeed ...
+//
+// A zero seed means a random one.
+//
+// Default: a random seed that is unique for each map.
+//
+func WithArenaHashSeed(hashSeed uint64) ArenaOption {
+	return func(options *ArenaConfig) {
+		options.hashSeed = hashSeed
+	}
+}
+
+func newArenaConfig(options []ArenaOption) ArenaConfig {
+	config := defaultArenaConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config.normalize()
+}
+
+// it replaces invalid values by the closest valid ones or by default ones
+// if there are no such values (see the Config.normalize() method)
+func (config ArenaConfig) normalize() ArenaConfig {
+	if config.initialCapacity <= 0 {
+		config.initialCapacity = 1
+	}
+
+	switch {
+	case config.maxLoadFactor >= 1:
+		config.maxLoadFactor = maxValidLoadFactor
+	case !(config.maxLoadFactor > 0):
+		config.maxLoadFactor = defaultArenaConfig.maxLoadFactor
+	}
+
+	// the maximal size is converted via a variable, because it overflows int
+	// on 32-bit platforms, where the clamping isn't required
+	maxSlabSize := uint64(maxArenaSlabSize)
+	switch {
+	case config.slabSize <= 0:
+		config.slabSize = 1
+	case uint64(config.slabSize) > maxSlabSize:
+		config.slabSize = int(maxSlabSize)
+	}
+
+	return config
+}
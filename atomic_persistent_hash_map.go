@@ -0,0 +1,111 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicPersistentHashMap ...
+//
+// It adapts the PersistentHashMap structure to the Storage interface.
+// It stores a current version of the map and atomically swaps it
+// on modification.
+//
+// It's safe for concurrent access. Reading doesn't use any lock, and
+// modifications are serialized by a mutex lock.
+//
+type AtomicPersistentHashMap struct {
+	current   atomic.Value // it stores the PersistentHashMap type
+	writeLock sync.Mutex
+}
+
+// NewAtomicPersistentHashMap ...
+func NewAtomicPersistentHashMap() *AtomicPersistentHashMap {
+	hashMap := new(AtomicPersistentHashMap)
+	hashMap.current.Store(PersistentHashMap{})
+
+	return hashMap
+}
+
+// Get ...
+func (hashMap *AtomicPersistentHashMap) Get(key Key) (
+	value interface{},
+	ok bool,
+) {
+	return hashMap.Snapshot().Get(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It iterates over a snapshot, so modifications during iteration
+// don't affect it.
+//
+func (hashMap *AtomicPersistentHashMap) Iterate(handler Handler) bool {
+	return hashMap.Snapshot().Iterate(handler)
+}
+
+// Set ...
+func (hashMap *AtomicPersistentHashMap) Set(key Key, value interface{}) {
+	hashMap.swap(func(current PersistentHashMap) PersistentHashMap {
+		return current.Set(key, value)
+	})
+}
+
+// Delete ...
+func (hashMap *AtomicPersistentHashMap) Delete(key Key) {
+	hashMap.swap(func(current PersistentHashMap) PersistentHashMap {
+		return current.Delete(key)
+	})
+}
+
+// Size ...
+func (hashMap *AtomicPersistentHashMap) Size() int {
+	return hashMap.Snapshot().Size()
+}
+
+// Snapshot ...
+//
+// It returns a current version of the map. It's cheap, because versions
+// are immutable.
+//
+func (hashMap *AtomicPersistentHashMap) Snapshot() PersistentHashMap {
+	return hashMap.current.Load().(PersistentHashMap)
+}
+
+// Restore ...
+//
+// It replaces a current version of the map by the passed one, e.g. by one
+// returned by the Snapshot() method earlier.
+//
+func (hashMap *AtomicPersistentHashMap) Restore(snapshot PersistentHashMap) {
+	hashMap.writeLock.Lock()
+	defer hashMap.writeLock.Unlock()
+
+	hashMap.current.Store(snapshot)
+}
+
+func (hashMap *AtomicPersistentHashMap) update(
+	key Key,
+	handler updateHandler,
+) {
+	hashMap.swap(func(current PersistentHashMap) PersistentHashMap {
+		value, ok := current.Get(key)
+		newValue, keep := handler(value, ok)
+		if keep {
+			return current.Set(key, newValue)
+		}
+
+		return current.Delete(key)
+	})
+}
+
+func (hashMap *AtomicPersistentHashMap) swap(
+	modify func(current PersistentHashMap) PersistentHashMap,
+) {
+	hashMap.writeLock.Lock()
+	defer hashMap.writeLock.Unlock()
+
+	hashMap.current.Store(modify(hashMap.Snapshot()))
+}
@@ -0,0 +1,95 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicPersistentHashMap(test *testing.T) {
+	hashMap := NewAtomicPersistentHashMap()
+	hashMap.Set(IntKey(1), "one")
+	hashMap.Set(IntKey(2), "two")
+	hashMap.Set(IntKey(1), "uno")
+	hashMap.Delete(IntKey(2))
+	hashMap.Delete(IntKey(3))
+
+	value, ok := hashMap.Get(IntKey(1))
+	assert.Equal(test, "uno", value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get(IntKey(2))
+	assert.Nil(test, value)
+	assert.False(test, ok)
+
+	assert.Equal(test, 1, hashMap.Size())
+
+	items := make(map[Key]interface{})
+	ok = hashMap.Iterate(func(key Key, value interface{}) bool {
+		items[key] = value
+		return true
+	})
+	assert.True(test, ok)
+	assert.Equal(test, map[Key]interface{}{IntKey(1): "uno"}, items)
+}
+
+func TestAtomicPersistentHashMap_Restore(test *testing.T) {
+	hashMap := NewAtomicPersistentHashMap()
+	hashMap.Set(IntKey(1), "one")
+
+	snapshot := hashMap.Snapshot()
+	hashMap.Set(IntKey(1), "uno")
+	hashMap.Set(IntKey(2), "two")
+
+	value, _ := snapshot.Get(IntKey(1))
+	assert.Equal(test, "one", value)
+	assert.Equal(test, 1, snapshot.Size())
+
+	hashMap.Restore(snapshot)
+
+	value, _ = hashMap.Get(IntKey(1))
+	assert.Equal(test, "one", value)
+	assert.Equal(test, 1, hashMap.Size())
+}
+
+func TestAtomicPersistentHashMap_concurrency(test *testing.T) {
+	const writerCount = 10
+	const writesPerWriter = 100
+
+	hashMap := NewAtomicPersistentHashMap()
+	hashMap.Set(IntKey(-1), 0)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < writerCount; i++ {
+		waitGroup.Add(2)
+
+		go func(writer int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Set(IntKey(writer*writesPerWriter+j), j)
+				updateStorage(
+					hashMap,
+					IntKey(-1),
+					func(value interface{}, ok bool) (interface{}, bool) {
+						return value.(int) + 1, true
+					},
+				)
+			}
+		}(i)
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Get(IntKey(-1))
+				hashMap.Iterate(func(key Key, value interface{}) bool { return true })
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	counter, _ := hashMap.Get(IntKey(-1))
+	assert.Equal(test, writerCount*writesPerWriter, counter)
+	assert.Equal(test, writerCount*writesPerWriter+1, hashMap.Size())
+}
@@ -0,0 +1,130 @@
+package hashmap
+
+// BiMap ...
+//
+// It's a one-to-one map that allows searching both by keys and by values,
+// so its values should implement the Key interface.
+//
+// It's not safe for concurrent access.
+//
+type BiMap struct {
+	forward  *HashMap
+	backward *HashMap
+}
+
+// NewBiMap ...
+//
+// The options are applied to both inner maps: by keys and by values.
+//
+func NewBiMap(options ...Option) *BiMap {
+	return &BiMap{
+		forward:  NewHashMap(options...),
+		backward: NewHashMap(options...),
+	}
+}
+
+// Get ...
+func (biMap *BiMap) Get(key Key) (value Key, ok bool) {
+	return getBiMapPair(biMap.forward, key)
+}
+
+// GetByValue ...
+func (biMap *BiMap) GetByValue(value Key) (key Key, ok bool) {
+	return getBiMapPair(biMap.backward, value)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// Values passed to the handler implement the Key interface.
+//
+// It randomizes of iteration order.
+//
+func (biMap *BiMap) Iterate(handler Handler) bool {
+	return biMap.forward.Iterate(handler)
+}
+
+// Set ...
+//
+// If the key or the value are already bound, their previous pairs are deleted,
+// so the mapping stays one-to-one.
+//
+func (biMap *BiMap) Set(key Key, value Key) {
+	setBiMapPair(biMap.forwardOf, biMap.backwardOf, key, value)
+}
+
+// Delete ...
+func (biMap *BiMap) Delete(key Key) {
+	deleteBiMapPair(biMap.forwardOf, biMap.backwardOf, key)
+}
+
+// DeleteByValue ...
+func (biMap *BiMap) DeleteByValue(value Key) {
+	deleteBiMapPair(biMap.backwardOf, biMap.forwardOf, value)
+}
+
+// Size ...
+func (biMap *BiMap) Size() int {
+	return biMap.forward.Size()
+}
+
+// Inverse ...
+//
+// It returns a view of the map with keys and values swapped. The view shares
+// data with the original map.
+//
+func (biMap *BiMap) Inverse() *BiMap {
+	return &BiMap{forward: biMap.backward, backward: biMap.forward}
+}
+
+func (biMap *BiMap) forwardOf(key Key) *HashMap {
+	return biMap.forward
+}
+
+func (biMap *BiMap) backwardOf(value Key) *HashMap {
+	return biMap.backward
+}
+
+// it returns an inner map that contains the passed key
+type biMapTableSelector func(key Key) *HashMap
+
+func getBiMapPair(table *HashMap, key Key) (value Key, ok bool) {
+	rawValue, ok := table.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return rawValue.(Key), true
+}
+
+func setBiMapPair(
+	forward biMapTableSelector,
+	backward biMapTableSelector,
+	key Key,
+	value Key,
+) {
+	if oldValue, ok := getBiMapPair(forward(key), key); ok {
+		backward(oldValue).Delete(oldValue)
+	}
+	if oldKey, ok := getBiMapPair(backward(value), value); ok {
+		forward(oldKey).Delete(oldKey)
+	}
+
+	forward(key).Set(key, value)
+	backward(value).Set(value, key)
+}
+
+func deleteBiMapPair(
+	forward biMapTableSelector,
+	backward biMapTableSelector,
+	key Key,
+) {
+	value, ok := getBiMapPair(forward(key), key)
+	if !ok {
+		return
+	}
+
+	forward(key).Delete(key)
+	backward(value).Delete(value)
+}
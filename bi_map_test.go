@@ -0,0 +1,119 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// it's a common interface of all variants of bidirectional maps for tests
+type testBiMap interface {
+	Get(key Key) (value Key, ok bool)
+	GetByValue(value Key) (key Key, ok bool)
+	Iterate(handler Handler) bool
+	Set(key Key, value Key)
+	Delete(key Key)
+	DeleteByValue(value Key)
+	Size() int
+}
+
+func TestBiMap(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		makeBiMap   func() testBiMap
+		makeInverse func(biMap testBiMap) testBiMap
+	}{
+		{
+			name:      "with the plain bidirectional map",
+			makeBiMap: func() testBiMap { return NewBiMap() },
+			makeInverse: func(biMap testBiMap) testBiMap {
+				return biMap.(*BiMap).Inverse()
+			},
+		},
+		{
+			name:      "with the synchronized bidirectional map",
+			makeBiMap: func() testBiMap { return NewSynchronizedBiMap() },
+			makeInverse: func(biMap testBiMap) testBiMap {
+				return biMap.(*SynchronizedBiMap).Inverse()
+			},
+		},
+		{
+			name: "with the concurrent bidirectional map",
+			makeBiMap: func() testBiMap {
+				return NewConcurrentBiMap(WithConcurrencyLevel(4))
+			},
+			makeInverse: func(biMap testBiMap) testBiMap {
+				return biMap.(ConcurrentBiMap).Inverse()
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			biMap := data.makeBiMap()
+			biMap.Set(IntKey(1), StringKey("one"))
+			biMap.Set(IntKey(2), StringKey("two"))
+			biMap.Set(IntKey(3), StringKey("three"))
+			// it rebinds the key, so the old value should be unbound
+			biMap.Set(IntKey(1), StringKey("uno"))
+			// it rebinds the value, so the old key should be unbound
+			biMap.Set(IntKey(4), StringKey("two"))
+
+			inverse := data.makeInverse(biMap)
+			inverse.Set(StringKey("five"), IntKey(5))
+
+			gotValue, gotOk := biMap.Get(IntKey(1))
+			assert.Equal(test, StringKey("uno"), gotValue)
+			assert.True(test, gotOk)
+
+			_, gotOk = biMap.GetByValue(StringKey("one"))
+			assert.False(test, gotOk)
+
+			_, gotOk = biMap.Get(IntKey(2))
+			assert.False(test, gotOk)
+
+			gotKey, gotOk := biMap.GetByValue(StringKey("two"))
+			assert.Equal(test, IntKey(4), gotKey)
+			assert.True(test, gotOk)
+
+			gotValue, gotOk = biMap.Get(IntKey(5))
+			assert.Equal(test, StringKey("five"), gotValue)
+			assert.True(test, gotOk)
+
+			gotKey, gotOk = inverse.Get(StringKey("three"))
+			assert.Equal(test, IntKey(3), gotKey)
+			assert.True(test, gotOk)
+
+			assert.Equal(test, 4, biMap.Size())
+			assert.Equal(test, 4, inverse.Size())
+
+			biMap.Delete(IntKey(3))
+			biMap.DeleteByValue(StringKey("five"))
+			inverse.DeleteByValue(IntKey(4))
+
+			_, gotOk = inverse.Get(StringKey("three"))
+			assert.False(test, gotOk)
+			_, gotOk = biMap.Get(IntKey(5))
+			assert.False(test, gotOk)
+			_, gotOk = biMap.GetByValue(StringKey("two"))
+			assert.False(test, gotOk)
+
+			gotPairs := make(map[Key]Key)
+			biMap.Iterate(func(key Key, value interface{}) bool {
+				gotPairs[key] = value.(Key)
+				return true
+			})
+
+			gotInversePairs := make(map[Key]Key)
+			inverse.Iterate(func(key Key, value interface{}) bool {
+				gotInversePairs[key] = value.(Key)
+				return true
+			})
+
+			assert.Equal(test, map[Key]Key{IntKey(1): StringKey("uno")}, gotPairs)
+			assert.Equal(
+				test,
+				map[Key]Key{StringKey("uno"): IntKey(1)},
+				gotInversePairs,
+			)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package example shows keys generated by the hashmap-keygen command.
+package example
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+//go:generate hashmap-keygen
+
+// Celsius ...
+type Celsius float64
+
+// Label ...
+//
+//hashmap:key
+//
+type Label struct {
+	Name string `hashmap:"nocase"`
+}
+
+// Point ...
+//
+// Points are equal regardless of their cached descriptions and cases
+// of their tags.
+//
+//hashmap:key
+//
+type Point struct {
+	X, Y        int
+	Temperature Celsius
+	Label
+	Tags        [2]string `hashmap:"nocase"`
+	Flags       [2]bool
+	Owner       hashmap.StringKey `hashmap:"key"`
+	description []string          `hashmap:"-"`
+	hits        int               `hashmap:"-"`
+}
+
+// Describe ...
+//
+// It caches the description of the point; the cache doesn't affect
+// equality of points.
+//
+func (point *Point) Describe(description ...string) []string {
+	if description != nil {
+		point.description = description
+		point.hits = 0
+	}
+
+	point.hits++
+	return point.description
+}
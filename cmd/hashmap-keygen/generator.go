@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+const generatedCodeHeader = "// Code generated by hashmap-keygen. DO NOT EDIT."
+
+const hashmapImportPath = "github.com/thewizardplusplus/go-hashmap"
+
+type codeWriter struct {
+	buffer bytes.Buffer
+}
+
+func (writer *codeWriter) printf(format string, arguments ...interface{}) {
+	fmt.Fprintf(&writer.buffer, format, arguments...)
+}
+
+func (writer *codeWriter) source() ([]byte, error) {
+	source, err := format.Source(writer.buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated code: %v", err)
+	}
+
+	return source, nil
+}
+
+// it generates the Hash64(), Hash() and Equals() methods of key types
+func generateKeyMethods(keyPackage keyPackage) ([]byte, error) {
+	var writer codeWriter
+	writer.printf("%s\n\npackage %s\n\n", generatedCodeHeader, keyPackage.name)
+
+	writer.printf("import (\n")
+	if hasCaselessFields(keyPackage) {
+		writer.printf("%q\n\n", "strings")
+	}
+	writer.printf("hashmap %q\n)\n", hashmapImportPath)
+
+	for _, keyType := range keyPackage.types {
+		writeHashMethods(&writer, keyType)
+		writeEqualsMethod(&writer, keyType)
+	}
+
+	return writer.source()
+}
+
+// it generates tests that check that equal keys have equal hashes
+func generateKeyTests(keyPackage keyPackage) ([]byte, error) {
+	var writer codeWriter
+	writer.printf("%s\n\npackage %s\n\n", generatedCodeHeader, keyPackage.name)
+
+	writer.printf("import (\n%q\n", "math/rand")
+	if hasForeignKeyFields(keyPackage) {
+		writer.printf("%q\n", "reflect")
+	}
+	if hasFieldsOfKind(keyPackage, stringFieldKind) {
+		writer.printf("%q\n", "strconv")
+	}
+	if hasCaselessFields(keyPackage) {
+		writer.printf("%q\n", "strings")
+	}
+	writer.printf("%q\n", "testing")
+	if hasForeignKeyFields(keyPackage) {
+		writer.printf("%q\n", "testing/quick")
+	}
+	writer.printf(")\n")
+
+	for _, keyType := range keyPackage.types {
+		writeConsistencyTest(&writer, keyPackage, keyType)
+	}
+
+	return writer.source()
+}
+
+func writeHashMethods(writer *codeWriter, keyType keyType) {
+	writer.printf("\n// Hash64 ...\n")
+	writer.printf("func (key %s) Hash64() uint64 {\n", keyType.name)
+	writer.printf("var hash uint64\n")
+	for _, field := range keyType.fields {
+		if field.skipped {
+			continue
+		}
+
+		writeFieldHashing(writer, field.typ, "key."+field.name, field.caseless, 0)
+	}
+	writer.printf("\nreturn hash\n}\n")
+
+	writer.printf("\n// Hash ...\n")
+	writer.printf("func (key %s) Hash() int {\n", keyType.name)
+	writer.printf("return int(uint(key.Hash64()) >> 1)\n}\n")
+}
+
+func writeFieldHashing(
+	writer *codeWriter,
+	typ fieldType,
+	value string,
+	caseless bool,
+	depth int,
+) {
+	if typ.kind == arrayFieldKind {
+		item := fmt.Sprintf("item%d", depth)
+		writer.printf("for _, %s := range %s {\n", item, value)
+		writeFieldHashing(writer, *typ.element, item, caseless, depth+1)
+		writer.printf("}\n")
+
+		return
+	}
+
+	writer.printf(
+		"hash = hashmap.CombineHashes(hash, %s)\n",
+		hashExpression(typ, value, caseless),
+	)
+}
+
+func hashExpression(typ fieldType, value string, caseless bool) string {
+	switch typ.kind {
+	case stringFieldKind:
+		if caseless {
+			return "hashmap.HashStringFold(" + convert(typ, "string", value) + ")"
+		}
+
+		return "hashmap.HashString(" + convert(typ, "string", value) + ")"
+	case boolFieldKind:
+		return "hashmap.HashBool(" + convert(typ, "bool", value) + ")"
+	case integerFieldKind:
+		return "hashmap.HashUint64(" + convert(typ, "uint64", value) + ")"
+	case floatFieldKind:
+		return "hashmap.HashFloat64(" + convert(typ, "float64", value) + ")"
+	case complexFieldKind:
+		return "hashmap.HashComplex128(" + convert(typ, "complex128", value) + ")"
+	default:
+		return "hashmap.HashKey(" + value + ")"
+	}
+}
+
+func writeEqualsMethod(writer *codeWriter, keyType keyType) {
+	writer.printf("\n// Equals ...\n")
+	writer.printf(
+		"func (key %s) Equals(other hashmap.Key) bool {\n",
+		keyType.name,
+	)
+
+	var comparedFields []keyField
+	for _, field := range keyType.fields {
+		if !field.skipped {
+			comparedFields = append(comparedFields, field)
+		}
+	}
+	if len(comparedFields) == 0 {
+		writer.printf("_ = other.(%s)\n\nreturn true\n}\n", keyType.name)
+		return
+	}
+
+	writer.printf("otherKey := other.(%s)\n", keyType.name)
+	for _, field := range comparedFields {
+		writeFieldComparison(
+			writer,
+			field.typ,
+			"key."+field.name,
+			"otherKey."+field.name,
+			field.caseless,
+			0,
+		)
+	}
+	writer.printf("\nreturn true\n}\n")
+}
+
+func writeFieldComparison(
+	writer *codeWriter,
+	typ fieldType,
+	value string,
+	otherValue string,
+	caseless bool,
+	depth int,
+) {
+	switch {
+	case typ.kind == arrayFieldKind &&
+		(caseless || leafType(typ).kind == keyFieldKind):
+		index := fmt.Sprintf("index%d", depth)
+		writer.printf("for %s := range %s {\n", index, value)
+		writeFieldComparison(
+			writer,
+			*typ.element,
+			value+"["+index+"]",
+			otherValue+"["+index+"]",
+			caseless,
+			depth+1,
+		)
+		writer.printf("}\n")
+	case typ.kind == keyFieldKind:
+		writer.printf("if !%s.Equals(%s) {\nreturn false\n}\n", value, otherValue)
+	case caseless:
+		writer.printf(
+			"if !strings.EqualFold(%s, %s) {\nreturn false\n}\n",
+			convert(typ, "string", value),
+			convert(typ, "string", otherValue),
+		)
+	// values of basic types and arrays of them are compared by the == operator
+	default:
+		writer.printf("if %s != %s {\nreturn false\n}\n", value, otherValue)
+	}
+}
+
+func writeConsistencyTest(
+	writer *codeWriter,
+	keyPackage keyPackage,
+	keyType keyType,
+) {
+	writer.printf(
+		"\nfunc Test%s_hashConsistency(test *testing.T) {\n",
+		strings.ToUpper(keyType.name[:1])+keyType.name[1:],
+	)
+	writer.printf("random := rand.New(rand.NewSource(1))\n")
+	writer.printf("for i := 0; i < 100; i++ {\n")
+	writer.printf("var one, two %s\n", keyType.name)
+	for _, field := range keyType.fields {
+		if !field.resolved {
+			continue
+		}
+
+		writeFieldFilling(
+			writer,
+			keyPackage,
+			field,
+			"one."+field.name,
+			"two."+field.name,
+			0,
+		)
+	}
+
+	writer.printf(`
+		if !one.Equals(one) {
+			test.Fatalf("%%+v isn't equal to itself", one)
+		}
+		if !one.Equals(two) || !two.Equals(one) {
+			test.Fatalf("%%+v and %%+v aren't equal", one, two)
+		}
+		if one.Hash64() != two.Hash64() || one.Hash() != two.Hash() {
+			test.Fatalf("hashes of equal %%+v and %%+v differ", one, two)
+		}
+	}
+}
+`)
+}
+
+// it fills the field of the first key by a random value and the same field
+// of the second key by an equal one; skipped fields are filled independently
+func writeFieldFilling(
+	writer *codeWriter,
+	keyPackage keyPackage,
+	field keyField,
+	value string,
+	otherValue string,
+	depth int,
+) {
+	switch field.typ.kind {
+	case arrayFieldKind:
+		index := fmt.Sprintf("index%d", depth)
+		writer.printf("for %s := range %s {\n", index, value)
+
+		elementField := field
+		elementField.typ = *field.typ.element
+		writeFieldFilling(
+			writer,
+			keyPackage,
+			elementField,
+			value+"["+index+"]",
+			otherValue+"["+index+"]",
+			depth+1,
+		)
+		writer.printf("}\n")
+
+		return
+	case keyFieldKind:
+		writeKeyFieldFilling(writer, keyPackage, field, value, otherValue, depth)
+		return
+	}
+
+	writer.printf("%s = %s\n", value, randomValueExpression(field.typ))
+	switch {
+	case field.skipped:
+		writer.printf("%s = %s\n", otherValue, randomValueExpression(field.typ))
+	case field.caseless:
+		upperValue := "strings.ToUpper(" + convert(field.typ, "string", value) + ")"
+		writer.printf(
+			"%s = %s\n",
+			otherValue,
+			convertFrom("string", field.typ, upperValue),
+		)
+	default:
+		writer.printf("%s = %s\n", otherValue, value)
+	}
+}
+
+// fields of key types generated together with the key are filled
+// recursively, fields of other key types are filled via the testing/quick
+// package
+func writeKeyFieldFilling(
+	writer *codeWriter,
+	keyPackage keyPackage,
+	field keyField,
+	value string,
+	otherValue string,
+	depth int,
+) {
+	if keyType, ok := findKeyType(keyPackage, field.typ.name); ok {
+		for _, subfield := range keyType.fields {
+			if !subfield.resolved {
+				continue
+			}
+
+			// subfields of a skipped field are skipped too
+			subfield.skipped = subfield.skipped || field.skipped
+			writeFieldFilling(
+				writer,
+				keyPackage,
+				subfield,
+				value+"."+subfield.name,
+				otherValue+"."+subfield.name,
+				depth,
+			)
+		}
+
+		return
+	}
+
+	writeRandomKeyFilling(writer, value)
+	if field.skipped {
+		writeRandomKeyFilling(writer, otherValue)
+	} else {
+		writer.printf("%s = %s\n", otherValue, value)
+	}
+}
+
+func writeRandomKeyFilling(writer *codeWriter, value string) {
+	writer.printf(`func() {
+			// the value is left zero if the testing/quick package doesn't support
+			// its type (e.g. a struct with unexported fields)
+			defer func() { recover() }()
+			if value, ok := quick.Value(reflect.TypeOf(%[1]s), random); ok {
+				reflect.ValueOf(&%[1]s).Elem().Set(value)
+			}
+		}()
+`, value)
+}
+
+func randomValueExpression(typ fieldType) string {
+	switch typ.kind {
+	case stringFieldKind:
+		value := "strconv.FormatUint(random.Uint64(), 36)"
+		return convertFrom("string", typ, value)
+	case boolFieldKind:
+		return convertFrom("bool", typ, "random.Intn(2) == 1")
+	case integerFieldKind:
+		return convertFrom("uint64", typ, "random.Uint64()")
+	case floatFieldKind:
+		return convertFrom("float64", typ, "random.NormFloat64()")
+	default:
+		value := "complex(random.NormFloat64(), random.NormFloat64())"
+		return convertFrom("complex128", typ, value)
+	}
+}
+
+// it converts the value of the field type to the target type
+// if they differ
+func convert(typ fieldType, targetType string, value string) string {
+	if typ.name == targetType {
+		return value
+	}
+
+	return targetType + "(" + value + ")"
+}
+
+// it converts the value of the source type to the field type if they differ
+func convertFrom(sourceType string, typ fieldType, value string) string {
+	if typ.name == sourceType {
+		return value
+	}
+
+	return typ.name + "(" + value + ")"
+}
+
+func findKeyType(keyPackage keyPackage, name string) (keyType, bool) {
+	for _, keyType := range keyPackage.types {
+		if keyType.name == name {
+			return keyType, true
+		}
+	}
+
+	return keyType{}, false
+}
+
+// it checks for fields of key types that aren't generated together
+// with the keys, so they are filled via the testing/quick package in tests
+func hasForeignKeyFields(keyPackage keyPackage) bool {
+	for _, keyType := range keyPackage.types {
+		for _, field := range keyType.fields {
+			if !field.resolved {
+				continue
+			}
+
+			typ := leafType(field.typ)
+			if typ.kind != keyFieldKind {
+				continue
+			}
+			if _, ok := findKeyType(keyPackage, typ.name); !ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasCaselessFields(keyPackage keyPackage) bool {
+	for _, keyType := range keyPackage.types {
+		for _, field := range keyType.fields {
+			if field.caseless && field.resolved && !field.skipped {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasFieldsOfKind(keyPackage keyPackage, kind fieldKind) bool {
+	for _, keyType := range keyPackage.types {
+		for _, field := range keyType.fields {
+			if field.resolved && leafType(field.typ).kind == kind {
+				return true
+			}
+		}
+	}
+
+	return false
+}
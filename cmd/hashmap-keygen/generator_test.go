@@ -0,0 +1,205 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_example(test *testing.T) {
+	directory, removeDirectory := makeTempDir(test)
+	defer removeDirectory()
+
+	output := filepath.Join(directory, "point_key.go")
+	err := generate(generationOptions{
+		filename:  filepath.Join("example", "point.go"),
+		output:    output,
+		withTests: true,
+	})
+	require.NoError(test, err)
+
+	// the generated files of the example should be up to date
+	for _, filenames := range [][2]string{
+		{filepath.Join("example", "point_key.go"), output},
+		{
+			filepath.Join("example", "point_key_test.go"),
+			filepath.Join(filepath.Dir(output), "point_key_test.go"),
+		},
+	} {
+		want, err := ioutil.ReadFile(filenames[0])
+		require.NoError(test, err)
+
+		got, err := ioutil.ReadFile(filenames[1])
+		require.NoError(test, err)
+
+		assert.Equal(test, string(want), string(got), filenames[0])
+	}
+}
+
+func TestGenerate_withoutTests(test *testing.T) {
+	directory, removeDirectory := makeTempDir(test)
+	defer removeDirectory()
+
+	filename := writeSource(test, directory, `package keys
+
+//hashmap:key
+type Key struct {
+	ID int
+}
+`)
+	err := generate(generationOptions{filename: filename, withTests: false})
+	require.NoError(test, err)
+
+	assert.FileExists(test, filepath.Join(directory, "keys_key.go"))
+
+	_, err = os.Stat(filepath.Join(directory, "keys_key_test.go"))
+	assert.True(test, os.IsNotExist(err))
+}
+
+func Test_generateKeyMethods(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		keyPackage keyPackage
+		want       string
+	}{
+		{
+			name: "with named and array fields",
+			keyPackage: keyPackage{
+				name: "keys",
+				types: []keyType{
+					{
+						name: "Key",
+						fields: []keyField{
+							{
+								name:     "ID",
+								typ:      fieldType{kind: integerFieldKind, name: "uint64"},
+								resolved: true,
+							},
+							{
+								name: "Names",
+								typ: fieldType{
+									kind:    arrayFieldKind,
+									name:    "[2]Name",
+									element: &fieldType{kind: stringFieldKind, name: "Name"},
+								},
+								resolved: true,
+							},
+							{
+								name:     "cache",
+								typ:      fieldType{kind: integerFieldKind, name: "int"},
+								skipped:  true,
+								resolved: true,
+							},
+						},
+					},
+				},
+			},
+			want: `// Code generated by hashmap-keygen. DO NOT EDIT.
+
+package keys
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// Hash64 ...
+func (key Key) Hash64() uint64 {
+	var hash uint64
+	hash = hashmap.CombineHashes(hash, hashmap.HashUint64(key.ID))
+	for _, item0 := range key.Names {
+		hash = hashmap.CombineHashes(hash, hashmap.HashString(string(item0)))
+	}
+
+	return hash
+}
+
+// Hash ...
+func (key Key) Hash() int {
+	return int(uint(key.Hash64()) >> 1)
+}
+
+// Equals ...
+func (key Key) Equals(other hashmap.Key) bool {
+	otherKey := other.(Key)
+	if key.ID != otherKey.ID {
+		return false
+	}
+	if key.Names != otherKey.Names {
+		return false
+	}
+
+	return true
+}
+`,
+		},
+		{
+			name: "with only skipped fields",
+			keyPackage: keyPackage{
+				name: "keys",
+				types: []keyType{
+					{
+						name: "Key",
+						fields: []keyField{
+							{name: "cache", skipped: true, caseless: true},
+						},
+					},
+				},
+			},
+			want: `// Code generated by hashmap-keygen. DO NOT EDIT.
+
+package keys
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// Hash64 ...
+func (key Key) Hash64() uint64 {
+	var hash uint64
+
+	return hash
+}
+
+// Hash ...
+func (key Key) Hash() int {
+	return int(uint(key.Hash64()) >> 1)
+}
+
+// Equals ...
+func (key Key) Equals(other hashmap.Key) bool {
+	_ = other.(Key)
+
+	return true
+}
+`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := generateKeyMethods(data.keyPackage)
+
+			assert.Equal(test, data.want, string(got))
+			assert.NoError(test, err)
+		})
+	}
+}
+
+// it replaces the testing.T.TempDir() method, which isn't available before
+// Go 1.15; the directory should be removed via the returned function
+func makeTempDir(test *testing.T) (directory string, remove func()) {
+	directory, err := ioutil.TempDir("", "hashmap-keygen")
+	require.NoError(test, err)
+
+	return directory, func() { os.RemoveAll(directory) } // nolint: errcheck
+}
+
+func writeSource(test *testing.T, directory string, source string) string {
+	filename := filepath.Join(directory, "keys.go")
+	err := ioutil.WriteFile(filename, []byte(source), 0644)
+	require.NoError(test, err)
+
+	return filename
+}
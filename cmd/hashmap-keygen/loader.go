@@ -0,0 +1,432 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// it's a directive comment that marks a struct type as a key
+// in its documentation
+const keyAnnotation = "//hashmap:key"
+
+// it's a name of a tag of struct fields; its options are separated by commas
+const fieldTagName = "hashmap"
+
+const (
+	skipTagOption     = "-"
+	nocaseTagOption   = "nocase"
+	keyFieldTagOption = "key"
+)
+
+type fieldKind int
+
+const (
+	stringFieldKind fieldKind = iota
+	boolFieldKind
+	integerFieldKind
+	floatFieldKind
+	complexFieldKind
+	keyFieldKind
+	arrayFieldKind
+)
+
+// nolint: gochecknoglobals
+var basicTypeKinds = map[string]fieldKind{
+	"string":     stringFieldKind,
+	"bool":       boolFieldKind,
+	"int":        integerFieldKind,
+	"int8":       integerFieldKind,
+	"int16":      integerFieldKind,
+	"int32":      integerFieldKind,
+	"int64":      integerFieldKind,
+	"uint":       integerFieldKind,
+	"uint8":      integerFieldKind,
+	"uint16":     integerFieldKind,
+	"uint32":     integerFieldKind,
+	"uint64":     integerFieldKind,
+	"uintptr":    integerFieldKind,
+	"byte":       integerFieldKind,
+	"rune":       integerFieldKind,
+	"float32":    floatFieldKind,
+	"float64":    floatFieldKind,
+	"complex64":  complexFieldKind,
+	"complex128": complexFieldKind,
+}
+
+type fieldType struct {
+	kind fieldKind
+	// it's the type as it's written in the source
+	name string
+	// it's set only for arrays
+	element *fieldType
+}
+
+type keyField struct {
+	name     string
+	typ      fieldType
+	skipped  bool
+	caseless bool
+	// it's false for skipped fields of unsupported types
+	resolved bool
+}
+
+type keyType struct {
+	name   string
+	fields []keyField
+}
+
+type keyPackage struct {
+	name  string
+	types []keyType
+}
+
+type packageScope struct {
+	typeSpecs   map[string]*ast.TypeSpec
+	annotated   map[string]bool
+	keyMethods  map[string]map[string]bool
+	targetSpecs []*ast.TypeSpec
+}
+
+// it loads key types from the file; other files of the same directory
+// are used to resolve types of fields
+func loadKeyPackage(filename string, typeNames []string) (keyPackage, error) {
+	fileSet := token.NewFileSet()
+	packages, err := parser.ParseDir(
+		fileSet,
+		filepath.Dir(filename),
+		func(info os.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		},
+		parser.ParseComments,
+	)
+	if err != nil {
+		return keyPackage{}, fmt.Errorf("unable to parse the package: %v", err)
+	}
+
+	absoluteFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return keyPackage{}, fmt.Errorf("unable to resolve the file: %v", err)
+	}
+
+	for packageName, astPackage := range packages {
+		for astFilename, file := range astPackage.Files {
+			absoluteASTFilename, err := filepath.Abs(astFilename)
+			if err != nil || absoluteASTFilename != absoluteFilename {
+				continue
+			}
+
+			scope := newPackageScope(astPackage, file)
+			keyTypes, err := scope.loadKeyTypes(typeNames)
+			if err != nil {
+				return keyPackage{}, err
+			}
+
+			return keyPackage{name: packageName, types: keyTypes}, nil
+		}
+	}
+
+	return keyPackage{}, fmt.Errorf("the file %s isn't found", filename)
+}
+
+func newPackageScope(
+	astPackage *ast.Package,
+	targetFile *ast.File,
+) packageScope {
+	scope := packageScope{
+		typeSpecs:  make(map[string]*ast.TypeSpec),
+		annotated:  make(map[string]bool),
+		keyMethods: make(map[string]map[string]bool),
+	}
+	for _, file := range astPackage.Files {
+		for _, declaration := range file.Decls {
+			switch declaration := declaration.(type) {
+			case *ast.GenDecl:
+				if declaration.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range declaration.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					scope.typeSpecs[typeSpec.Name.Name] = typeSpec
+					if !isAnnotated(declaration, typeSpec) {
+						continue
+					}
+
+					scope.annotated[typeSpec.Name.Name] = true
+					if file == targetFile {
+						scope.targetSpecs = append(scope.targetSpecs, typeSpec)
+					}
+				}
+			case *ast.FuncDecl:
+				scope.registerMethod(declaration)
+			}
+		}
+	}
+
+	return scope
+}
+
+func (scope packageScope) loadKeyTypes(typeNames []string) ([]keyType, error) {
+	targetSpecs := scope.targetSpecs
+	if len(typeNames) != 0 {
+		targetSpecs = nil
+		for _, typeName := range typeNames {
+			typeSpec, ok := scope.typeSpecs[typeName]
+			if !ok {
+				return nil, fmt.Errorf("the type %s isn't found", typeName)
+			}
+
+			// explicitly specified types will get methods too
+			scope.annotated[typeName] = true
+			targetSpecs = append(targetSpecs, typeSpec)
+		}
+	}
+	if len(targetSpecs) == 0 {
+		return nil, errors.New("there are no key types")
+	}
+
+	var keyTypes []keyType
+	for _, typeSpec := range targetSpecs {
+		keyType, err := scope.loadKeyType(typeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("the type %s: %v", typeSpec.Name.Name, err)
+		}
+
+		keyTypes = append(keyTypes, keyType)
+	}
+
+	return keyTypes, nil
+}
+
+func (scope packageScope) loadKeyType(typeSpec *ast.TypeSpec) (keyType, error) {
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return keyType{}, errors.New("it isn't a struct")
+	}
+
+	keyType := keyType{name: typeSpec.Name.Name}
+	for _, field := range structType.Fields.List {
+		options, err := parseFieldTag(field.Tag)
+		if err != nil {
+			return keyType, err
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			// it's an embedded field
+			names = []*ast.Ident{ast.NewIdent(embeddedFieldName(field.Type))}
+		}
+
+		for _, name := range names {
+			// blank fields are ignored by the == operator
+			if name.Name == "_" {
+				continue
+			}
+
+			keyField, err := scope.loadKeyField(name.Name, field.Type, options)
+			if err != nil {
+				return keyType, fmt.Errorf("the field %s: %v", name.Name, err)
+			}
+
+			keyType.fields = append(keyType.fields, keyField)
+		}
+	}
+
+	return keyType, nil
+}
+
+func (scope packageScope) loadKeyField(
+	name string,
+	typeExpression ast.Expr,
+	options map[string]bool,
+) (keyField, error) {
+	keyField := keyField{
+		name:     name,
+		skipped:  options[skipTagOption],
+		caseless: options[nocaseTagOption],
+	}
+
+	typ, err := scope.resolveType(typeExpression, options[keyFieldTagOption])
+	if err != nil {
+		// types of skipped fields don't matter
+		if keyField.skipped {
+			return keyField, nil
+		}
+
+		return keyField, err
+	}
+
+	keyField.typ, keyField.resolved = typ, true
+	if keyField.caseless && leafType(typ).kind != stringFieldKind {
+		return keyField, fmt.Errorf(
+			"the %s option requires a string",
+			nocaseTagOption,
+		)
+	}
+
+	return keyField, nil
+}
+
+func (scope packageScope) resolveType(
+	typeExpression ast.Expr,
+	isKey bool,
+) (fieldType, error) {
+	typeName := types.ExprString(typeExpression)
+	if isKey {
+		return fieldType{kind: keyFieldKind, name: typeName}, nil
+	}
+
+	switch typeExpression := typeExpression.(type) {
+	case *ast.Ident:
+		if kind, ok := basicTypeKinds[typeExpression.Name]; ok {
+			return fieldType{kind: kind, name: typeName}, nil
+		}
+		if scope.isKey(typeExpression.Name) {
+			return fieldType{kind: keyFieldKind, name: typeName}, nil
+		}
+
+		typeSpec, ok := scope.typeSpecs[typeExpression.Name]
+		if !ok {
+			return fieldType{}, fmt.Errorf("the type %s isn't supported", typeName)
+		}
+		if _, ok := typeSpec.Type.(*ast.StructType); ok {
+			return fieldType{}, fmt.Errorf(
+				"the type %s isn't a key (annotate it or implement the key interface)",
+				typeName,
+			)
+		}
+
+		underlyingType, err := scope.resolveType(typeSpec.Type, false)
+		if err != nil {
+			return fieldType{}, err
+		}
+
+		underlyingType.name = typeName
+		return underlyingType, nil
+	case *ast.SelectorExpr:
+		return fieldType{}, fmt.Errorf(
+			"the type %s of another package should be marked by the %s option",
+			typeName,
+			keyFieldTagOption,
+		)
+	case *ast.ArrayType:
+		if typeExpression.Len == nil {
+			return fieldType{}, fmt.Errorf("the type %s isn't comparable", typeName)
+		}
+
+		elementType, err := scope.resolveType(typeExpression.Elt, false)
+		if err != nil {
+			return fieldType{}, err
+		}
+
+		return fieldType{
+			kind:    arrayFieldKind,
+			name:    typeName,
+			element: &elementType,
+		}, nil
+	case *ast.ParenExpr:
+		return scope.resolveType(typeExpression.X, false)
+	default:
+		return fieldType{}, fmt.Errorf("the type %s isn't supported", typeName)
+	}
+}
+
+func (scope packageScope) isKey(typeName string) bool {
+	methods := scope.keyMethods[typeName]
+	return scope.annotated[typeName] || (methods["Hash"] && methods["Equals"])
+}
+
+func (scope packageScope) registerMethod(declaration *ast.FuncDecl) {
+	if declaration.Recv == nil || len(declaration.Recv.List) == 0 {
+		return
+	}
+
+	receiverType := declaration.Recv.List[0].Type
+	if starExpression, ok := receiverType.(*ast.StarExpr); ok {
+		receiverType = starExpression.X
+	}
+
+	receiverIdent, ok := receiverType.(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	methods, ok := scope.keyMethods[receiverIdent.Name]
+	if !ok {
+		methods = make(map[string]bool)
+		scope.keyMethods[receiverIdent.Name] = methods
+	}
+
+	methods[declaration.Name.Name] = true
+}
+
+func isAnnotated(declaration *ast.GenDecl, typeSpec *ast.TypeSpec) bool {
+	for _, comments := range []*ast.CommentGroup{declaration.Doc, typeSpec.Doc} {
+		if comments == nil {
+			continue
+		}
+
+		for _, comment := range comments.List {
+			if strings.TrimSpace(comment.Text) == keyAnnotation {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func parseFieldTag(tag *ast.BasicLit) (map[string]bool, error) {
+	options := make(map[string]bool)
+	if tag == nil {
+		return options, nil
+	}
+
+	tagValue, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unquote the tag: %v", err)
+	}
+
+	for _, option := range strings.Split(
+		reflect.StructTag(tagValue).Get(fieldTagName),
+		",",
+	) {
+		switch option {
+		case "":
+		case skipTagOption, nocaseTagOption, keyFieldTagOption:
+			options[option] = true
+		default:
+			return nil, fmt.Errorf("unknown tag option %q", option)
+		}
+	}
+
+	return options, nil
+}
+
+func embeddedFieldName(typeExpression ast.Expr) string {
+	switch typeExpression := typeExpression.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(typeExpression.X)
+	case *ast.SelectorExpr:
+		return typeExpression.Sel.Name
+	default:
+		return types.ExprString(typeExpression)
+	}
+}
+
+func leafType(typ fieldType) fieldType {
+	for typ.kind == arrayFieldKind {
+		typ = *typ.element
+	}
+
+	return typ
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadKeyPackage(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		source    string
+		typeNames []string
+		want      keyPackage
+		wantErr   string
+	}{
+		{
+			name: "with an annotated type",
+			source: `package keys
+
+type Name string
+
+type Other struct {
+	ID int
+}
+
+//hashmap:key
+type Key struct {
+	ID, Count int
+	Name      Name ` + "`hashmap:\"nocase\"`" + `
+	Tags      [2]string
+	_         int
+	cache     map[string]int ` + "`hashmap:\"-\"`" + `
+}
+`,
+			want: keyPackage{
+				name: "keys",
+				types: []keyType{
+					{
+						name: "Key",
+						fields: []keyField{
+							{
+								name:     "ID",
+								typ:      fieldType{kind: integerFieldKind, name: "int"},
+								resolved: true,
+							},
+							{
+								name:     "Count",
+								typ:      fieldType{kind: integerFieldKind, name: "int"},
+								resolved: true,
+							},
+							{
+								name:     "Name",
+								typ:      fieldType{kind: stringFieldKind, name: "Name"},
+								caseless: true,
+								resolved: true,
+							},
+							{
+								name: "Tags",
+								typ: fieldType{
+									kind:    arrayFieldKind,
+									name:    "[2]string",
+									element: &fieldType{kind: stringFieldKind, name: "string"},
+								},
+								resolved: true,
+							},
+							{name: "cache", skipped: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "with key fields",
+			source: `package keys
+
+import "time"
+
+type Inner struct{}
+
+func (inner Inner) Hash() int { return 0 }
+
+func (inner *Inner) Equals(other interface{}) bool { return true }
+
+//hashmap:key
+type Key struct {
+	Inner
+	Time time.Time ` + "`hashmap:\"key\"`" + `
+}
+`,
+			want: keyPackage{
+				name: "keys",
+				types: []keyType{
+					{
+						name: "Key",
+						fields: []keyField{
+							{
+								name:     "Inner",
+								typ:      fieldType{kind: keyFieldKind, name: "Inner"},
+								resolved: true,
+							},
+							{
+								name:     "Time",
+								typ:      fieldType{kind: keyFieldKind, name: "time.Time"},
+								resolved: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "with an explicitly specified type",
+			source: `package keys
+
+type Key struct {
+	ID bool
+}
+`,
+			typeNames: []string{"Key"},
+			want: keyPackage{
+				name: "keys",
+				types: []keyType{
+					{
+						name: "Key",
+						fields: []keyField{
+							{
+								name:     "ID",
+								typ:      fieldType{kind: boolFieldKind, name: "bool"},
+								resolved: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "without key types",
+			source: `package keys
+
+type Key struct {
+	ID int
+}
+`,
+			wantErr: "there are no key types",
+		},
+		{
+			name: "with an unknown type",
+			source: `package keys
+
+type Key struct {
+	ID int
+}
+`,
+			typeNames: []string{"Unknown"},
+			wantErr:   "the type Unknown isn't found",
+		},
+		{
+			name: "with a not struct type",
+			source: `package keys
+
+//hashmap:key
+type Key int
+`,
+			wantErr: "the type Key: it isn't a struct",
+		},
+		{
+			name: "with a slice field",
+			source: `package keys
+
+//hashmap:key
+type Key struct {
+	IDs []int
+}
+`,
+			wantErr: "the type Key: the field IDs: the type []int isn't comparable",
+		},
+		{
+			name: "with a field of a struct type that isn't a key",
+			source: `package keys
+
+type Other struct{}
+
+//hashmap:key
+type Key struct {
+	Other Other
+}
+`,
+			wantErr: "the type Key: the field Other: the type Other isn't a key " +
+				"(annotate it or implement the key interface)",
+		},
+		{
+			name: "with a field of a type of another package",
+			source: `package keys
+
+import "time"
+
+//hashmap:key
+type Key struct {
+	Time time.Time
+}
+`,
+			wantErr: "the type Key: the field Time: the type time.Time " +
+				"of another package should be marked by the key option",
+		},
+		{
+			name: "with the nocase option of a not string field",
+			source: `package keys
+
+//hashmap:key
+type Key struct {
+	ID int ` + "`hashmap:\"nocase\"`" + `
+}
+`,
+			wantErr: "the type Key: the field ID: the nocase option requires a string",
+		},
+		{
+			name: "with an unknown tag option",
+			source: `package keys
+
+//hashmap:key
+type Key struct {
+	ID int ` + "`hashmap:\"unknown\"`" + `
+}
+`,
+			wantErr: `the type Key: unknown tag option "unknown"`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			directory, removeDirectory := makeTempDir(test)
+			defer removeDirectory()
+
+			filename := writeSource(test, directory, data.source)
+			got, err := loadKeyPackage(filename, data.typeNames)
+
+			if data.wantErr != "" {
+				assert.EqualError(test, err, data.wantErr)
+				return
+			}
+
+			require.NoError(test, err)
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Command hashmap-keygen generates implementations of the hashmap.Key
+// interface for struct types.
+//
+// It's intended to be used via the go:generate directive:
+//
+//	//go:generate hashmap-keygen
+//
+// It processes struct types of the file marked by the following comment
+// in their documentation (or the types specified by the -type flag):
+//
+//	//hashmap:key
+//
+// It generates the Hash64(), Hash() and Equals() methods of each type,
+// which combine hashes of fields and compare fields respectively, and tests
+// that check the consistency of these methods. Fields are configured
+// by the "hashmap" tag with the following options separated by commas:
+//
+//	nocase  the string field is compared case-insensitively;
+//	key     the field implements the hashmap.Key interface (it's required
+//	        for types of other packages);
+//	-       the field is skipped.
+//
+// Supported types of fields are basic types, arrays of supported types
+// and types that implement the hashmap.Key interface.
+//
+// The tests fill fields of key types generated in the same file recursively
+// and fields of other key types via the testing/quick package.
+//
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("hashmap-keygen: ")
+
+	typeNames := flag.String(
+		"type",
+		"",
+		"comma-separated list of type names (default: annotated types)",
+	)
+	output := flag.String(
+		"output",
+		"",
+		"output file name (default: <file>_key.go)",
+	)
+	withTests := flag.Bool("tests", true, "generate tests")
+	flag.Usage = func() {
+		fmt.Fprintln(
+			flag.CommandLine.Output(),
+			"Usage: hashmap-keygen [flags] [file]",
+		)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	filename := flag.Arg(0)
+	if filename == "" {
+		// it's set by the go generate command
+		filename = os.Getenv("GOFILE")
+	}
+	if filename == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := generationOptions{
+		filename:  filename,
+		output:    *output,
+		withTests: *withTests,
+	}
+	if *typeNames != "" {
+		options.typeNames = strings.Split(*typeNames, ",")
+	}
+	if err := generate(options); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generationOptions struct {
+	filename  string
+	typeNames []string
+	output    string
+	withTests bool
+}
+
+func generate(options generationOptions) error {
+	keyPackage, err := loadKeyPackage(options.filename, options.typeNames)
+	if err != nil {
+		return err
+	}
+
+	output := options.output
+	if output == "" {
+		output = strings.TrimSuffix(options.filename, ".go") + "_key.go"
+	}
+
+	methodsSource, err := generateKeyMethods(keyPackage)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(output, methodsSource, 0644); err != nil {
+		return fmt.Errorf("unable to write the methods: %v", err)
+	}
+
+	if !options.withTests {
+		return nil
+	}
+
+	testsSource, err := generateKeyTests(keyPackage)
+	if err != nil {
+		return err
+	}
+
+	testOutput := strings.TrimSuffix(output, ".go") + "_test.go"
+	if err := ioutil.WriteFile(testOutput, testsSource, 0644); err != nil {
+		return fmt.Errorf("unable to write the tests: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package hashmap
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+type biMapShard struct {
+	lock sync.RWMutex
+	// it contains pairs which keys belong to the shard
+	forward *HashMap
+	// it contains pairs which values belong to the shard
+	backward *HashMap
+}
+
+// ConcurrentBiMap ...
+//
+// It's safe for concurrent access because it uses data sharding. Each shard
+// is protected by its own mutex lock. A pair can affect up to four shards
+// (of its key, of its value and of their previous pairs); all of them
+// are locked in order of their indices, so both directions stay consistent.
+//
+type ConcurrentBiMap struct {
+	shards  []*biMapShard
+	inverse bool
+}
+
+// NewConcurrentBiMap ...
+//
+// Only the concurrency level is used from the options.
+//
+func NewConcurrentBiMap(options ...ConcurrentOption) ConcurrentBiMap {
+	config := newConcurrentConfig(options)
+
+	var shards []*biMapShard
+	for i := 0; i < config.concurrencyLevel; i++ {
+		shard := &biMapShard{forward: NewHashMap(), backward: NewHashMap()}
+		shards = append(shards, shard)
+	}
+
+	return ConcurrentBiMap{shards: shards, inverse: false}
+}
+
+// Get ...
+func (biMap ConcurrentBiMap) Get(key Key) (value Key, ok bool) {
+	shard := biMap.selectShard(key)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	return getBiMapPair(biMap.forwardOf(key), key)
+}
+
+// GetByValue ...
+func (biMap ConcurrentBiMap) GetByValue(value Key) (key Key, ok bool) {
+	shard := biMap.selectShard(value)
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	return getBiMapPair(biMap.backwardOf(value), value)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// Values passed to the handler implement the Key interface.
+//
+// It randomizes of iteration order over pairs and over shards.
+//
+// A mutex lock is using only for iteration, not for handling (the handler
+// is called out of lock).
+//
+func (biMap ConcurrentBiMap) Iterate(handler Handler) bool {
+	for _, index := range rand.Perm(len(biMap.shards)) {
+		if ok := biMap.iterateShard(index, handler); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Set ...
+//
+// See the BiMap.Set() method.
+//
+func (biMap ConcurrentBiMap) Set(key Key, value Key) {
+	initialShards := []int{
+		biMap.selectShardIndex(key),
+		biMap.selectShardIndex(value),
+	}
+	lockedShards := biMap.lockShards(initialShards, func() []int {
+		var requiredShards []int
+		if oldValue, ok := getBiMapPair(biMap.forwardOf(key), key); ok {
+			index := biMap.selectShardIndex(oldValue)
+			requiredShards = append(requiredShards, index)
+		}
+		if oldKey, ok := getBiMapPair(biMap.backwardOf(value), value); ok {
+			index := biMap.selectShardIndex(oldKey)
+			requiredShards = append(requiredShards, index)
+		}
+
+		return requiredShards
+	})
+	defer biMap.unlockShards(lockedShards)
+
+	setBiMapPair(biMap.forwardOf, biMap.backwardOf, key, value)
+}
+
+// Delete ...
+func (biMap ConcurrentBiMap) Delete(key Key) {
+	biMap.deletePair(biMap.forwardOf, biMap.backwardOf, key)
+}
+
+// DeleteByValue ...
+func (biMap ConcurrentBiMap) DeleteByValue(value Key) {
+	biMap.deletePair(biMap.backwardOf, biMap.forwardOf, value)
+}
+
+// Size ...
+func (biMap ConcurrentBiMap) Size() int {
+	var size int
+	for _, shard := range biMap.shards {
+		shard.lock.RLock()
+		size += shard.forward.Size()
+		shard.lock.RUnlock()
+	}
+
+	return size
+}
+
+// Inverse ...
+//
+// It returns a view of the map with keys and values swapped. The view shares
+// data and locks with the original map.
+//
+func (biMap ConcurrentBiMap) Inverse() ConcurrentBiMap {
+	return ConcurrentBiMap{shards: biMap.shards, inverse: !biMap.inverse}
+}
+
+func (biMap ConcurrentBiMap) forwardOf(key Key) *HashMap {
+	shard := biMap.selectShard(key)
+	if biMap.inverse {
+		return shard.backward
+	}
+
+	return shard.forward
+}
+
+func (biMap ConcurrentBiMap) backwardOf(value Key) *HashMap {
+	shard := biMap.selectShard(value)
+	if biMap.inverse {
+		return shard.forward
+	}
+
+	return shard.backward
+}
+
+func (biMap ConcurrentBiMap) iterateShard(index int, handler Handler) bool {
+	shard := biMap.shards[index]
+	shard.lock.RLock()
+	defer shard.lock.RUnlock()
+
+	table := shard.forward
+	if biMap.inverse {
+		table = shard.backward
+	}
+
+	return table.Iterate(func(key Key, value interface{}) bool {
+		shard.lock.RUnlock()
+		defer shard.lock.RLock()
+
+		return handler(key, value)
+	})
+}
+
+func (biMap ConcurrentBiMap) deletePair(
+	forward biMapTableSelector,
+	backward biMapTableSelector,
+	key Key,
+) {
+	initialShards := []int{biMap.selectShardIndex(key)}
+	lockedShards := biMap.lockShards(initialShards, func() []int {
+		value, ok := getBiMapPair(forward(key), key)
+		if !ok {
+			return nil
+		}
+
+		return []int{biMap.selectShardIndex(value)}
+	})
+	defer biMap.unlockShards(lockedShards)
+
+	deleteBiMapPair(forward, backward, key)
+}
+
+// it locks the initial shards and the shards required by the operation;
+// the latter are known only under locks, so if they aren't locked yet,
+// all shards are relocked
+func (biMap ConcurrentBiMap) lockShards(
+	initialShards []int,
+	requireShards func() []int,
+) (lockedShards []int) {
+	lockedShards = normalizeShardIndices(initialShards)
+	for {
+		for _, index := range lockedShards {
+			biMap.shards[index].lock.Lock()
+		}
+
+		requiredShards := append(requireShards(), lockedShards...)
+		requiredShards = normalizeShardIndices(requiredShards)
+		// the required shards include the locked ones
+		if len(requiredShards) == len(lockedShards) {
+			return lockedShards
+		}
+
+		biMap.unlockShards(lockedShards)
+		lockedShards = requiredShards
+	}
+}
+
+func (biMap ConcurrentBiMap) unlockShards(lockedShards []int) {
+	for _, index := range lockedShards {
+		biMap.shards[index].lock.Unlock()
+	}
+}
+
+func (biMap ConcurrentBiMap) selectShard(key Key) *biMapShard {
+	return biMap.shards[biMap.selectShardIndex(key)]
+}
+
+// it selects a shard in the same way as the ConcurrentHashMap structure
+// selects a segment (see the segmentIndex() function)
+func (biMap ConcurrentBiMap) selectShardIndex(key Key) int {
+	return segmentIndex(hashKey(nil, key), len(biMap.shards))
+}
+
+// it sorts the indices and removes duplicates from them
+func normalizeShardIndices(indices []int) []int {
+	sort.Ints(indices)
+
+	var uniqueIndices []int
+	for _, index := range indices {
+		count := len(uniqueIndices)
+		if count == 0 || uniqueIndices[count-1] != index {
+			uniqueIndices = append(uniqueIndices, index)
+		}
+	}
+
+	return uniqueIndices
+}
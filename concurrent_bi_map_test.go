@@ -0,0 +1,99 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentBiMap_consistency(test *testing.T) {
+	biMap := NewConcurrentBiMap(WithConcurrencyLevel(4))
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < 100; j++ {
+				key, value := IntKey((i+j)%10), StringKey(rune('a'+(i*j)%10))
+				switch j % 3 {
+				case 0, 1:
+					biMap.Set(key, value)
+				case 2:
+					biMap.DeleteByValue(value)
+				}
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	var size int
+	biMap.Iterate(func(key Key, value interface{}) bool {
+		gotKey, gotOk := biMap.GetByValue(value.(Key))
+		assert.Equal(test, key, gotKey)
+		assert.True(test, gotOk)
+
+		size++
+		return true
+	})
+
+	var inverseSize int
+	biMap.Inverse().Iterate(func(value Key, key interface{}) bool {
+		gotValue, gotOk := biMap.Get(key.(Key))
+		assert.Equal(test, value, gotValue)
+		assert.True(test, gotOk)
+
+		inverseSize++
+		return true
+	})
+
+	assert.Equal(test, size, inverseSize)
+	assert.Equal(test, size, biMap.Size())
+}
+
+func TestConcurrentBiMap_selectShardIndex_weakHashes(test *testing.T) {
+	biMap := NewConcurrentBiMap(WithConcurrencyLevel(16))
+
+	// the low bits of these hashes are the same, so the shard selection
+	// by them would put all the keys into a single shard
+	touchedShards := make(map[int]struct{})
+	for i := 0; i < 256; i++ {
+		index := biMap.selectShardIndex(collidingKey{id: i, hash: i << 4})
+		touchedShards[index] = struct{}{}
+	}
+
+	assert.Len(test, touchedShards, 16)
+}
+
+func TestNormalizeShardIndices(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		indices []int
+		want    []int
+	}{
+		{
+			name:    "without indices",
+			indices: nil,
+			want:    nil,
+		},
+		{
+			name:    "with unique indices",
+			indices: []int{3, 1, 2},
+			want:    []int{1, 2, 3},
+		},
+		{
+			name:    "with duplicated indices",
+			indices: []int{3, 1, 3, 2, 1},
+			want:    []int{1, 2, 3},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := normalizeShardIndices(data.indices)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
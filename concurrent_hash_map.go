@@ -1,6 +1,7 @@
 package hashmap
 
 import (
+	"math/bits"
 	"math/rand"
 )
 
@@ -10,23 +11,50 @@ import (
 // Each segment should take care of concurrent access safety itself.
 //
 type ConcurrentHashMap struct {
-	segments []Storage
+	segments          []Storage
+	instrumentation   Instrumentation
+	contentionMonitor *ContentionMonitor
+	hasher            Hasher
 }
 
 // NewConcurrentHashMap ...
+//
+// Invalid options are normalized (see the ConcurrentConfig.Validate()
+// method).
+//
 func NewConcurrentHashMap(options ...ConcurrentOption) ConcurrentHashMap {
-	config := defaultConcurrentConfig
-	for _, option := range options {
-		option(&config)
+	return newConcurrentHashMapWithConfig(newConcurrentConfig(options))
+}
+
+// NewConcurrentHashMapE ...
+//
+// Unlike the NewConcurrentHashMap() function, it returns an error on invalid
+// options (see the ConcurrentConfig.Validate() method).
+//
+func NewConcurrentHashMapE(
+	options ...ConcurrentOption,
+) (ConcurrentHashMap, error) {
+	config := applyConcurrentOptions(options)
+	if err := config.Validate(); err != nil {
+		return ConcurrentHashMap{}, err
 	}
 
+	return newConcurrentHashMapWithConfig(config.normalize()), nil
+}
+
+func newConcurrentHashMapWithConfig(config ConcurrentConfig) ConcurrentHashMap {
 	var segments []Storage
 	for i := 0; i < config.concurrencyLevel; i++ {
 		segment := config.segmentFactory()
 		segments = append(segments, segment)
 	}
 
-	return ConcurrentHashMap{segments: segments}
+	return ConcurrentHashMap{
+		segments:          segments,
+		instrumentation:   config.instrumentation,
+		contentionMonitor: config.contentionMonitor,
+		hasher:            config.hasher,
+	}
 }
 
 // Get ...
@@ -53,15 +81,227 @@ func (hashMap ConcurrentHashMap) Iterate(handler Handler) bool {
 
 // Set ...
 func (hashMap ConcurrentHashMap) Set(key Key, value interface{}) {
-	hashMap.selectSegment(key).Set(key, value)
+	index := hashMap.selectSegmentIndex(key)
+	hashMap.segments[index].Set(key, value)
+	hashMap.reportSegmentLoad(index)
 }
 
 // Delete ...
 func (hashMap ConcurrentHashMap) Delete(key Key) {
-	hashMap.selectSegment(key).Delete(key)
+	index := hashMap.selectSegmentIndex(key)
+	hashMap.segments[index].Delete(key)
+	hashMap.reportSegmentLoad(index)
+}
+
+// IterateMutable ...
+//
+// It's the same as the HashMap.IterateMutable() method, but segments
+// are iterated one by one. Segments produced by the default segment factory
+// hold their locks during their iteration, so the mutation is atomic
+// per segment.
+//
+// If a segment doesn't implement the IterateMutable() method, actions
+// are applied via its Set() and Delete() methods after its iteration.
+//
+func (hashMap ConcurrentHashMap) IterateMutable(handler MutableHandler) {
+	for index, segment := range hashMap.segments {
+		iterateStorageMutable(segment, handler)
+		hashMap.reportSegmentLoad(index)
+	}
+}
+
+// DeleteIf ...
+//
+// See the HashMap.DeleteIf() and ConcurrentHashMap.IterateMutable()
+// methods.
+//
+func (hashMap ConcurrentHashMap) DeleteIf(
+	predicate func(key Key, value interface{}) bool,
+) {
+	hashMap.IterateMutable(deleteIfHandler(predicate))
+}
+
+// UpdateAll ...
+//
+// See the HashMap.UpdateAll() and ConcurrentHashMap.IterateMutable()
+// methods.
+//
+func (hashMap ConcurrentHashMap) UpdateAll(
+	update func(key Key, value interface{}) interface{},
+) {
+	hashMap.IterateMutable(updateAllHandler(update))
+}
+
+// WithEntry ...
+//
+// It calls the handler with an entry by the key (see the Entry structure)
+// provided by the segment. Segments produced by the default segment factory
+// hold their locks while the handler is called, so operations via the entry
+// are atomic. The entry shouldn't be used after the handler returns.
+//
+// If the segment doesn't provide entries, the entry uses its Get(), Set()
+// and Delete() methods, i.e. it isn't atomic.
+//
+func (hashMap ConcurrentHashMap) WithEntry(key Key, handler EntryHandler) {
+	index := hashMap.selectSegmentIndex(key)
+	withStorageEntry(hashMap.segments[index], key, handler)
+	hashMap.reportSegmentLoad(index)
+}
+
+// Scan ...
+//
+// It's the same as the HashMap.Scan() method, but segments are scanned
+// one by one; an index of a segment is stored in high bits of the cursor.
+// The guarantees hold for segments produced by the default segment factory.
+//
+// If a segment doesn't implement the Scan() method, all its items
+// are returned at once.
+//
+func (hashMap ConcurrentHashMap) Scan(cursor uint64, count int) (
+	items []ScanItem,
+	nextCursor uint64,
+) {
+	// shifting by 64 bits gives zero, so a single segment takes
+	// the whole cursor
+	segmentShift := uint(64 - bits.Len(uint(len(hashMap.segments)-1)))
+	segmentIndex := int(cursor >> segmentShift)
+	segmentCursor := cursor & (uint64(1)<<segmentShift - 1)
+	for segmentIndex < len(hashMap.segments) {
+		segment := hashMap.segments[segmentIndex]
+		segmentItems, nextSegmentCursor :=
+			scanStorage(segment, segmentCursor, count-len(items))
+		items = append(items, segmentItems...)
+
+		segmentCursor = nextSegmentCursor
+		if segmentCursor == 0 {
+			segmentIndex++
+		}
+		if len(items) >= count {
+			break
+		}
+	}
+	if segmentIndex >= len(hashMap.segments) {
+		return items, 0
+	}
+
+	return items, uint64(segmentIndex)<<segmentShift | segmentCursor
+}
+
+// Size ...
+//
+// If a segment doesn't implement the Sizer interface, its items are counted
+// via iteration.
+//
+func (hashMap ConcurrentHashMap) Size() int {
+	var size int
+	for _, segment := range hashMap.segments {
+		size += storageSize(segment)
+	}
+
+	return size
+}
+
+// Stats ...
+//
+// If a segment doesn't implement the StatsProvider interface, only its size
+// is reported.
+//
+func (hashMap ConcurrentHashMap) Stats() ConcurrentStats {
+	var segments []Stats
+	var maxSegmentSize int
+	for _, segment := range hashMap.segments {
+		stats := storageStats(segment)
+		segments = append(segments, stats)
+
+		if stats.Size > maxSegmentSize {
+			maxSegmentSize = stats.Size
+		}
+	}
+
+	total := mergeStats(segments)
+	var segmentSizeSkew float64
+	if total.Size != 0 {
+		meanSegmentSize := float64(total.Size) / float64(len(segments))
+		segmentSizeSkew = float64(maxSegmentSize) / meanSegmentSize
+	}
+
+	return ConcurrentStats{
+		Stats:           total,
+		Segments:        segments,
+		SegmentSizeSkew: segmentSizeSkew,
+	}
+}
+
+// RecommendedConcurrencyLevel ...
+//
+// It returns a concurrency level recommended by the contention monitor
+// (see the ContentionMonitor.RecommendedConcurrencyLevel() method). Without
+// the monitor, it returns the current concurrency level.
+//
+func (hashMap ConcurrentHashMap) RecommendedConcurrencyLevel() int {
+	if hashMap.contentionMonitor == nil {
+		return len(hashMap.segments)
+	}
+
+	return hashMap.contentionMonitor.
+		RecommendedConcurrencyLevel(len(hashMap.segments))
+}
+
+// if the segment doesn't implement the updater interface, it's updated
+// not atomically
+func (hashMap ConcurrentHashMap) update(key Key, handler updateHandler) {
+	index := hashMap.selectSegmentIndex(key)
+	updateStorage(hashMap.segments[index], key, handler)
+	hashMap.reportSegmentLoad(index)
 }
 
 func (hashMap ConcurrentHashMap) selectSegment(key Key) Storage {
-	index := key.Hash() % len(hashMap.segments)
-	return hashMap.segments[index]
+	return hashMap.segments[hashMap.selectSegmentIndex(key)]
+}
+
+func (hashMap ConcurrentHashMap) selectSegmentIndex(key Key) int {
+	hash := hashKey(hashMap.hasher, key)
+	return segmentIndex(hash, len(hashMap.segments))
+}
+
+func (hashMap ConcurrentHashMap) reportSegmentLoad(index int) {
+	if hashMap.instrumentation == nil {
+		return
+	}
+
+	sizer, ok := hashMap.segments[index].(Sizer)
+	if !ok {
+		return
+	}
+
+	hashMap.instrumentation.OnSegmentLoad(index, sizer.Size())
+}
+
+// it selects a segment by high bits of the mixed hash, while the HashMap
+// structure selects a bucket by low bits, so keys of the same segment
+// don't crowd in the same buckets; the mixing spreads weak hashes of keys
+// (e.g. small integers) over the high bits
+func segmentIndex(keyHash uint64, segmentCount int) int {
+	hash, count := mixHash(keyHash), uint64(segmentCount)
+	// a count of segments that is a power of two allows to use a bit shift
+	if count&(count-1) == 0 {
+		return int(hash >> (64 - uint(bits.Len64(count-1))))
+	}
+
+	// it maps the hash to the [0, count) range proportionally,
+	// see "Fast Random Integer Generation in an Interval" by D. Lemire
+	return int(mulHigh64(hash, count))
+}
+
+// it returns high 64 bits of the 128-bit product like the bits.Mul64()
+// function, which isn't available before Go 1.12
+func mulHigh64(x uint64, y uint64) uint64 {
+	const mask32 = 1<<32 - 1
+	x0, x1 := x&mask32, x>>32
+	y0, y1 := y&mask32, y>>32
+	w0 := x0 * y0
+	t := x1*y0 + w0>>32
+	w1 := t&mask32 + x0*y1
+
+	return x1*y1 + t>>32 + w1>>32
 }
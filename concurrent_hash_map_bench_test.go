@@ -88,3 +88,117 @@ func BenchmarkConcurrentHashMap(benchmark *testing.B) {
 		}
 	}
 }
+
+func BenchmarkShardedHashMap(benchmark *testing.B) {
+	for _, data := range []struct {
+		name      string
+		prepare   func() *ShardedHashMap
+		benchmark func(hashMap *ShardedHashMap)
+	}{
+		{
+			name: "Get",
+			prepare: func() *ShardedHashMap {
+				hashMap := NewShardedHashMap()
+				for i := 0; i < sizeForSyncBench; i++ {
+					hashMap.Set(IntKey(i), i)
+				}
+
+				return hashMap
+			},
+			benchmark: func(hashMap *ShardedHashMap) {
+				hashMap.Get(IntKey(rand.Intn(sizeForSyncBench)))
+			},
+		},
+		{
+			name: "Iterate",
+			prepare: func() *ShardedHashMap {
+				hashMap := NewShardedHashMap()
+				for i := 0; i < sizeForSyncBench; i++ {
+					hashMap.Set(IntKey(i), i)
+				}
+
+				return hashMap
+			},
+			benchmark: func(hashMap *ShardedHashMap) {
+				hashMap.Iterate(func(key Key, value interface{}) bool { return true })
+			},
+		},
+		{
+			name:    "Set",
+			prepare: func() *ShardedHashMap { return NewShardedHashMap() },
+			benchmark: func(hashMap *ShardedHashMap) {
+				for i := 0; i < sizeForSyncBench; i++ {
+					hashMap.Set(IntKey(i), i)
+				}
+			},
+		},
+		{
+			name: "Delete",
+			prepare: func() *ShardedHashMap {
+				hashMap := NewShardedHashMap()
+				for i := 0; i < sizeForSyncBench; i++ {
+					hashMap.Set(IntKey(i), i)
+				}
+
+				return hashMap
+			},
+			benchmark: func(hashMap *ShardedHashMap) {
+				hashMap.Delete(IntKey(rand.Intn(sizeForSyncBench)))
+			},
+		},
+	} {
+		for threads := 1; threads <= 1e3; threads *= 10 {
+			name := fmt.Sprintf("%s/%d/%d", data.name, sizeForSyncBench, threads)
+			benchmark.Run(name, func(benchmark *testing.B) {
+				hashMap := data.prepare()
+				benchmark.ResetTimer()
+
+				for i := 0; i < benchmark.N; i++ {
+					var waiter sync.WaitGroup
+					waiter.Add(threads)
+
+					for j := 0; j < threads; j++ {
+						go func() {
+							defer waiter.Done()
+							data.benchmark(hashMap)
+						}()
+					}
+
+					waiter.Wait()
+				}
+			})
+		}
+	}
+}
+
+// it compares the ConcurrentHashMap and ShardedHashMap structures under
+// heavy write load, where false sharing of segments is noticeable
+func BenchmarkShardedHashMap_writeHeavy(benchmark *testing.B) {
+	for _, data := range []struct {
+		name    string
+		prepare func() Storage
+	}{
+		{
+			name:    "ConcurrentHashMap",
+			prepare: func() Storage { return NewConcurrentHashMap() },
+		},
+		{
+			name:    "ShardedHashMap",
+			prepare: func() Storage { return NewShardedHashMap() },
+		},
+	} {
+		benchmark.Run(data.name, func(benchmark *testing.B) {
+			hashMap := data.prepare()
+			for i := 0; i < sizeForSyncBench; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+			benchmark.ResetTimer()
+
+			benchmark.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					hashMap.Set(IntKey(i%sizeForSyncBench), i)
+				}
+			})
+		})
+	}
+}
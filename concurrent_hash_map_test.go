@@ -1,8 +1,12 @@
 package hashmap
 
 import (
+	"math"
 	"math/rand"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,7 +34,7 @@ func TestNewConcurrentHashMap(test *testing.T) {
 						segments = append(segments, &SynchronizedHashMap{
 							innerMap: &HashMap{
 								config:  defaultConfig,
-								buckets: make([]*bucket, defaultConfig.initialCapacity),
+								buckets: make([]bucket, defaultConfig.initialCapacity),
 								size:    0,
 							},
 						})
@@ -52,7 +56,7 @@ func TestNewConcurrentHashMap(test *testing.T) {
 						segments = append(segments, &SynchronizedHashMap{
 							innerMap: &HashMap{
 								config:  defaultConfig,
-								buckets: make([]*bucket, defaultConfig.initialCapacity),
+								buckets: make([]bucket, defaultConfig.initialCapacity),
 								size:    0,
 							},
 						})
@@ -62,6 +66,73 @@ func TestNewConcurrentHashMap(test *testing.T) {
 				}(),
 			},
 		},
+		{
+			name: "with the normalized concurrency level",
+			args: args{
+				options: []ConcurrentOption{WithConcurrencyLevel(0)},
+			},
+			want: ConcurrentHashMap{
+				segments: []Storage{
+					&SynchronizedHashMap{
+						innerMap: &HashMap{
+							config:  defaultConfig,
+							buckets: make([]bucket, defaultConfig.initialCapacity),
+							size:    0,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "with the automatic concurrency level",
+			args: args{
+				options: []ConcurrentOption{WithAutoConcurrencyLevel(3)},
+			},
+			want: ConcurrentHashMap{
+				segments: func() []Storage {
+					var segments []Storage
+					level := ceilPowerOfTwo(runtime.GOMAXPROCS(0) * 3)
+					for i := 0; i < level; i++ {
+						segments = append(segments, &SynchronizedHashMap{
+							innerMap: &HashMap{
+								config:  defaultConfig,
+								buckets: make([]bucket, defaultConfig.initialCapacity),
+								size:    0,
+							},
+						})
+					}
+
+					return segments
+				}(),
+			},
+		},
+		{
+			name: "with the set contention monitor",
+			args: args{
+				options: []ConcurrentOption{
+					WithConcurrencyLevel(2),
+					WithContentionMonitor(NewContentionMonitor(time.Millisecond)),
+				},
+			},
+			want: ConcurrentHashMap{
+				segments: func() []Storage {
+					var segments []Storage
+					for i := 0; i < 2; i++ {
+						segments = append(segments, &SynchronizedHashMap{
+							innerMap: &HashMap{
+								config:  defaultConfig,
+								buckets: make([]bucket, defaultConfig.initialCapacity),
+								size:    0,
+							},
+							instrumentation: NewContentionMonitor(time.Millisecond),
+						})
+					}
+
+					return segments
+				}(),
+				contentionMonitor: NewContentionMonitor(time.Millisecond),
+			},
+		},
 		{
 			name: "with the set segment factory",
 			args: args{
@@ -102,6 +173,7 @@ func TestNewConcurrentHashMap(test *testing.T) {
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			got := NewConcurrentHashMap(data.args.options...)
+			resetSeeds(test, got)
 
 			for _, segment := range got.segments {
 				_, ok := segment.(interface {
@@ -116,6 +188,51 @@ func TestNewConcurrentHashMap(test *testing.T) {
 	}
 }
 
+func TestNewConcurrentHashMapE(test *testing.T) {
+	type args struct {
+		options []ConcurrentOption
+	}
+
+	for _, data := range []struct {
+		name             string
+		args             args
+		wantSegmentCount int
+		wantErr          error
+	}{
+		{
+			name: "with the valid config",
+			args: args{
+				options: []ConcurrentOption{WithConcurrencyLevel(23)},
+			},
+			wantSegmentCount: 23,
+			wantErr:          nil,
+		},
+		{
+			name: "with the zero concurrency level",
+			args: args{
+				options: []ConcurrentOption{WithConcurrencyLevel(0)},
+			},
+			wantSegmentCount: 0,
+			wantErr:          ErrInvalidConcurrencyLevel,
+		},
+		{
+			name: "with the negative concurrency level",
+			args: args{
+				options: []ConcurrentOption{WithConcurrencyLevel(-23)},
+			},
+			wantSegmentCount: 0,
+			wantErr:          ErrInvalidConcurrencyLevel,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewConcurrentHashMapE(data.args.options...)
+
+			assert.Len(test, got.segments, data.wantSegmentCount)
+			assert.Equal(test, data.wantErr, errorCause(err))
+		})
+	}
+}
+
 func TestConcurrentHashMap(test *testing.T) {
 	type result struct {
 		value interface{}
@@ -160,7 +277,7 @@ func TestConcurrentHashMap(test *testing.T) {
 
 				return []Key{key}
 			},
-			wantTouchedSegments: map[int]struct{}{5: {}},
+			wantTouchedSegments: map[int]struct{}{11: {}},
 			wantResults:         []result{{"five", true}},
 		},
 		{
@@ -182,7 +299,7 @@ func TestConcurrentHashMap(test *testing.T) {
 
 				return []Key{key}
 			},
-			wantTouchedSegments: map[int]struct{}{5: {}},
+			wantTouchedSegments: map[int]struct{}{11: {}},
 			wantResults:         []result{{"five #2", true}},
 		},
 		{
@@ -211,7 +328,7 @@ func TestConcurrentHashMap(test *testing.T) {
 
 				return []Key{fiveKey, sixKey}
 			},
-			wantTouchedSegments: map[int]struct{}{5: {}, 6: {}},
+			wantTouchedSegments: map[int]struct{}{11: {}, 13: {}},
 			wantResults:         []result{{"five", true}, {"six", true}},
 		},
 		{
@@ -301,7 +418,7 @@ func TestConcurrentHashMap(test *testing.T) {
 			for index, segment := range hashMap.segments {
 				innerMap := segment.(*SynchronizedHashMap).innerMap.(*HashMap)
 				for _, bucket := range innerMap.buckets {
-					if bucket != nil {
+					if bucket.occupied {
 						mock.AssertExpectationsForObjects(test, bucket.key)
 					}
 				}
@@ -321,7 +438,7 @@ func TestConcurrentHashMap(test *testing.T) {
 
 func TestConcurrentHashMap_Iterate(test *testing.T) {
 	type fields struct {
-		buckets [][]*bucket
+		buckets [][]bucket
 	}
 
 	for _, data := range []struct {
@@ -334,9 +451,9 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 		{
 			name: "without buckets",
 			fields: fields{
-				buckets: [][]*bucket{
-					make([]*bucket, defaultConfig.initialCapacity),
-					make([]*bucket, defaultConfig.initialCapacity),
+				buckets: [][]bucket{
+					make([]bucket, defaultConfig.initialCapacity),
+					make([]bucket, defaultConfig.initialCapacity),
 				},
 			},
 			interruptOnCount: 10,
@@ -346,11 +463,11 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets in the same segment and without an interrupt",
 			fields: fields{
-				buckets: [][]*bucket{
+				buckets: [][]bucket{
 					5: {
-						5: {key: new(MockKey), value: "five #1"},
-						6: {key: new(MockKey), value: "five #2"},
-						7: {key: new(MockKey), value: "five #3"},
+						5: {key: new(MockKey), value: "five #1", occupied: true},
+						6: {key: new(MockKey), value: "five #2", occupied: true},
+						7: {key: new(MockKey), value: "five #3", occupied: true},
 					},
 				},
 			},
@@ -365,11 +482,11 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets in the same segment and with an interrupt",
 			fields: fields{
-				buckets: [][]*bucket{
+				buckets: [][]bucket{
 					5: {
-						5: {key: new(MockKey), value: "five #1"},
-						6: {key: new(MockKey), value: "five #2"},
-						7: {key: new(MockKey), value: "five #3"},
+						5: {key: new(MockKey), value: "five #1", occupied: true},
+						6: {key: new(MockKey), value: "five #2", occupied: true},
+						7: {key: new(MockKey), value: "five #3", occupied: true},
 					},
 				},
 			},
@@ -383,10 +500,10 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets in different segments and without an interrupt",
 			fields: fields{
-				buckets: [][]*bucket{
-					5: {5: {key: new(MockKey), value: "five"}},
-					6: {6: {key: new(MockKey), value: "six"}},
-					7: {7: {key: new(MockKey), value: "seven"}},
+				buckets: [][]bucket{
+					5: {5: {key: new(MockKey), value: "five", occupied: true}},
+					6: {6: {key: new(MockKey), value: "six", occupied: true}},
+					7: {7: {key: new(MockKey), value: "seven", occupied: true}},
 				},
 			},
 			interruptOnCount: 10,
@@ -400,10 +517,10 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets in different segments and with an interrupt",
 			fields: fields{
-				buckets: [][]*bucket{
-					5: {5: {key: new(MockKey), value: "five"}},
-					6: {6: {key: new(MockKey), value: "six"}},
-					7: {7: {key: new(MockKey), value: "seven"}},
+				buckets: [][]bucket{
+					5: {5: {key: new(MockKey), value: "five", occupied: true}},
+					6: {6: {key: new(MockKey), value: "six", occupied: true}},
+					7: {7: {key: new(MockKey), value: "seven", occupied: true}},
 				},
 			},
 			interruptOnCount: 2,
@@ -428,14 +545,14 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 			var gotBuckets []bucket
 			hashMap := ConcurrentHashMap{segments: segments}
 			gotOk := hashMap.Iterate(func(key Key, value interface{}) bool {
-				gotBuckets = append(gotBuckets, bucket{key, value})
+				gotBuckets = append(gotBuckets, bucket{key: key, value: value})
 				// interrupt after a specified count of got buckets
 				return len(gotBuckets) < data.interruptOnCount
 			})
 
 			for _, buckets := range data.fields.buckets {
 				for _, bucket := range buckets {
-					if bucket != nil {
+					if bucket.occupied {
 						mock.AssertExpectationsForObjects(test, bucket.key)
 					}
 				}
@@ -448,7 +565,7 @@ func TestConcurrentHashMap_Iterate(test *testing.T) {
 
 func TestConcurrentHashMap_Iterate_order(test *testing.T) {
 	type fields struct {
-		buckets [][]*bucket
+		buckets [][]bucket
 	}
 
 	for _, data := range []struct {
@@ -462,11 +579,11 @@ func TestConcurrentHashMap_Iterate_order(test *testing.T) {
 		{
 			name: "with few buckets in the same segment",
 			fields: fields{
-				buckets: [][]*bucket{
+				buckets: [][]bucket{
 					5: {
-						5: {key: new(MockKey), value: "five #1"},
-						6: {key: new(MockKey), value: "five #2"},
-						7: {key: new(MockKey), value: "five #3"},
+						5: {key: new(MockKey), value: "five #1", occupied: true},
+						6: {key: new(MockKey), value: "five #2", occupied: true},
+						7: {key: new(MockKey), value: "five #3", occupied: true},
 					},
 				},
 			},
@@ -486,10 +603,10 @@ func TestConcurrentHashMap_Iterate_order(test *testing.T) {
 		{
 			name: "with few buckets in different segments",
 			fields: fields{
-				buckets: [][]*bucket{
-					5: {5: {key: new(MockKey), value: "five"}},
-					6: {6: {key: new(MockKey), value: "six"}},
-					7: {7: {key: new(MockKey), value: "seven"}},
+				buckets: [][]bucket{
+					5: {5: {key: new(MockKey), value: "five", occupied: true}},
+					6: {6: {key: new(MockKey), value: "six", occupied: true}},
+					7: {7: {key: new(MockKey), value: "seven", occupied: true}},
 				},
 			},
 			randomSeedOne: 1,
@@ -519,20 +636,20 @@ func TestConcurrentHashMap_Iterate_order(test *testing.T) {
 			var gotBucketsOne []bucket
 			rand.Seed(data.randomSeedOne)
 			gotOkOne := hashMap.Iterate(func(key Key, value interface{}) bool {
-				gotBucketsOne = append(gotBucketsOne, bucket{key, value})
+				gotBucketsOne = append(gotBucketsOne, bucket{key: key, value: value})
 				return true
 			})
 
 			var gotBucketsTwo []bucket
 			rand.Seed(data.randomSeedTwo)
 			gotOkTwo := hashMap.Iterate(func(key Key, value interface{}) bool {
-				gotBucketsTwo = append(gotBucketsTwo, bucket{key, value})
+				gotBucketsTwo = append(gotBucketsTwo, bucket{key: key, value: value})
 				return true
 			})
 
 			for _, buckets := range data.fields.buckets {
 				for _, bucket := range buckets {
-					if bucket != nil {
+					if bucket.occupied {
 						mock.AssertExpectationsForObjects(test, bucket.key)
 					}
 				}
@@ -546,3 +663,335 @@ func TestConcurrentHashMap_Iterate_order(test *testing.T) {
 		})
 	}
 }
+
+func TestConcurrentHashMap_instrumentation(test *testing.T) {
+	instrumentation := new(MockInstrumentation)
+	instrumentation.On("OnSegmentLoad", 0, 1).Once()
+	instrumentation.On("OnSegmentLoad", 0, 2).Once()
+	instrumentation.On("OnSegmentLoad", 1, 1).Once()
+	instrumentation.On("OnSegmentLoad", 0, 1).Once()
+
+	hashMap := NewConcurrentHashMap(
+		WithConcurrencyLevel(2),
+		WithConcurrentInstrumentation(instrumentation),
+	)
+	hashMap.Set(collidingKey{id: 1, hash: 1}, "one")
+	hashMap.Set(collidingKey{id: 3, hash: 3}, "three")
+	hashMap.Set(collidingKey{id: 2, hash: 2}, "two")
+	hashMap.Delete(collidingKey{id: 1, hash: 1})
+
+	mock.AssertExpectationsForObjects(test, instrumentation)
+	assert.Equal(test, 2, hashMap.Size())
+}
+
+func TestConcurrentHashMap_selectSegmentIndex(test *testing.T) {
+	type fields struct {
+		segments []Storage
+		hasher   Hasher
+	}
+	type args struct {
+		key Key
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+		want   int
+	}{
+		{
+			name: "with a power of two of segments",
+			fields: fields{
+				segments: make([]Storage, 8),
+			},
+			args: args{
+				key: collidingKey{id: 1, hash: 21},
+			},
+			want: 6,
+		},
+		{
+			name: "with a single segment",
+			fields: fields{
+				segments: make([]Storage, 1),
+			},
+			args: args{
+				key: collidingKey{id: 1, hash: 21},
+			},
+			want: 0,
+		},
+		{
+			name: "with an arbitrary count of segments",
+			fields: fields{
+				segments: make([]Storage, 6),
+			},
+			args: args{
+				key: collidingKey{id: 1, hash: 21},
+			},
+			want: 5,
+		},
+		{
+			name: "with a negative hash",
+			fields: fields{
+				segments: make([]Storage, 8),
+			},
+			args: args{
+				key: collidingKey{id: 1, hash: -3},
+			},
+			want: 0,
+		},
+		{
+			name: "with a hasher",
+			fields: fields{
+				segments: make([]Storage, 8),
+				hasher:   func(key Key) uint64 { return 9 },
+			},
+			args: args{
+				key: collidingKey{id: 1, hash: 21},
+			},
+			want: 4,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := ConcurrentHashMap{
+				segments: data.fields.segments,
+				hasher:   data.fields.hasher,
+			}
+			got := hashMap.selectSegmentIndex(data.args.key)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestConcurrentHashMap_selectSegmentIndex_weakHashes(test *testing.T) {
+	hashMap := NewConcurrentHashMap(WithConcurrencyLevel(16))
+
+	// the low bits of these hashes are the same, so the segment selection
+	// by them would put all the keys into a single segment
+	touchedSegments := make(map[int]struct{})
+	for i := 0; i < 256; i++ {
+		index := hashMap.selectSegmentIndex(collidingKey{id: i, hash: i << 4})
+		touchedSegments[index] = struct{}{}
+	}
+
+	assert.Len(test, touchedSegments, 16)
+}
+
+func TestConcurrentHashMap_withHasherAndEqualer(test *testing.T) {
+	hashMap := NewConcurrentHashMap(
+		WithConcurrentHasher(caseInsensitiveHasher),
+		WithConcurrentEqualer(caseInsensitiveEqualer),
+	)
+	hashMap.Set(StringKey("key"), "one")
+	hashMap.Set(StringKey("KEY"), "two")
+
+	gotValue, gotOk := hashMap.Get(StringKey("Key"))
+	assert.Equal(test, "two", gotValue)
+	assert.True(test, gotOk)
+	assert.Equal(test, 1, hashMap.Size())
+
+	hashMap.Delete(StringKey("kEY"))
+	assert.Equal(test, 0, hashMap.Size())
+}
+
+func TestConcurrentHashMap_RecommendedConcurrencyLevel(test *testing.T) {
+	test.Run("without a contention monitor", func(test *testing.T) {
+		hashMap := NewConcurrentHashMap(WithConcurrencyLevel(23))
+		got := hashMap.RecommendedConcurrencyLevel()
+
+		assert.Equal(test, 23, got)
+	})
+
+	test.Run("with a contention monitor", func(test *testing.T) {
+		monitor := NewContentionMonitor(0)
+		hashMap := NewConcurrentHashMap(
+			WithConcurrencyLevel(23),
+			WithContentionMonitor(monitor),
+		)
+		monitor.OnLockContention(time.Millisecond)
+		got := hashMap.RecommendedConcurrencyLevel()
+
+		assert.Equal(test, 64, got)
+	})
+}
+
+func TestConcurrentHashMap_WithEntry(test *testing.T) {
+	const handlerCount = 10
+	const callsPerHandler = 100
+	const keyCount = 5
+
+	hashMap := NewConcurrentHashMap(WithConcurrencyLevel(4))
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < handlerCount; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < callsPerHandler; j++ {
+				hashMap.WithEntry(IntKey(j%keyCount), func(entry *Entry) {
+					entry.
+						AndModify(func(value interface{}) interface{} {
+							return value.(int) + 1
+						}).
+						OrInsert(1)
+				})
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	for i := 0; i < keyCount; i++ {
+		counter, _ := hashMap.Get(IntKey(i))
+		assert.Equal(test, handlerCount*callsPerHandler/keyCount, counter)
+	}
+	assert.Equal(test, keyCount, hashMap.Size())
+}
+
+func TestConcurrentHashMap_Scan(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		makeSegment   StorageFactory
+		wantCallCount int
+	}{
+		{
+			name:          "with segments produced by the default factory",
+			makeSegment:   nil,
+			wantCallCount: 10,
+		},
+		{
+			name: "with segments that don't support scanning",
+			makeSegment: func() Storage {
+				return NewLinkedHashMap()
+			},
+			wantCallCount: 4,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			options := []ConcurrentOption{WithConcurrencyLevel(4)}
+			if data.makeSegment != nil {
+				options = append(options, WithSegmentFactory(data.makeSegment))
+			}
+
+			hashMap := NewConcurrentHashMap(options...)
+			for i := 0; i < 100; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+
+			gotValues := make(map[interface{}]struct{})
+			var cursor uint64
+			var callCount int
+			for {
+				var items []ScanItem
+				items, cursor = hashMap.Scan(cursor, 10)
+				callCount++
+
+				for _, item := range items {
+					gotValues[item.Value] = struct{}{}
+				}
+				if cursor == 0 {
+					break
+				}
+
+				if callCount > 3 {
+					continue
+				}
+
+				// segments are grown between first calls
+				for i := 0; i < 10; i++ {
+					key := 100 + callCount*10 + i
+					hashMap.Set(IntKey(key), key)
+				}
+			}
+
+			assert.True(test, callCount >= data.wantCallCount)
+			for i := 0; i < 100; i++ {
+				assert.Contains(test, gotValues, i)
+			}
+		})
+	}
+}
+
+func TestConcurrentHashMap_IterateMutable(test *testing.T) {
+	const keyCount = 10
+	const updaterCount = 10
+
+	hashMap := NewConcurrentHashMap(WithConcurrencyLevel(4))
+	for i := 0; i < keyCount; i++ {
+		hashMap.Set(IntKey(i), 0)
+	}
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < updaterCount; i++ {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			hashMap.UpdateAll(func(key Key, value interface{}) interface{} {
+				return value.(int) + 1
+			})
+		}()
+		go func(updater int) {
+			defer waitGroup.Done()
+
+			hashMap.Set(IntKey(keyCount+updater), 0)
+			hashMap.DeleteIf(func(key Key, value interface{}) bool {
+				return int(key.(IntKey)) == keyCount+updater
+			})
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, keyCount, hashMap.Size())
+	for i := 0; i < keyCount; i++ {
+		counter, _ := hashMap.Get(IntKey(i))
+		assert.Equal(test, updaterCount, counter)
+	}
+}
+
+func Test_mulHigh64(test *testing.T) {
+	type args struct {
+		x uint64
+		y uint64
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{
+			name: "with a small product",
+			args: args{x: 3, y: 5},
+			want: 0,
+		},
+		{
+			name: "with a product equal to 2 to the power of 64",
+			args: args{x: 1 << 32, y: 1 << 32},
+			want: 1,
+		},
+		{
+			name: "with a proportional mapping",
+			args: args{x: 1 << 63, y: 23},
+			want: 11,
+		},
+		{
+			name: "with maximal factors",
+			args: args{x: math.MaxUint64, y: math.MaxUint64},
+			want: math.MaxUint64 - 1,
+		},
+		{
+			name: "with carries from low halves",
+			args: args{x: 0xffffffff00000001, y: 0x00000001ffffffff},
+			want: 0x1fffffffd,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := mulHigh64(data.args.x, data.args.y)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
@@ -1,27 +1,76 @@
 package hashmap
 
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
 // StorageFactory ...
 type StorageFactory func() Storage
 
+// FallibleStorageFactory ...
+type FallibleStorageFactory func() FallibleStorage
+
 // ConcurrentConfig ...
 type ConcurrentConfig struct {
-	concurrencyLevel int
-	segmentFactory   StorageFactory
+	concurrencyLevel       int
+	segmentFactory         StorageFactory
+	segmentOptions         []Option
+	fallibleSegmentFactory FallibleStorageFactory
+	instrumentation        Instrumentation
+	contentionMonitor      *ContentionMonitor
+	hasher                 Hasher
+	equaler                Equaler
+	maxConcurrencyLevel    int
+	splitThreshold         int
+	mergeThreshold         int
+	contentionThreshold    time.Duration
 }
 
 // nolint: gochecknoglobals
 var (
 	defaultConcurrentConfig = ConcurrentConfig{
-		concurrencyLevel: 16,
-		segmentFactory:   func() Storage { return NewSynchronizedHashMap() },
+		concurrencyLevel:    16,
+		maxConcurrencyLevel: 1024,
 	}
 )
 
+// ErrInvalidConcurrencyLevel ...
+//
+// nolint: gochecknoglobals
+//
+var ErrInvalidConcurrencyLevel = errors.New("concurrency level isn't positive")
+
+// Validate ...
+//
+// It returns an error if the config breaks the ConcurrentHashMap structure:
+// a zero concurrency level leaves no segments to select. Options of segments
+// should be validated separately.
+//
+// The returned error details the ErrInvalidConcurrencyLevel error, which
+// is returned by its Cause() and Unwrap() methods.
+//
+func (config ConcurrentConfig) Validate() error {
+	if config.concurrencyLevel <= 0 {
+		return newDetailedError(
+			ErrInvalidConcurrencyLevel,
+			"%d",
+			config.concurrencyLevel,
+		)
+	}
+
+	return nil
+}
+
 // ConcurrentOption ...
 type ConcurrentOption func(options *ConcurrentConfig)
 
 // WithConcurrencyLevel ...
 //
+// It should be positive, a non-positive one is replaced by 1
+// (see also the NewConcurrentHashMapE() function).
+//
 // Default: 16.
 //
 func WithConcurrencyLevel(concurrencyLevel int) ConcurrentOption {
@@ -30,13 +79,223 @@ func WithConcurrencyLevel(concurrencyLevel int) ConcurrentOption {
 	}
 }
 
+// WithAutoConcurrencyLevel ...
+//
+// It derives the concurrency level from the runtime.GOMAXPROCS() value
+// multiplied by the passed count of segments per processor. The result
+// is rounded up to a power of two, so segments are selected via a bit mask.
+//
+// It overrides the WithConcurrencyLevel() option and vice versa.
+//
+func WithAutoConcurrencyLevel(segmentsPerProcessor int) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		concurrencyLevel := runtime.GOMAXPROCS(0) * segmentsPerProcessor
+		options.concurrencyLevel = ceilPowerOfTwo(concurrencyLevel)
+	}
+}
+
 // WithSegmentFactory ...
 //
 // Default: a factory that produces an instance
-// of the SynchronizedHashMap structure with default options.
+// of the SynchronizedHashMap structure with default options; if the contention
+// monitor is set, the instance reports lock contention to it; if the hasher
+// or the equaler is set, the inner map of the instance uses them.
 //
 func WithSegmentFactory(segmentFactory StorageFactory) ConcurrentOption {
 	return func(options *ConcurrentConfig) {
 		options.segmentFactory = segmentFactory
 	}
 }
+
+// WithFallibleSegmentFactory ...
+//
+// It's used only by the FallibleConcurrentHashMap structure.
+//
+// Default: a factory that adapts segments produced by the segment factory
+// via the FallibleAdapter structure.
+//
+func WithFallibleSegmentFactory(
+	segmentFactory FallibleStorageFactory,
+) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.fallibleSegmentFactory = segmentFactory
+	}
+}
+
+// WithConcurrentInstrumentation ...
+//
+// It's used only by the ConcurrentHashMap structure. Segments should
+// be configured separately via the segment factory.
+//
+// Default: nil (events aren't reported).
+//
+func WithConcurrentInstrumentation(
+	instrumentation Instrumentation,
+) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.instrumentation = instrumentation
+	}
+}
+
+// WithSegmentOptions ...
+//
+// It's used only by the ShardedHashMap structure, which creates its segments
+// itself instead of using the segment factory.
+//
+// Default: no options (segments use default options of the HashMap
+// structure).
+//
+func WithSegmentOptions(segmentOptions ...Option) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.segmentOptions = segmentOptions
+	}
+}
+
+// WithContentionMonitor ...
+//
+// It's used only by the ConcurrentHashMap structure and by the default
+// segment factory (see the ConcurrentHashMap.RecommendedConcurrencyLevel()
+// method). Custom segments should report to the monitor themselves.
+//
+// Default: nil (contention isn't measured).
+//
+func WithContentionMonitor(monitor *ContentionMonitor) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.contentionMonitor = monitor
+	}
+}
+
+// WithConcurrentHasher ...
+//
+// It's used by the ConcurrentHashMap, FallibleConcurrentHashMap
+// and ReshardableHashMap structures for selection of segments and by
+// the default segment factory (see the WithHasher() function). Custom segments
+// should be configured with the same hasher separately.
+//
+// Default: nil (the Key.Hash() method is used).
+//
+func WithConcurrentHasher(hasher Hasher) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.hasher = hasher
+	}
+}
+
+// WithConcurrentEqualer ...
+//
+// It's used only by the default segment factory (see the WithEqualer()
+// function). Custom segments should be configured with the same equaler
+// separately.
+//
+// Default: nil (the Key.Equals() method is used).
+//
+func WithConcurrentEqualer(equaler Equaler) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.equaler = equaler
+	}
+}
+
+// WithMaxConcurrencyLevel ...
+//
+// It's used only by the ReshardableHashMap structure. It's rounded up
+// to a power of two.
+//
+// Default: 1024.
+//
+func WithMaxConcurrencyLevel(maxConcurrencyLevel int) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.maxConcurrencyLevel = maxConcurrencyLevel
+	}
+}
+
+// WithSplitThreshold ...
+//
+// It's used only by the ReshardableHashMap structure. If a segment size
+// exceeds the threshold after setting, the segment is split.
+//
+// Default: 0 (splitting by a size is disabled).
+//
+func WithSplitThreshold(size int) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.splitThreshold = size
+	}
+}
+
+// WithMergeThreshold ...
+//
+// It's used only by the ReshardableHashMap structure. If a total size
+// of a segment and of its buddy is less than the threshold after deleting,
+// they are merged.
+//
+// Default: 0 (merging by a size is disabled).
+//
+func WithMergeThreshold(size int) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.mergeThreshold = size
+	}
+}
+
+// WithContentionThreshold ...
+//
+// It's used only by the ReshardableHashMap structure. If an operation
+// on a segment takes longer than the threshold, the segment is split.
+// For segments that use locks, the time of an operation mostly consists
+// of the lock wait.
+//
+// Default: 0 (splitting by contention is disabled).
+//
+func WithContentionThreshold(wait time.Duration) ConcurrentOption {
+	return func(options *ConcurrentConfig) {
+		options.contentionThreshold = wait
+	}
+}
+
+func newConcurrentConfig(options []ConcurrentOption) ConcurrentConfig {
+	return applyConcurrentOptions(options).normalize()
+}
+
+func applyConcurrentOptions(options []ConcurrentOption) ConcurrentConfig {
+	config := defaultConcurrentConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+// it replaces invalid values (see the ConcurrentConfig.Validate() method)
+// by the closest valid ones and sets the default segment factory
+func (config ConcurrentConfig) normalize() ConcurrentConfig {
+	if config.concurrencyLevel <= 0 {
+		config.concurrencyLevel = 1
+	}
+
+	if config.segmentFactory == nil {
+		var segmentOptions []SynchronizedOption
+		if config.contentionMonitor != nil {
+			segmentOptions = append(
+				segmentOptions,
+				WithSynchronizedInstrumentation(config.contentionMonitor),
+			)
+		}
+
+		var innerMapOptions []Option
+		if config.hasher != nil {
+			innerMapOptions = append(innerMapOptions, WithHasher(config.hasher))
+		}
+		if config.equaler != nil {
+			innerMapOptions = append(innerMapOptions, WithEqualer(config.equaler))
+		}
+
+		config.segmentFactory = func() Storage {
+			options := segmentOptions
+			if len(innerMapOptions) != 0 {
+				innerMap := NewHashMap(innerMapOptions...)
+				options = append([]SynchronizedOption{WithInnerMap(innerMap)}, options...)
+			}
+
+			return NewSynchronizedHashMap(options...)
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,78 @@
+package hashmap
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// ContentionMonitor ...
+//
+// It's an instrumentation that measures lock contention of synchronized maps
+// (e.g. segments of a concurrent hash map) and recommends a concurrency
+// level. An acquisition is considered as contended if its wait exceeds
+// the threshold. It's safe for concurrent access.
+//
+type ContentionMonitor struct {
+	NopInstrumentation
+
+	threshold    time.Duration
+	acquisitions int64
+	contentions  int64
+}
+
+// NewContentionMonitor ...
+func NewContentionMonitor(threshold time.Duration) *ContentionMonitor {
+	return &ContentionMonitor{threshold: threshold}
+}
+
+// OnLockContention ...
+func (monitor *ContentionMonitor) OnLockContention(wait time.Duration) {
+	atomic.AddInt64(&monitor.acquisitions, 1)
+	if wait > monitor.threshold {
+		atomic.AddInt64(&monitor.contentions, 1)
+	}
+}
+
+// ContentionRatio ...
+//
+// It returns a ratio of contended acquisitions to all ones.
+//
+func (monitor *ContentionMonitor) ContentionRatio() float64 {
+	acquisitions := atomic.LoadInt64(&monitor.acquisitions)
+	if acquisitions == 0 {
+		return 0
+	}
+
+	return float64(atomic.LoadInt64(&monitor.contentions)) /
+		float64(acquisitions)
+}
+
+// RecommendedConcurrencyLevel ...
+//
+// It returns a power of two close to the current level: doubled if more than
+// 10% of acquisitions are contended, halved (but not lower than
+// the runtime.GOMAXPROCS() value) if less than 1% of them are contended,
+// and the same one otherwise.
+//
+func (monitor *ContentionMonitor) RecommendedConcurrencyLevel(current int) int {
+	level := ceilPowerOfTwo(current)
+	switch ratio := monitor.ContentionRatio(); {
+	case ratio > 0.1:
+		return level * 2
+	case ratio < 0.01 && level/2 >= runtime.GOMAXPROCS(0):
+		return level / 2
+	default:
+		return level
+	}
+}
+
+// Reset ...
+//
+// It resets the measured statistics, e.g. after applying of a recommended
+// level.
+//
+func (monitor *ContentionMonitor) Reset() {
+	atomic.StoreInt64(&monitor.acquisitions, 0)
+	atomic.StoreInt64(&monitor.contentions, 0)
+}
@@ -0,0 +1,131 @@
+package hashmap
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentionMonitor_OnLockContention(test *testing.T) {
+	monitor := NewContentionMonitor(time.Millisecond)
+	monitor.OnLockContention(time.Microsecond)
+	monitor.OnLockContention(time.Millisecond)
+	monitor.OnLockContention(time.Second)
+
+	assert.Equal(test, int64(3), monitor.acquisitions)
+	assert.Equal(test, int64(1), monitor.contentions)
+}
+
+func TestContentionMonitor_ContentionRatio(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		waits []time.Duration
+		want  float64
+	}{
+		{
+			name:  "without acquisitions",
+			waits: nil,
+			want:  0,
+		},
+		{
+			name:  "without contentions",
+			waits: []time.Duration{time.Microsecond, time.Microsecond},
+			want:  0,
+		},
+		{
+			name: "with contentions",
+			waits: []time.Duration{
+				time.Microsecond,
+				time.Second,
+				time.Microsecond,
+				time.Second,
+			},
+			want: 0.5,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			monitor := NewContentionMonitor(time.Millisecond)
+			for _, wait := range data.waits {
+				monitor.OnLockContention(wait)
+			}
+			got := monitor.ContentionRatio()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestContentionMonitor_RecommendedConcurrencyLevel(test *testing.T) {
+	minimalLevel := ceilPowerOfTwo(runtime.GOMAXPROCS(0))
+
+	type args struct {
+		current int
+	}
+
+	for _, data := range []struct {
+		name        string
+		contentions int
+		args        args
+		want        int
+	}{
+		{
+			name:        "with a high contention",
+			contentions: 20,
+			args: args{
+				current: 23,
+			},
+			want: 64,
+		},
+		{
+			name:        "with a moderate contention",
+			contentions: 5,
+			args: args{
+				current: 32,
+			},
+			want: 32,
+		},
+		{
+			name:        "with a low contention",
+			contentions: 0,
+			args: args{
+				current: minimalLevel * 4,
+			},
+			want: minimalLevel * 2,
+		},
+		{
+			name:        "with a low contention and the minimal level",
+			contentions: 0,
+			args: args{
+				current: minimalLevel,
+			},
+			want: minimalLevel,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			monitor := NewContentionMonitor(time.Millisecond)
+			for i := 0; i < 100; i++ {
+				wait := time.Microsecond
+				if i < data.contentions {
+					wait = time.Second
+				}
+
+				monitor.OnLockContention(wait)
+			}
+			got := monitor.RecommendedConcurrencyLevel(data.args.current)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestContentionMonitor_Reset(test *testing.T) {
+	monitor := NewContentionMonitor(time.Millisecond)
+	monitor.OnLockContention(time.Second)
+	monitor.Reset()
+
+	assert.Equal(test, int64(0), monitor.acquisitions)
+	assert.Equal(test, int64(0), monitor.contentions)
+	assert.Equal(test, 0.0, monitor.ContentionRatio())
+}
@@ -0,0 +1,272 @@
+package hashmap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// it's a lock that is able to give up its acquisition when a context is done
+type contextLocker interface {
+	sync.Locker
+
+	LockContext(ctx context.Context) error
+}
+
+const (
+	contextLockWriterBit = 1 << iota
+	contextLockWaitingWriterBit
+	contextLockReaderUnit
+)
+
+// it's a readers-writer lock, whose acquisition is abandoned cleanly when
+// a context is done: unlike a goroutine blocked on the sync.RWMutex.Lock()
+// method, an abandoned acquisition doesn't stay queued, so it doesn't block
+// new readers after the caller has given up and doesn't leak a goroutine;
+// the zero value is an unlocked lock
+//
+// like the sync.RWMutex structure, it acquires an uncontended lock by a single
+// atomic operation, so readers don't serialize each other; the mutex
+// and the channel are used only by the waiting goroutines
+//
+// like the sync.RWMutex structure, it prefers writers: while a writer waits,
+// new readers wait too, so writers can't be starved by readers
+//
+type contextRWMutex struct {
+	// it's accessed atomically and consists of the writer bit, the waiting
+	// writer bit and the reader count multiplied by contextLockReaderUnit
+	state int32
+	// it's a number of the waiting goroutines; it's accessed atomically
+	waiterCount int32
+
+	waiting        sync.Mutex
+	waitingWriters int
+	// it's closed on each release of the lock to wake up waiters;
+	// it's created lazily by the first waiter
+	changed chan struct{}
+}
+
+// Lock ...
+func (lock *contextRWMutex) Lock() {
+	if atomic.CompareAndSwapInt32(&lock.state, 0, contextLockWriterBit) {
+		return
+	}
+
+	// the background context is never done, so the acquisition can't fail
+	lock.acquireSlowly(context.Background(), true) // nolint: errcheck
+}
+
+// LockContext ...
+func (lock *contextRWMutex) LockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if atomic.CompareAndSwapInt32(&lock.state, 0, contextLockWriterBit) {
+		return nil
+	}
+
+	return lock.acquireSlowly(ctx, true)
+}
+
+// Unlock ...
+func (lock *contextRWMutex) Unlock() {
+	for {
+		state := atomic.LoadInt32(&lock.state)
+		if state&contextLockWriterBit == 0 {
+			panic("hashmap: unlock of an unlocked lock")
+		}
+
+		newState := state &^ contextLockWriterBit
+		if atomic.CompareAndSwapInt32(&lock.state, state, newState) {
+			break
+		}
+	}
+
+	lock.notify()
+}
+
+// RLock ...
+func (lock *contextRWMutex) RLock() {
+	if lock.addReader() {
+		return
+	}
+
+	// the background context is never done, so the acquisition can't fail
+	lock.acquireSlowly(context.Background(), false) // nolint: errcheck
+}
+
+// RLockContext ...
+func (lock *contextRWMutex) RLockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if lock.addReader() {
+		return nil
+	}
+
+	return lock.acquireSlowly(ctx, false)
+}
+
+// RUnlock ...
+func (lock *contextRWMutex) RUnlock() {
+	state := atomic.AddInt32(&lock.state, -contextLockReaderUnit)
+	if state < 0 {
+		panic("hashmap: read unlock of an unlocked lock")
+	}
+
+	// only writers wait for readers
+	if state < contextLockReaderUnit {
+		lock.notify()
+	}
+}
+
+// RLocker ...
+func (lock *contextRWMutex) RLocker() contextLocker {
+	return (*contextReadLocker)(lock)
+}
+
+// unlike the contextRWMutex.tryRLock() method, it acquires the lock
+// by a single atomic operation, but it can't be called under the waiting lock
+func (lock *contextRWMutex) addReader() bool {
+	state := atomic.AddInt32(&lock.state, contextLockReaderUnit)
+	if state&(contextLockWriterBit|contextLockWaitingWriterBit) == 0 {
+		return true
+	}
+
+	// the counted reader may have prevented a writer from the acquisition,
+	// so the latter should be woken up
+	lock.RUnlock()
+	return false
+}
+
+func (lock *contextRWMutex) tryLock() bool {
+	for {
+		// the waiting writer bit is ignored, because writers compete
+		// with each other
+		state := atomic.LoadInt32(&lock.state)
+		if state&^contextLockWaitingWriterBit != 0 {
+			return false
+		}
+
+		newState := state | contextLockWriterBit
+		if atomic.CompareAndSwapInt32(&lock.state, state, newState) {
+			return true
+		}
+	}
+}
+
+func (lock *contextRWMutex) tryRLock() bool {
+	for {
+		state := atomic.LoadInt32(&lock.state)
+		if state&(contextLockWriterBit|contextLockWaitingWriterBit) != 0 {
+			return false
+		}
+
+		newState := state + contextLockReaderUnit
+		if atomic.CompareAndSwapInt32(&lock.state, state, newState) {
+			return true
+		}
+	}
+}
+
+func (lock *contextRWMutex) acquireSlowly(
+	ctx context.Context,
+	exclusive bool,
+) error {
+	tryAcquire := lock.tryRLock
+	if exclusive {
+		tryAcquire = lock.tryLock
+	}
+
+	lock.waiting.Lock()
+	defer lock.waiting.Unlock()
+
+	// the waiter count is increased before the acquisition attempt, so a release
+	// after the failed attempt will see it and wake up the waiter
+	atomic.AddInt32(&lock.waiterCount, 1)
+	defer atomic.AddInt32(&lock.waiterCount, -1)
+
+	if exclusive {
+		lock.addWaitingWriters(1)
+		defer lock.addWaitingWriters(-1)
+	}
+
+	for {
+		if tryAcquire() {
+			return nil
+		}
+
+		if lock.changed == nil {
+			lock.changed = make(chan struct{})
+		}
+
+		changed := lock.changed
+		lock.waiting.Unlock()
+
+		select {
+		case <-changed:
+			lock.waiting.Lock()
+		case <-ctx.Done():
+			lock.waiting.Lock()
+			// readers that wait for the writer should be woken up
+			if exclusive {
+				lock.closeChanged()
+			}
+
+			return ctx.Err()
+		}
+	}
+}
+
+// it should be called under the waiting lock
+func (lock *contextRWMutex) addWaitingWriters(delta int) {
+	lock.waitingWriters += delta
+	for {
+		state := atomic.LoadInt32(&lock.state)
+		newState := state &^ contextLockWaitingWriterBit
+		if lock.waitingWriters != 0 {
+			newState |= contextLockWaitingWriterBit
+		}
+
+		if atomic.CompareAndSwapInt32(&lock.state, state, newState) {
+			return
+		}
+	}
+}
+
+func (lock *contextRWMutex) notify() {
+	if atomic.LoadInt32(&lock.waiterCount) == 0 {
+		return
+	}
+
+	lock.waiting.Lock()
+	defer lock.waiting.Unlock()
+
+	lock.closeChanged()
+}
+
+// it should be called under the waiting lock
+func (lock *contextRWMutex) closeChanged() {
+	if lock.changed == nil {
+		return
+	}
+
+	close(lock.changed)
+	lock.changed = nil
+}
+
+type contextReadLocker contextRWMutex
+
+func (locker *contextReadLocker) Lock() {
+	(*contextRWMutex)(locker).RLock()
+}
+
+func (locker *contextReadLocker) LockContext(ctx context.Context) error {
+	return (*contextRWMutex)(locker).RLockContext(ctx)
+}
+
+func (locker *contextReadLocker) Unlock() {
+	(*contextRWMutex)(locker).RUnlock()
+}
@@ -0,0 +1,47 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+)
+
+type readLocker interface {
+	RLock()
+	RUnlock()
+}
+
+func BenchmarkContextRWMutex_parallelRead(benchmark *testing.B) {
+	for _, data := range []struct {
+		name string
+		lock readLocker
+	}{
+		{
+			name: "sync.RWMutex",
+			lock: new(sync.RWMutex),
+		},
+		{
+			name: "contextRWMutex",
+			lock: new(contextRWMutex),
+		},
+	} {
+		benchmark.Run(data.name, func(benchmark *testing.B) {
+			hashMap := NewHashMap()
+			for i := 0; i < sizeForSyncBench; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+
+			benchmark.ResetTimer()
+
+			benchmark.RunParallel(func(pb *testing.PB) {
+				var key int
+				for pb.Next() {
+					data.lock.RLock()
+					hashMap.Get(IntKey(key % sizeForSyncBench))
+					data.lock.RUnlock()
+
+					key++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package hashmap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextRWMutex_LockContext(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		makeCtx    func() (context.Context, context.CancelFunc)
+		lockBefore func(lock *contextRWMutex)
+		wantErr    error
+		wantLocked bool
+	}{
+		{
+			name: "with the background context",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			lockBefore: func(lock *contextRWMutex) {},
+			wantErr:    nil,
+			wantLocked: true,
+		},
+		{
+			name: "with the free lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			lockBefore: func(lock *contextRWMutex) {},
+			wantErr:    nil,
+			wantLocked: true,
+		},
+		{
+			name: "with the cancelled context",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx, cancel
+			},
+			lockBefore: func(lock *contextRWMutex) {},
+			wantErr:    context.Canceled,
+			wantLocked: false,
+		},
+		{
+			name: "with the lock busy by a writer",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockBefore: func(lock *contextRWMutex) { lock.Lock() },
+			wantErr:    context.DeadlineExceeded,
+			wantLocked: true,
+		},
+		{
+			name: "with the lock busy by a reader",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockBefore: func(lock *contextRWMutex) { lock.RLock() },
+			wantErr:    context.DeadlineExceeded,
+			wantLocked: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var lock contextRWMutex
+			data.lockBefore(&lock)
+
+			ctx, cancel := data.makeCtx()
+			defer cancel()
+
+			gotErr := lock.LockContext(ctx)
+
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantLocked, isWriteLocked(&lock))
+			assert.Zero(test, lock.waitingWriters)
+			assert.Zero(test, lock.waiterCount)
+		})
+	}
+}
+
+func TestContextRWMutex_RLockContext(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		lockBefore func(lock *contextRWMutex)
+		wantErr    error
+		wantCount  int
+	}{
+		{
+			name:       "with the free lock",
+			lockBefore: func(lock *contextRWMutex) {},
+			wantErr:    nil,
+			wantCount:  1,
+		},
+		{
+			name:       "with the lock busy by a reader",
+			lockBefore: func(lock *contextRWMutex) { lock.RLock() },
+			wantErr:    nil,
+			wantCount:  2,
+		},
+		{
+			name:       "with the lock busy by a writer",
+			lockBefore: func(lock *contextRWMutex) { lock.Lock() },
+			wantErr:    context.DeadlineExceeded,
+			wantCount:  0,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var lock contextRWMutex
+			data.lockBefore(&lock)
+
+			ctx, cancel :=
+				context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+
+			gotErr := lock.RLockContext(ctx)
+
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantCount, readLockCount(&lock))
+			assert.Zero(test, lock.waiterCount)
+		})
+	}
+}
+
+func TestContextRWMutex_waitingWriter(test *testing.T) {
+	var lock contextRWMutex
+	lock.RLock()
+
+	writerCtx, cancelWriter := context.WithCancel(context.Background())
+	defer cancelWriter()
+
+	writerErrs := make(chan error)
+	go func() { writerErrs <- lock.LockContext(writerCtx) }()
+	waitForWaitingWriters(&lock, 1)
+
+	// the waiting writer blocks new readers
+	readerCtx, cancelReader :=
+		context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelReader()
+
+	assert.Equal(test, context.DeadlineExceeded, lock.RLockContext(readerCtx))
+
+	// the abandoned writer doesn't block new readers anymore
+	cancelWriter()
+	assert.Equal(test, context.Canceled, <-writerErrs)
+
+	lock.RLock()
+	assert.Equal(test, 2, readLockCount(&lock))
+	assert.Zero(test, lock.waitingWriters)
+	assert.Zero(test, lock.state&contextLockWaitingWriterBit)
+}
+
+func TestContextRWMutex_release(test *testing.T) {
+	var lock contextRWMutex
+	lock.Lock()
+
+	readerErrs := make(chan error)
+	go func() { readerErrs <- lock.RLockContext(context.Background()) }()
+	go func() { readerErrs <- lock.RLockContext(context.Background()) }()
+
+	lock.Unlock()
+	assert.NoError(test, <-readerErrs)
+	assert.NoError(test, <-readerErrs)
+
+	writerErrs := make(chan error)
+	go func() { writerErrs <- lock.LockContext(context.Background()) }()
+	waitForWaitingWriters(&lock, 1)
+
+	lock.RUnlock()
+	lock.RUnlock()
+	assert.NoError(test, <-writerErrs)
+	assert.True(test, isWriteLocked(&lock))
+}
+
+func TestContextRWMutex_unlockOfUnlocked(test *testing.T) {
+	var lock contextRWMutex
+
+	assert.Panics(test, lock.Unlock)
+	assert.Panics(test, lock.RUnlock)
+}
+
+func TestContextRWMutex_exclusion(test *testing.T) {
+	const goroutineCount = 8
+	const iterationCount = 1000
+
+	var lock contextRWMutex
+	var readerCount, writerCount int32
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func(goroutine int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < iterationCount; j++ {
+				ctx, cancel :=
+					context.WithTimeout(context.Background(), time.Microsecond)
+				if (goroutine+j)%2 == 0 {
+					if lock.LockContext(ctx) == nil {
+						count := atomic.AddInt32(&writerCount, 1)
+						assert.Equal(test, int32(1), count)
+						assert.Zero(test, atomic.LoadInt32(&readerCount))
+
+						atomic.AddInt32(&writerCount, -1)
+						lock.Unlock()
+					}
+				} else {
+					if lock.RLockContext(ctx) == nil {
+						atomic.AddInt32(&readerCount, 1)
+						assert.Zero(test, atomic.LoadInt32(&writerCount))
+
+						atomic.AddInt32(&readerCount, -1)
+						lock.RUnlock()
+					}
+				}
+				cancel()
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Zero(test, lock.state)
+	assert.Zero(test, lock.waiterCount)
+	assert.Zero(test, lock.waitingWriters)
+}
+
+func waitForWaitingWriters(lock *contextRWMutex, count int) {
+	for {
+		lock.waiting.Lock()
+		waitingWriters := lock.waitingWriters
+		lock.waiting.Unlock()
+
+		if waitingWriters == count {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func isWriteLocked(lock *contextRWMutex) bool {
+	return atomic.LoadInt32(&lock.state)&contextLockWriterBit != 0
+}
+
+func readLockCount(lock *contextRWMutex) int {
+	return int(atomic.LoadInt32(&lock.state) / contextLockReaderUnit)
+}
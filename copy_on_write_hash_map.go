@@ -0,0 +1,158 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnWriteHashMap ...
+//
+// It's safe for concurrent access and is optimized for read-mostly
+// workloads. It publishes an immutable instance of the HashMap structure,
+// so reading doesn't use any lock. Each modification clones the published
+// map, modifies the clone and publishes it instead.
+//
+// Concurrent modifications are coalesced: ones that arrive while the previous
+// clone is being built are applied together to a single next clone. If one
+// of them panics (e.g. in the handler of the Batch() method), the clone isn't
+// published, and the panic is passed on to all their callers.
+//
+type CopyOnWriteHashMap struct {
+	current   atomic.Value // it stores the *HashMap type
+	writeLock sync.Mutex
+	batchLock sync.Mutex
+	batch     *copyOnWriteBatch
+}
+
+type copyOnWriteBatch struct {
+	modifications []func(hashMap *HashMap)
+	done          chan struct{}
+	// they're set before closing of the done channel if a modification panics
+	panicked   bool
+	panicValue interface{}
+}
+
+// NewCopyOnWriteHashMap ...
+//
+// Options are applied to the published map.
+//
+func NewCopyOnWriteHashMap(options ...Option) *CopyOnWriteHashMap {
+	hashMap := new(CopyOnWriteHashMap)
+	hashMap.current.Store(NewHashMap(options...))
+
+	return hashMap
+}
+
+// Get ...
+func (hashMap *CopyOnWriteHashMap) Get(key Key) (value interface{}, ok bool) {
+	return hashMap.load().Get(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It randomizes of iteration order.
+//
+// It iterates over a snapshot, so modifications during iteration
+// don't affect it.
+//
+func (hashMap *CopyOnWriteHashMap) Iterate(handler Handler) bool {
+	return hashMap.load().Iterate(handler)
+}
+
+// Set ...
+func (hashMap *CopyOnWriteHashMap) Set(key Key, value interface{}) {
+	hashMap.modify(func(innerMap *HashMap) { innerMap.Set(key, value) })
+}
+
+// Delete ...
+func (hashMap *CopyOnWriteHashMap) Delete(key Key) {
+	hashMap.modify(func(innerMap *HashMap) { innerMap.Delete(key) })
+}
+
+// Size ...
+func (hashMap *CopyOnWriteHashMap) Size() int {
+	return hashMap.load().Size()
+}
+
+// Batch ...
+//
+// It applies all modifications made by the handler to a single clone
+// and publishes it at once, so readers see either none or all of them.
+//
+// The passed storage is valid only inside the handler.
+//
+func (hashMap *CopyOnWriteHashMap) Batch(handler func(storage Storage)) {
+	hashMap.modify(func(innerMap *HashMap) { handler(innerMap) })
+}
+
+func (hashMap *CopyOnWriteHashMap) update(key Key, handler updateHandler) {
+	hashMap.modify(func(innerMap *HashMap) { innerMap.update(key, handler) })
+}
+
+// it adds the modification to the pending batch; the caller that has created
+// the batch commits it, the others wait for the commit
+func (hashMap *CopyOnWriteHashMap) modify(modification func(hashMap *HashMap)) {
+	hashMap.batchLock.Lock()
+	batch := hashMap.batch
+	isCommitter := batch == nil
+	if isCommitter {
+		batch = &copyOnWriteBatch{done: make(chan struct{})}
+		hashMap.batch = batch
+	}
+	batch.modifications = append(batch.modifications, modification)
+	hashMap.batchLock.Unlock()
+
+	if !isCommitter {
+		<-batch.done
+		if batch.panicked {
+			panic(batch.panicValue)
+		}
+
+		return
+	}
+
+	hashMap.commit(batch)
+}
+
+func (hashMap *CopyOnWriteHashMap) load() *HashMap {
+	return hashMap.current.Load().(*HashMap)
+}
+
+func (hashMap *CopyOnWriteHashMap) commit(batch *copyOnWriteBatch) {
+	// the waiters are woken up even if a modification panics,
+	// but then they get the panic instead of the publishing
+	var published bool
+	defer func() {
+		if published {
+			return
+		}
+
+		// the recovered value can be nil (see the panic(nil) call before Go 1.21),
+		// so the panic is detected by the flag
+		panicValue := recover()
+		batch.panicked, batch.panicValue = true, panicValue
+		close(batch.done)
+
+		panic(panicValue)
+	}()
+
+	// while the previous commit is in progress, the batch is being filled
+	hashMap.writeLock.Lock()
+	defer hashMap.writeLock.Unlock()
+
+	// detach the batch, so next modifications go to a new one
+	hashMap.batchLock.Lock()
+	hashMap.batch = nil
+	hashMap.batchLock.Unlock()
+
+	newHashMap := hashMap.load().clone()
+	for _, modification := range batch.modifications {
+		modification(newHashMap)
+	}
+
+	hashMap.current.Store(newHashMap)
+	published = true
+	close(batch.done)
+}
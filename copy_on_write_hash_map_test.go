@@ -0,0 +1,231 @@
+package hashmap
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCopyOnWriteHashMap(test *testing.T) {
+	got := NewCopyOnWriteHashMap(WithInitialCapacity(23))
+
+	current := got.load()
+	assert.Equal(test, 23, len(current.buckets))
+	assert.Equal(test, 0, current.size)
+	assert.Nil(test, got.batch)
+}
+
+func TestCopyOnWriteHashMap(test *testing.T) {
+	hashMap := NewCopyOnWriteHashMap()
+	hashMap.Set(IntKey(1), "one")
+	hashMap.Set(IntKey(2), "two")
+	hashMap.Set(IntKey(1), "uno")
+	hashMap.Delete(IntKey(2))
+	hashMap.Delete(IntKey(3))
+
+	value, ok := hashMap.Get(IntKey(1))
+	assert.Equal(test, "uno", value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get(IntKey(2))
+	assert.Nil(test, value)
+	assert.False(test, ok)
+
+	assert.Equal(test, 1, hashMap.Size())
+}
+
+func TestCopyOnWriteHashMap_Iterate(test *testing.T) {
+	test.Run("with full iteration", func(test *testing.T) {
+		hashMap := NewCopyOnWriteHashMap()
+		for i := 0; i < 10; i++ {
+			hashMap.Set(IntKey(i), i*i)
+		}
+
+		items := make(map[Key]interface{})
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			items[key] = value
+			return true
+		})
+
+		assert.True(test, ok)
+		assert.Len(test, items, 10)
+		for i := 0; i < 10; i++ {
+			assert.Equal(test, i*i, items[IntKey(i)])
+		}
+	})
+
+	test.Run("with interrupted iteration", func(test *testing.T) {
+		hashMap := NewCopyOnWriteHashMap()
+		for i := 0; i < 10; i++ {
+			hashMap.Set(IntKey(i), i*i)
+		}
+
+		var count int
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			count++
+			return count < 3
+		})
+
+		assert.False(test, ok)
+		assert.Equal(test, 3, count)
+	})
+
+	test.Run("with modification during iteration", func(test *testing.T) {
+		hashMap := NewCopyOnWriteHashMap()
+		for i := 0; i < 10; i++ {
+			hashMap.Set(IntKey(i), i*i)
+		}
+
+		var count int
+		hashMap.Iterate(func(key Key, value interface{}) bool {
+			hashMap.Delete(key)
+			hashMap.Set(IntKey(100+count), count)
+
+			count++
+			return true
+		})
+
+		assert.Equal(test, 10, count)
+		assert.Equal(test, 10, hashMap.Size())
+	})
+}
+
+func TestCopyOnWriteHashMap_snapshot(test *testing.T) {
+	hashMap := NewCopyOnWriteHashMap()
+	hashMap.Set(IntKey(1), "one")
+
+	snapshot := hashMap.load()
+	hashMap.Set(IntKey(1), "uno")
+	hashMap.Set(IntKey(2), "two")
+
+	value, _ := snapshot.Get(IntKey(1))
+	assert.Equal(test, "one", value)
+	assert.Equal(test, 1, snapshot.Size())
+}
+
+func TestCopyOnWriteHashMap_Batch(test *testing.T) {
+	hashMap := NewCopyOnWriteHashMap()
+	hashMap.Set(IntKey(1), "one")
+
+	snapshot := hashMap.load()
+	hashMap.Batch(func(storage Storage) {
+		storage.Set(IntKey(2), "two")
+		storage.Set(IntKey(3), "three")
+		storage.Delete(IntKey(1))
+
+		// modifications aren't published until the end of the batch
+		assert.Equal(test, snapshot, hashMap.load())
+	})
+
+	assert.Equal(test, 2, hashMap.Size())
+	_, ok := hashMap.Get(IntKey(1))
+	assert.False(test, ok)
+	value, _ := hashMap.Get(IntKey(3))
+	assert.Equal(test, "three", value)
+}
+
+func TestCopyOnWriteHashMap_panic(test *testing.T) {
+	hashMap := NewCopyOnWriteHashMap()
+
+	// the first commit holds the write lock until the next batch is filled
+	started, release := make(chan struct{}), make(chan struct{})
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		hashMap.Batch(func(storage Storage) {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	panics := make(chan interface{}, 2)
+	modify := func(modification func()) {
+		defer func() { panics <- recover() }()
+		modification()
+	}
+	go modify(func() {
+		hashMap.Batch(func(storage Storage) { panic("failure") })
+	})
+	waitForCopyOnWriteBatch(hashMap, 1)
+	go modify(func() { hashMap.Set(IntKey(1), "one") })
+	waitForCopyOnWriteBatch(hashMap, 2)
+
+	close(release)
+	<-firstDone
+
+	assert.Equal(test, "failure", <-panics)
+	assert.Equal(test, "failure", <-panics)
+
+	_, ok := hashMap.Get(IntKey(1))
+	assert.False(test, ok)
+	assert.Equal(test, 0, hashMap.Size())
+
+	// the map stays usable after the panic
+	hashMap.Set(IntKey(2), "two")
+	gotValue, gotOk := hashMap.Get(IntKey(2))
+	assert.Equal(test, "two", gotValue)
+	assert.True(test, gotOk)
+}
+
+func TestCopyOnWriteHashMap_concurrency(test *testing.T) {
+	const writerCount = 10
+	const writesPerWriter = 100
+
+	hashMap := NewCopyOnWriteHashMap()
+	hashMap.Set(StringKey("counter"), 0)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < writerCount; i++ {
+		waitGroup.Add(2)
+
+		go func(writer int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Set(StringKey(fmt.Sprint(writer, j)), j)
+				updateStorage(
+					hashMap,
+					StringKey("counter"),
+					func(value interface{}, ok bool) (interface{}, bool) {
+						return value.(int) + 1, true
+					},
+				)
+			}
+		}(i)
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Get(StringKey("counter"))
+				hashMap.Iterate(func(key Key, value interface{}) bool { return true })
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	counter, _ := hashMap.Get(StringKey("counter"))
+	assert.Equal(test, writerCount*writesPerWriter, counter)
+	assert.Equal(test, writerCount*writesPerWriter+1, hashMap.Size())
+}
+
+// it waits until the pending batch contains the count of modifications
+func waitForCopyOnWriteBatch(hashMap *CopyOnWriteHashMap, count int) {
+	for {
+		hashMap.batchLock.Lock()
+		var pending int
+		if hashMap.batch != nil {
+			pending = len(hashMap.batch.modifications)
+		}
+		hashMap.batchLock.Unlock()
+
+		if pending == count {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
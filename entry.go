@@ -0,0 +1,184 @@
+package hashmap
+
+// EntryHandler ...
+type EntryHandler func(entry *Entry)
+
+// it's implemented by storages that are able to provide an entry by a key
+// with their own synchronization
+type entryProvider interface {
+	WithEntry(key Key, handler EntryHandler)
+}
+
+// Entry ...
+//
+// It's a handle of an item by a key. An entry of the HashMap structure
+// remembers a position of the item found on its creation, so its operations
+// don't search the item again. An entry of another storage uses its Get(),
+// Set() and Delete() methods.
+//
+// The entry is valid only until the storage is modified not via the entry.
+//
+type Entry struct {
+	key    Key
+	exists bool
+
+	// it's set for an entry of the HashMap structure
+	hashMap     *HashMap
+	hash        uint64
+	index       int
+	probeLength int
+
+	// it's set for an entry of another storage
+	storage Storage
+	value   interface{}
+}
+
+func newHashMapEntry(hashMap *HashMap, key Key) *Entry {
+	entry := &Entry{key: key, hashMap: hashMap}
+	entry.locate()
+
+	return entry
+}
+
+func newStorageEntry(storage Storage, key Key) *Entry {
+	value, exists := storage.Get(key)
+	return &Entry{key: key, exists: exists, storage: storage, value: value}
+}
+
+// Key ...
+func (entry *Entry) Key() Key {
+	return entry.key
+}
+
+// Exists ...
+func (entry *Entry) Exists() bool {
+	return entry.exists
+}
+
+// Value ...
+//
+// It returns nil if the item doesn't exist.
+//
+func (entry *Entry) Value() interface{} {
+	if !entry.exists {
+		return nil
+	}
+	if entry.hashMap == nil {
+		return entry.value
+	}
+
+	return entry.hashMap.buckets[entry.index].value
+}
+
+// Set ...
+func (entry *Entry) Set(value interface{}) {
+	if entry.hashMap == nil {
+		entry.storage.Set(entry.key, value)
+		entry.value, entry.exists = value, true
+
+		return
+	}
+
+	hashMap := entry.hashMap
+	if hashMap.config.instrumentation != nil {
+		hashMap.config.instrumentation.OnSet(entry.probeLength)
+	}
+	if entry.exists {
+		hashMap.buckets[entry.index].value = value
+		return
+	}
+
+	rehashed := hashMap.insertAt(entry.index, entry.probeLength, bucket{
+		key:      entry.key,
+		value:    value,
+		hash:     entry.hash,
+		occupied: true,
+	})
+	entry.exists = true
+	// rehashing moves items, so the position should be found again
+	if rehashed {
+		entry.locate()
+	}
+}
+
+// Delete ...
+//
+// It does nothing if the item doesn't exist.
+//
+func (entry *Entry) Delete() {
+	if !entry.exists {
+		return
+	}
+	if entry.hashMap == nil {
+		entry.storage.Delete(entry.key)
+		entry.value, entry.exists = nil, false
+
+		return
+	}
+
+	if entry.hashMap.config.instrumentation != nil {
+		entry.hashMap.config.instrumentation.OnDelete(entry.probeLength)
+	}
+	entry.hashMap.deleteAt(entry.index)
+	// backward shifting moves the following items, so a position
+	// for the next setting should be found again
+	entry.locate()
+}
+
+// OrInsert ...
+//
+// It sets the value if the item doesn't exist and returns the current value.
+//
+func (entry *Entry) OrInsert(value interface{}) interface{} {
+	if !entry.exists {
+		entry.Set(value)
+	}
+
+	return entry.Value()
+}
+
+// OrInsertWith ...
+//
+// It's the same as the OrInsert() method, but the value is made
+// by the function only if the item doesn't exist.
+//
+func (entry *Entry) OrInsertWith(makeValue func() interface{}) interface{} {
+	if !entry.exists {
+		entry.Set(makeValue())
+	}
+
+	return entry.Value()
+}
+
+// AndModify ...
+//
+// It replaces the value by the function result if the item exists.
+// It returns the entry itself, so calls can be chained (e.g. with
+// the OrInsert() method).
+//
+func (entry *Entry) AndModify(
+	modify func(value interface{}) interface{},
+) *Entry {
+	if entry.exists {
+		entry.Set(modify(entry.Value()))
+	}
+
+	return entry
+}
+
+func (entry *Entry) locate() {
+	entry.hash = entry.hashMap.hash(entry.key)
+	entry.index, entry.probeLength, entry.exists =
+		entry.hashMap.find(entry.key, entry.hash)
+}
+
+// if the storage doesn't implement the entryProvider interface, the entry
+// uses its Get(), Set() and Delete() methods, i.e. it isn't atomic
+func withStorageEntry(storage Storage, key Key, handler EntryHandler) {
+	if provider, ok := storage.(entryProvider); ok {
+		provider.WithEntry(key, handler)
+		return
+	}
+
+	handler(newStorageEntry(storage, key))
+}
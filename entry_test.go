@@ -0,0 +1,274 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEntry(test *testing.T) {
+	type result struct {
+		Value  interface{}
+		Exists bool
+	}
+
+	for _, data := range []struct {
+		name       string
+		makeMap    func() *HashMap
+		run        func(entry *Entry) interface{}
+		want       interface{}
+		wantResult result
+		wantSize   int
+	}{
+		{
+			name:    "getting by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				return entry.Value()
+			},
+			want:       nil,
+			wantResult: result{nil, false},
+			wantSize:   0,
+		},
+		{
+			name: "getting by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), "one")
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				return entry.Value()
+			},
+			want:       "one",
+			wantResult: result{"one", true},
+			wantSize:   1,
+		},
+		{
+			name:    "setting by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				entry.Set("two")
+				return entry.Value()
+			},
+			want:       "two",
+			wantResult: result{"two", true},
+			wantSize:   1,
+		},
+		{
+			name: "setting by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), "one")
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				entry.Set("two")
+				return entry.Value()
+			},
+			want:       "two",
+			wantResult: result{"two", true},
+			wantSize:   1,
+		},
+		{
+			name:    "deleting by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				entry.Delete()
+				return entry.Exists()
+			},
+			want:       false,
+			wantResult: result{nil, false},
+			wantSize:   0,
+		},
+		{
+			name: "deleting by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), "one")
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				entry.Delete()
+				return entry.Exists()
+			},
+			want:       false,
+			wantResult: result{nil, false},
+			wantSize:   0,
+		},
+		{
+			name:    "inserting by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				return entry.OrInsert("two")
+			},
+			want:       "two",
+			wantResult: result{"two", true},
+			wantSize:   1,
+		},
+		{
+			name: "inserting by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), "one")
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				return entry.OrInsert("two")
+			},
+			want:       "one",
+			wantResult: result{"one", true},
+			wantSize:   1,
+		},
+		{
+			name:    "inserting with a function by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				return entry.OrInsertWith(func() interface{} { return "two" })
+			},
+			want:       "two",
+			wantResult: result{"two", true},
+			wantSize:   1,
+		},
+		{
+			name: "inserting with a function by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), "one")
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				return entry.OrInsertWith(func() interface{} {
+					panic("the function shouldn't be called")
+				})
+			},
+			want:       "one",
+			wantResult: result{"one", true},
+			wantSize:   1,
+		},
+		{
+			name:    "modifying by a nonexistent key",
+			makeMap: func() *HashMap { return NewHashMap() },
+			run: func(entry *Entry) interface{} {
+				return entry.
+					AndModify(func(value interface{}) interface{} {
+						return value.(int) + 1
+					}).
+					OrInsert(0)
+			},
+			want:       0,
+			wantResult: result{0, true},
+			wantSize:   1,
+		},
+		{
+			name: "modifying by an existing key",
+			makeMap: func() *HashMap {
+				hashMap := NewHashMap()
+				hashMap.Set(IntKey(23), 5)
+
+				return hashMap
+			},
+			run: func(entry *Entry) interface{} {
+				return entry.
+					AndModify(func(value interface{}) interface{} {
+						return value.(int) + 1
+					}).
+					OrInsert(0)
+			},
+			want:       6,
+			wantResult: result{6, true},
+			wantSize:   1,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := data.makeMap()
+			entry := hashMap.Entry(IntKey(23))
+			got := data.run(entry)
+
+			gotValue, gotOk := hashMap.Get(IntKey(23))
+			assert.Equal(test, data.want, got)
+			assert.Equal(test, data.wantResult, result{gotValue, gotOk})
+			assert.Equal(test, IntKey(23), entry.Key())
+			assert.Equal(test, gotOk, entry.Exists())
+			assert.Equal(test, data.wantSize, hashMap.Size())
+		})
+	}
+}
+
+func TestEntry_singleSearch(test *testing.T) {
+	key := new(MockKey)
+	key.On("Hash").Return(5).Once()
+
+	hashMap := NewHashMap()
+	entry := hashMap.Entry(key)
+	entry.OrInsert(1)
+	entry.AndModify(func(value interface{}) interface{} {
+		return value.(int) + 1
+	})
+	entry.Set(entry.Value().(int) * 10)
+
+	mock.AssertExpectationsForObjects(test, key)
+	assert.Equal(test, 20, entry.Value())
+	assert.Equal(test, 1, hashMap.Size())
+}
+
+func TestEntry_withRehashing(test *testing.T) {
+	hashMap := NewHashMap(WithInitialCapacity(2))
+	hashMap.Set(IntKey(1), "one")
+
+	entry := hashMap.Entry(IntKey(2))
+	entry.Set("two")
+	entry.Set("three")
+
+	assert.Len(test, hashMap.buckets, 4)
+	assert.Equal(test, "three", entry.Value())
+	assert.Equal(test, 2, hashMap.Size())
+
+	gotValue, gotOk := hashMap.Get(IntKey(2))
+	assert.Equal(test, "three", gotValue)
+	assert.True(test, gotOk)
+}
+
+func TestEntry_withShifting(test *testing.T) {
+	hashMap := NewHashMap(WithInitialCapacity(8))
+	hashMap.seed = 0
+	hashMap.Set(collidingKey{id: 1, hash: 5}, "one")
+	hashMap.Set(collidingKey{id: 2, hash: 5}, "two")
+
+	entry := hashMap.Entry(collidingKey{id: 1, hash: 5})
+	entry.Delete()
+	// the second key is shifted into the position of the first one,
+	// so the setting shouldn't overwrite it
+	entry.Set("three")
+
+	assert.Equal(test, collidingKey{id: 2, hash: 5}, hashMap.buckets[5].key)
+	assert.Equal(test, collidingKey{id: 1, hash: 5}, hashMap.buckets[6].key)
+	assert.Equal(test, 2, hashMap.Size())
+}
+
+func TestEntry_withStorage(test *testing.T) {
+	storage := new(MockStorage)
+	storage.On("Get", IntKey(23)).Return(1, true).Once()
+	storage.On("Set", IntKey(23), 2).Return().Once()
+	storage.On("Delete", IntKey(23)).Return().Once()
+
+	var gotValues []interface{}
+	withStorageEntry(storage, IntKey(23), func(entry *Entry) {
+		entry.AndModify(func(value interface{}) interface{} {
+			return value.(int) + 1
+		})
+		gotValues = append(gotValues, entry.Value())
+
+		entry.Delete()
+		gotValues = append(gotValues, entry.Value(), entry.Exists())
+	})
+
+	mock.AssertExpectationsForObjects(test, storage)
+	assert.Equal(test, []interface{}{2, nil, false}, gotValues)
+}
@@ -0,0 +1,34 @@
+package hashmap
+
+import (
+	"fmt"
+)
+
+// it details a sentinel error (e.g. the ErrInvalidInitialCapacity error);
+// the latter is returned by the Cause() method (see the errors.Cause()
+// function of the github.com/pkg/errors package) and by the Unwrap() method
+// (see the errors.Is() function of Go 1.13+)
+type detailedError struct {
+	cause   error
+	details string
+}
+
+func newDetailedError(
+	cause error,
+	format string,
+	arguments ...interface{},
+) error {
+	return detailedError{cause: cause, details: fmt.Sprintf(format, arguments...)}
+}
+
+func (err detailedError) Error() string {
+	return err.cause.Error() + ": " + err.details
+}
+
+func (err detailedError) Cause() error {
+	return err.cause
+}
+
+func (err detailedError) Unwrap() error {
+	return err.cause
+}
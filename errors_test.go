@@ -0,0 +1,17 @@
+package hashmap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_detailedError(test *testing.T) {
+	cause := errors.New("cause")
+	err := newDetailedError(cause, "%d, %s", 23, "details")
+
+	assert.EqualError(test, err, "cause: 23, details")
+	assert.Equal(test, cause, errorCause(err))
+	assert.Equal(test, cause, err.(detailedError).Unwrap())
+}
@@ -2,23 +2,8 @@ package hashmap
 
 import (
 	"fmt"
-	"hash/fnv"
-	"io"
 )
 
-type StringKey string
-
-func (key StringKey) Hash() int {
-	hash := fnv.New32()
-	io.WriteString(hash, string(key)) // nolint: errcheck
-
-	return int(hash.Sum32())
-}
-
-func (key StringKey) Equals(other Key) bool {
-	return key == other.(StringKey)
-}
-
 func Example() {
 	timeZones := NewConcurrentHashMap()
 	timeZones.Set(StringKey("EST"), -5*60*60)
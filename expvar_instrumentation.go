@@ -0,0 +1,192 @@
+package hashmap
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ExpvarInstrumentation ...
+//
+// It exports events of maps as expvar variables. It's safe for concurrent
+// access.
+//
+// Exported variables:
+//   - get_hits, get_misses: counts of the getting;
+//   - hit_ratio: a ratio of hits to all getting;
+//   - sets, deletes: counts of the setting and the deleting;
+//   - probes, probe_length_total, max_probe_length: a count of probe
+//     sequences, a sum and a maximum of their lengths;
+//   - rehashes, rehash_duration_total: a count of rehashing and a sum
+//     of its durations in nanoseconds;
+//   - lock_acquisitions, lock_wait_total: a count of lock acquisitions
+//     and a sum of their waits in nanoseconds;
+//   - segment_loads: sizes of segments by their indices.
+//
+type ExpvarInstrumentation struct {
+	getHits             *expvar.Int
+	getMisses           *expvar.Int
+	sets                *expvar.Int
+	deletes             *expvar.Int
+	probes              *expvar.Int
+	probeLengthTotal    *expvar.Int
+	maxProbeLength      *int64
+	rehashes            *expvar.Int
+	rehashDurationTotal *expvar.Int
+	lockAcquisitions    *expvar.Int
+	lockWaitTotal       *expvar.Int
+	segmentLoads        *expvar.Map
+	// it serializes only the creating of segment loads, so each segment
+	// has a single variable
+	segmentLoadsLock *sync.Mutex
+}
+
+// NewExpvarInstrumentation ...
+//
+// It adds variables to the passed map. The map can be published by the caller
+// (e.g. via the expvar.NewMap() function).
+//
+func NewExpvarInstrumentation(metrics *expvar.Map) ExpvarInstrumentation {
+	instrumentation := ExpvarInstrumentation{
+		getHits:             new(expvar.Int),
+		getMisses:           new(expvar.Int),
+		sets:                new(expvar.Int),
+		deletes:             new(expvar.Int),
+		probes:              new(expvar.Int),
+		probeLengthTotal:    new(expvar.Int),
+		maxProbeLength:      new(int64),
+		rehashes:            new(expvar.Int),
+		rehashDurationTotal: new(expvar.Int),
+		lockAcquisitions:    new(expvar.Int),
+		lockWaitTotal:       new(expvar.Int),
+		segmentLoads:        new(expvar.Map).Init(),
+		segmentLoadsLock:    new(sync.Mutex),
+	}
+
+	metrics.Set("get_hits", instrumentation.getHits)
+	metrics.Set("get_misses", instrumentation.getMisses)
+	metrics.Set("hit_ratio", expvar.Func(instrumentation.hitRatio))
+	metrics.Set("sets", instrumentation.sets)
+	metrics.Set("deletes", instrumentation.deletes)
+	metrics.Set("probes", instrumentation.probes)
+	metrics.Set("probe_length_total", instrumentation.probeLengthTotal)
+	metrics.Set("max_probe_length", expvar.Func(func() interface{} {
+		return atomic.LoadInt64(instrumentation.maxProbeLength)
+	}))
+	metrics.Set("rehashes", instrumentation.rehashes)
+	metrics.Set("rehash_duration_total", instrumentation.rehashDurationTotal)
+	metrics.Set("lock_acquisitions", instrumentation.lockAcquisitions)
+	metrics.Set("lock_wait_total", instrumentation.lockWaitTotal)
+	metrics.Set("segment_loads", instrumentation.segmentLoads)
+
+	return instrumentation
+}
+
+// OnGetHit ...
+func (instrumentation ExpvarInstrumentation) OnGetHit(probeLength int) {
+	instrumentation.getHits.Add(1)
+	instrumentation.addProbe(probeLength)
+}
+
+// OnGetMiss ...
+func (instrumentation ExpvarInstrumentation) OnGetMiss(probeLength int) {
+	instrumentation.getMisses.Add(1)
+	instrumentation.addProbe(probeLength)
+}
+
+// OnSet ...
+func (instrumentation ExpvarInstrumentation) OnSet(probeLength int) {
+	instrumentation.sets.Add(1)
+	instrumentation.addProbe(probeLength)
+}
+
+// OnDelete ...
+func (instrumentation ExpvarInstrumentation) OnDelete(probeLength int) {
+	instrumentation.deletes.Add(1)
+	instrumentation.addProbe(probeLength)
+}
+
+// OnRehashStart ...
+func (instrumentation ExpvarInstrumentation) OnRehashStart(
+	size int,
+	capacity int,
+) {
+}
+
+// OnRehashEnd ...
+func (instrumentation ExpvarInstrumentation) OnRehashEnd(
+	size int,
+	capacity int,
+	duration time.Duration,
+) {
+	instrumentation.rehashes.Add(1)
+	instrumentation.rehashDurationTotal.Add(int64(duration))
+}
+
+// OnLockContention ...
+func (instrumentation ExpvarInstrumentation) OnLockContention(
+	wait time.Duration,
+) {
+	instrumentation.lockAcquisitions.Add(1)
+	instrumentation.lockWaitTotal.Add(int64(wait))
+}
+
+// OnSegmentLoad ...
+func (instrumentation ExpvarInstrumentation) OnSegmentLoad(
+	segment int,
+	size int,
+) {
+	instrumentation.segmentLoad(segment).Set(int64(size))
+}
+
+// it returns the existing variable of the segment load or creates it once
+func (instrumentation ExpvarInstrumentation) segmentLoad(
+	segment int,
+) *expvar.Int {
+	name := strconv.Itoa(segment)
+	if load, ok := instrumentation.segmentLoads.Get(name).(*expvar.Int); ok {
+		return load
+	}
+
+	instrumentation.segmentLoadsLock.Lock()
+	defer instrumentation.segmentLoadsLock.Unlock()
+
+	// the variable can be created by another goroutine before the locking
+	if load, ok := instrumentation.segmentLoads.Get(name).(*expvar.Int); ok {
+		return load
+	}
+
+	load := new(expvar.Int)
+	instrumentation.segmentLoads.Set(name, load)
+
+	return load
+}
+
+func (instrumentation ExpvarInstrumentation) addProbe(probeLength int) {
+	instrumentation.probes.Add(1)
+	instrumentation.probeLengthTotal.Add(int64(probeLength))
+
+	for {
+		maxProbeLength := atomic.LoadInt64(instrumentation.maxProbeLength)
+		if int64(probeLength) <= maxProbeLength ||
+			atomic.CompareAndSwapInt64(
+				instrumentation.maxProbeLength,
+				maxProbeLength,
+				int64(probeLength),
+			) {
+			break
+		}
+	}
+}
+
+func (instrumentation ExpvarInstrumentation) hitRatio() interface{} {
+	hits := instrumentation.getHits.Value()
+	total := hits + instrumentation.getMisses.Value()
+	if total == 0 {
+		return 0.0
+	}
+
+	return float64(hits) / float64(total)
+}
@@ -0,0 +1,70 @@
+package hashmap
+
+import (
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpvarInstrumentation(test *testing.T) {
+	metrics := new(expvar.Map).Init()
+	instrumentation := NewExpvarInstrumentation(metrics)
+	instrumentation.OnGetHit(1)
+	instrumentation.OnGetHit(3)
+	instrumentation.OnGetHit(2)
+	instrumentation.OnGetMiss(2)
+	instrumentation.OnSet(1)
+	instrumentation.OnDelete(1)
+	instrumentation.OnRehashStart(12, 16)
+	instrumentation.OnRehashEnd(12, 32, time.Millisecond)
+	instrumentation.OnLockContention(time.Microsecond)
+	instrumentation.OnLockContention(2 * time.Microsecond)
+	instrumentation.OnSegmentLoad(5, 23)
+	instrumentation.OnSegmentLoad(5, 42)
+
+	for name, want := range map[string]string{
+		"get_hits":              "3",
+		"get_misses":            "1",
+		"hit_ratio":             "0.75",
+		"sets":                  "1",
+		"deletes":               "1",
+		"probes":                "6",
+		"probe_length_total":    "10",
+		"max_probe_length":      "3",
+		"rehashes":              "1",
+		"rehash_duration_total": "1000000",
+		"lock_acquisitions":     "2",
+		"lock_wait_total":       "3000",
+		"segment_loads":         `{"5": 42}`,
+	} {
+		assert.Equal(test, want, metrics.Get(name).String(), name)
+	}
+}
+
+func TestExpvarInstrumentation_OnSegmentLoad(test *testing.T) {
+	metrics := new(expvar.Map).Init()
+	instrumentation := NewExpvarInstrumentation(metrics)
+	instrumentation.OnSegmentLoad(5, 23)
+	load := instrumentation.segmentLoads.Get("5")
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			instrumentation.OnSegmentLoad(5, 42)
+			instrumentation.OnSegmentLoad(10+i, i)
+		}(i)
+	}
+	waitGroup.Wait()
+
+	// the variable is updated in place instead of being replaced
+	assert.True(test, load == instrumentation.segmentLoads.Get("5"))
+	assert.Equal(test, "42", load.String())
+	assert.Equal(test, "7", instrumentation.segmentLoads.Get("17").String())
+}
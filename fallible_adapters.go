@@ -0,0 +1,144 @@
+package hashmap
+
+import (
+	"context"
+)
+
+// ErrorHandler ...
+type ErrorHandler func(err error)
+
+// FallibleAdapter ...
+//
+// It adapts the Storage interface to the FallibleStorage one. It fails only
+// if the passed context is done before the operation.
+//
+type FallibleAdapter struct {
+	storage Storage
+}
+
+// NewFallibleAdapter ...
+func NewFallibleAdapter(storage Storage) FallibleAdapter {
+	return FallibleAdapter{storage: storage}
+}
+
+// Get ...
+func (adapter FallibleAdapter) Get(ctx context.Context, key Key) (
+	value interface{},
+	ok bool,
+	err error,
+) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	value, ok = adapter.storage.Get(key)
+	return value, ok, nil
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken without an error.
+//
+// If the context is done, iteration is broken with its error.
+//
+func (adapter FallibleAdapter) Iterate(ctx context.Context, handler Handler) (
+	ok bool,
+	err error,
+) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if ok := adapter.storage.Iterate(WithInterruption(ctx, handler)); !ok {
+		return false, ctx.Err()
+	}
+
+	return true, nil
+}
+
+// Set ...
+func (adapter FallibleAdapter) Set(
+	ctx context.Context,
+	key Key,
+	value interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	adapter.storage.Set(key, value)
+	return nil
+}
+
+// Delete ...
+func (adapter FallibleAdapter) Delete(ctx context.Context, key Key) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	adapter.storage.Delete(key)
+	return nil
+}
+
+// InfallibleAdapter ...
+//
+// It adapts the FallibleStorage interface to the Storage one. It passes
+// the background context to the storage and reports its errors
+// via the error handler.
+//
+type InfallibleAdapter struct {
+	storage      FallibleStorage
+	errorHandler ErrorHandler
+}
+
+// NewInfallibleAdapter ...
+func NewInfallibleAdapter(
+	storage FallibleStorage,
+	errorHandler ErrorHandler,
+) InfallibleAdapter {
+	return InfallibleAdapter{storage: storage, errorHandler: errorHandler}
+}
+
+// Get ...
+//
+// If the storage fails, it's considered as a miss.
+//
+func (adapter InfallibleAdapter) Get(key Key) (value interface{}, ok bool) {
+	value, ok, err := adapter.storage.Get(context.Background(), key)
+	if err != nil {
+		adapter.errorHandler(err)
+		return nil, false
+	}
+
+	return value, ok
+}
+
+// Iterate ...
+//
+// If the handler returns false or the storage fails, iteration is broken.
+//
+func (adapter InfallibleAdapter) Iterate(handler Handler) bool {
+	ok, err := adapter.storage.Iterate(context.Background(), handler)
+	if err != nil {
+		adapter.errorHandler(err)
+		return false
+	}
+
+	return ok
+}
+
+// Set ...
+func (adapter InfallibleAdapter) Set(key Key, value interface{}) {
+	err := adapter.storage.Set(context.Background(), key, value)
+	if err != nil {
+		adapter.errorHandler(err)
+	}
+}
+
+// Delete ...
+func (adapter InfallibleAdapter) Delete(key Key) {
+	err := adapter.storage.Delete(context.Background(), key)
+	if err != nil {
+		adapter.errorHandler(err)
+	}
+}
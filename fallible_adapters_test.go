@@ -0,0 +1,449 @@
+package hashmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFallibleAdapter_Get(test *testing.T) {
+	type fields struct {
+		storage Storage
+	}
+	type args struct {
+		ctx context.Context
+		key Key
+	}
+
+	for _, data := range []struct {
+		name      string
+		fields    fields
+		args      args
+		wantValue interface{}
+		wantOk    assert.BoolAssertionFunc
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				storage: func() Storage {
+					storage := new(MockStorage)
+					storage.On("Get", NewMockKeyWithID(23)).Return("data", true)
+
+					return storage
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				key: NewMockKeyWithID(23),
+			},
+			wantValue: "data",
+			wantOk:    assert.True,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				storage: new(MockStorage),
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+
+					return ctx
+				}(),
+				key: NewMockKeyWithID(23),
+			},
+			wantValue: nil,
+			wantOk:    assert.False,
+			wantErr:   assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			adapter := NewFallibleAdapter(data.fields.storage)
+			gotValue, gotOk, gotErr := adapter.Get(data.args.ctx, data.args.key)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
+			assert.Equal(test, data.wantValue, gotValue)
+			data.wantOk(test, gotOk)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestFallibleAdapter_Iterate(test *testing.T) {
+	for _, data := range []struct {
+		name             string
+		cancelOnCount    int
+		interruptOnCount int
+		wantBuckets      []bucket
+		wantOk           assert.BoolAssertionFunc
+		wantErr          assert.ErrorAssertionFunc
+	}{
+		{
+			name:             "without an interrupt",
+			cancelOnCount:    10,
+			interruptOnCount: 10,
+			wantBuckets: []bucket{
+				{key: NewMockKeyWithID(5), value: "five"},
+				{key: NewMockKeyWithID(6), value: "six"},
+				{key: NewMockKeyWithID(7), value: "seven"},
+			},
+			wantOk:  assert.True,
+			wantErr: assert.NoError,
+		},
+		{
+			name:             "with an interrupt by the handler",
+			cancelOnCount:    10,
+			interruptOnCount: 2,
+			wantBuckets: []bucket{
+				{key: NewMockKeyWithID(5), value: "five"},
+				{key: NewMockKeyWithID(6), value: "six"},
+			},
+			wantOk:  assert.False,
+			wantErr: assert.NoError,
+		},
+		{
+			name:             "with an interrupt by the context",
+			cancelOnCount:    2,
+			interruptOnCount: 10,
+			wantBuckets: []bucket{
+				{key: NewMockKeyWithID(5), value: "five"},
+				{key: NewMockKeyWithID(6), value: "six"},
+			},
+			wantOk:  assert.False,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := new(MockStorage)
+			storage.
+				On("Iterate", mock.AnythingOfType("Handler")).
+				Return(func(handler Handler) bool {
+					for _, bucket := range []bucket{
+						{key: NewMockKeyWithID(5), value: "five"},
+						{key: NewMockKeyWithID(6), value: "six"},
+						{key: NewMockKeyWithID(7), value: "seven"},
+					} {
+						if ok := handler(bucket.key, bucket.value); !ok {
+							return false
+						}
+					}
+
+					return true
+				})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var gotBuckets []bucket
+			adapter := NewFallibleAdapter(storage)
+			gotOk, gotErr := adapter.Iterate(
+				ctx,
+				func(key Key, value interface{}) bool {
+					gotBuckets = append(gotBuckets, bucket{key: key, value: value})
+					// cancel after a specified count of got buckets
+					if len(gotBuckets) == data.cancelOnCount {
+						cancel()
+					}
+
+					// interrupt after a specified count of got buckets
+					return len(gotBuckets) < data.interruptOnCount
+				},
+			)
+
+			mock.AssertExpectationsForObjects(test, storage)
+			assert.Equal(test, data.wantBuckets, gotBuckets)
+			data.wantOk(test, gotOk)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestFallibleAdapter_Set(test *testing.T) {
+	type fields struct {
+		storage Storage
+	}
+	type args struct {
+		ctx   context.Context
+		key   Key
+		value interface{}
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				storage: func() Storage {
+					storage := new(MockStorage)
+					storage.On("Set", NewMockKeyWithID(23), "data").Return()
+
+					return storage
+				}(),
+			},
+			args: args{
+				ctx:   context.Background(),
+				key:   NewMockKeyWithID(23),
+				value: "data",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				storage: new(MockStorage),
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+
+					return ctx
+				}(),
+				key:   NewMockKeyWithID(23),
+				value: "data",
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			adapter := NewFallibleAdapter(data.fields.storage)
+			gotErr := adapter.Set(data.args.ctx, data.args.key, data.args.value)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestFallibleAdapter_Delete(test *testing.T) {
+	type fields struct {
+		storage Storage
+	}
+	type args struct {
+		ctx context.Context
+		key Key
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				storage: func() Storage {
+					storage := new(MockStorage)
+					storage.On("Delete", NewMockKeyWithID(23)).Return()
+
+					return storage
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				key: NewMockKeyWithID(23),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				storage: new(MockStorage),
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+
+					return ctx
+				}(),
+				key: NewMockKeyWithID(23),
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			adapter := NewFallibleAdapter(data.fields.storage)
+			gotErr := adapter.Delete(data.args.ctx, data.args.key)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestInfallibleAdapter(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		makeStorage func() FallibleStorage
+		run         func(adapter InfallibleAdapter) []interface{}
+		want        []interface{}
+		wantErrs    []error
+	}{
+		{
+			name: "getting with success",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Get", context.Background(), NewMockKeyWithID(23)).
+					Return("data", true, nil)
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				value, ok := adapter.Get(NewMockKeyWithID(23))
+				return []interface{}{value, ok}
+			},
+			want:     []interface{}{"data", true},
+			wantErrs: nil,
+		},
+		{
+			name: "getting with an error",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Get", context.Background(), NewMockKeyWithID(23)).
+					Return("data", true, errors.New("dummy"))
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				value, ok := adapter.Get(NewMockKeyWithID(23))
+				return []interface{}{value, ok}
+			},
+			want:     []interface{}{nil, false},
+			wantErrs: []error{errors.New("dummy")},
+		},
+		{
+			name: "iteration with success",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+					Return(true, nil)
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				ok := adapter.Iterate(func(key Key, value interface{}) bool {
+					return true
+				})
+				return []interface{}{ok}
+			},
+			want:     []interface{}{true},
+			wantErrs: nil,
+		},
+		{
+			name: "iteration with an error",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+					Return(true, errors.New("dummy"))
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				ok := adapter.Iterate(func(key Key, value interface{}) bool {
+					return true
+				})
+				return []interface{}{ok}
+			},
+			want:     []interface{}{false},
+			wantErrs: []error{errors.New("dummy")},
+		},
+		{
+			name: "setting with success",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Set", context.Background(), NewMockKeyWithID(23), "data").
+					Return(nil)
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				adapter.Set(NewMockKeyWithID(23), "data")
+				return nil
+			},
+			want:     nil,
+			wantErrs: nil,
+		},
+		{
+			name: "setting with an error",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Set", context.Background(), NewMockKeyWithID(23), "data").
+					Return(errors.New("dummy"))
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				adapter.Set(NewMockKeyWithID(23), "data")
+				return nil
+			},
+			want:     nil,
+			wantErrs: []error{errors.New("dummy")},
+		},
+		{
+			name: "deleting with success",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Delete", context.Background(), NewMockKeyWithID(23)).
+					Return(nil)
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				adapter.Delete(NewMockKeyWithID(23))
+				return nil
+			},
+			want:     nil,
+			wantErrs: nil,
+		},
+		{
+			name: "deleting with an error",
+			makeStorage: func() FallibleStorage {
+				storage := new(MockFallibleStorage)
+				storage.
+					On("Delete", context.Background(), NewMockKeyWithID(23)).
+					Return(errors.New("dummy"))
+
+				return storage
+			},
+			run: func(adapter InfallibleAdapter) []interface{} {
+				adapter.Delete(NewMockKeyWithID(23))
+				return nil
+			},
+			want:     nil,
+			wantErrs: []error{errors.New("dummy")},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := data.makeStorage()
+
+			var gotErrs []error
+			adapter := NewInfallibleAdapter(storage, func(err error) {
+				gotErrs = append(gotErrs, err)
+			})
+			got := data.run(adapter)
+
+			mock.AssertExpectationsForObjects(test, storage)
+			assert.Equal(test, data.want, got)
+			assert.Equal(test, data.wantErrs, gotErrs)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package hashmap
+
+import (
+	"context"
+	"math/rand"
+)
+
+// FallibleConcurrentHashMap ...
+//
+// It's the same as the ConcurrentHashMap structure, but it uses
+// the interface of a fallible storage as one shard and propagates errors
+// of shards to the caller.
+//
+type FallibleConcurrentHashMap struct {
+	segments []FallibleStorage
+	hasher   Hasher
+}
+
+// NewFallibleConcurrentHashMap ...
+func NewFallibleConcurrentHashMap(
+	options ...ConcurrentOption,
+) FallibleConcurrentHashMap {
+	config := newConcurrentConfig(options)
+	if config.fallibleSegmentFactory == nil {
+		segmentFactory := config.segmentFactory
+		config.fallibleSegmentFactory = func() FallibleStorage {
+			return NewFallibleAdapter(segmentFactory())
+		}
+	}
+
+	var segments []FallibleStorage
+	for i := 0; i < config.concurrencyLevel; i++ {
+		segment := config.fallibleSegmentFactory()
+		segments = append(segments, segment)
+	}
+
+	return FallibleConcurrentHashMap{
+		segments: segments,
+		hasher:   config.hasher,
+	}
+}
+
+// Get ...
+func (hashMap FallibleConcurrentHashMap) Get(ctx context.Context, key Key) (
+	value interface{},
+	ok bool,
+	err error,
+) {
+	return hashMap.selectSegment(key).Get(ctx, key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken without an error.
+//
+// If a segment fails, iteration is broken with its error.
+//
+// It randomizes of iteration order over items and their keys and over segments.
+//
+func (hashMap FallibleConcurrentHashMap) Iterate(
+	ctx context.Context,
+	handler Handler,
+) (ok bool, err error) {
+	for _, index := range rand.Perm(len(hashMap.segments)) {
+		segment := hashMap.segments[index]
+		if ok, err := segment.Iterate(ctx, handler); !ok || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Set ...
+func (hashMap FallibleConcurrentHashMap) Set(
+	ctx context.Context,
+	key Key,
+	value interface{},
+) error {
+	return hashMap.selectSegment(key).Set(ctx, key, value)
+}
+
+// Delete ...
+func (hashMap FallibleConcurrentHashMap) Delete(
+	ctx context.Context,
+	key Key,
+) error {
+	return hashMap.selectSegment(key).Delete(ctx, key)
+}
+
+func (hashMap FallibleConcurrentHashMap) selectSegment(
+	key Key,
+) FallibleStorage {
+	index := segmentIndex(hashKey(hashMap.hasher, key), len(hashMap.segments))
+	return hashMap.segments[index]
+}
@@ -0,0 +1,311 @@
+package hashmap
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewFallibleConcurrentHashMap(test *testing.T) {
+	type args struct {
+		options []ConcurrentOption
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want FallibleConcurrentHashMap
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			want: FallibleConcurrentHashMap{
+				segments: func() []FallibleStorage {
+					var segments []FallibleStorage
+					for i := 0; i < defaultConcurrentConfig.concurrencyLevel; i++ {
+						segments = append(segments, FallibleAdapter{
+							storage: &SynchronizedHashMap{
+								innerMap: &HashMap{
+									config:  defaultConfig,
+									buckets: make([]bucket, defaultConfig.initialCapacity),
+									size:    0,
+								},
+							},
+						})
+					}
+
+					return segments
+				}(),
+			},
+		},
+		{
+			name: "with the set segment factory",
+			args: args{
+				options: []ConcurrentOption{
+					WithConcurrencyLevel(23),
+					WithSegmentFactory(func() Storage { return new(MockStorage) }),
+				},
+			},
+			want: FallibleConcurrentHashMap{
+				segments: func() []FallibleStorage {
+					var segments []FallibleStorage
+					for i := 0; i < 23; i++ {
+						segments = append(
+							segments,
+							FallibleAdapter{storage: new(MockStorage)},
+						)
+					}
+
+					return segments
+				}(),
+			},
+		},
+		{
+			name: "with the set fallible segment factory",
+			args: args{
+				options: []ConcurrentOption{
+					WithConcurrencyLevel(23),
+					WithFallibleSegmentFactory(func() FallibleStorage {
+						return new(MockFallibleStorage)
+					}),
+				},
+			},
+			want: FallibleConcurrentHashMap{
+				segments: func() []FallibleStorage {
+					var segments []FallibleStorage
+					for i := 0; i < 23; i++ {
+						segments = append(segments, new(MockFallibleStorage))
+					}
+
+					return segments
+				}(),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewFallibleConcurrentHashMap(data.args.options...)
+			resetSeeds(test, got)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestFallibleConcurrentHashMap(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		makeMap func() FallibleConcurrentHashMap
+		run     func(hashMap FallibleConcurrentHashMap) []interface{}
+		want    []interface{}
+	}{
+		{
+			name: "getting by a nonexistent key",
+			makeMap: func() FallibleConcurrentHashMap {
+				return NewFallibleConcurrentHashMap()
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{nil, false, nil},
+		},
+		{
+			name: "setting by a nonexistent key",
+			makeMap: func() FallibleConcurrentHashMap {
+				hashMap := NewFallibleConcurrentHashMap()
+				hashMap.Set(context.Background(), IntKey(5), "five") // nolint: errcheck
+
+				return hashMap
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{"five", true, nil},
+		},
+		{
+			name: "deleting by an existing key",
+			makeMap: func() FallibleConcurrentHashMap {
+				hashMap := NewFallibleConcurrentHashMap()
+				hashMap.Set(context.Background(), IntKey(5), "five") // nolint: errcheck
+				hashMap.Delete(context.Background(), IntKey(5))      // nolint: errcheck
+
+				return hashMap
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{nil, false, nil},
+		},
+		{
+			name: "getting with an error",
+			makeMap: func() FallibleConcurrentHashMap {
+				segment := new(MockFallibleStorage)
+				segment.
+					On("Get", context.Background(), IntKey(5)).
+					Return(nil, false, errors.New("dummy"))
+
+				return NewFallibleConcurrentHashMap(
+					WithConcurrencyLevel(1),
+					WithFallibleSegmentFactory(func() FallibleStorage { return segment }),
+				)
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				_, _, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+		{
+			name: "setting with an error",
+			makeMap: func() FallibleConcurrentHashMap {
+				segment := new(MockFallibleStorage)
+				segment.
+					On("Set", context.Background(), IntKey(5), "five").
+					Return(errors.New("dummy"))
+
+				return NewFallibleConcurrentHashMap(
+					WithConcurrencyLevel(1),
+					WithFallibleSegmentFactory(func() FallibleStorage { return segment }),
+				)
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				err := hashMap.Set(context.Background(), IntKey(5), "five")
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+		{
+			name: "deleting with an error",
+			makeMap: func() FallibleConcurrentHashMap {
+				segment := new(MockFallibleStorage)
+				segment.
+					On("Delete", context.Background(), IntKey(5)).
+					Return(errors.New("dummy"))
+
+				return NewFallibleConcurrentHashMap(
+					WithConcurrencyLevel(1),
+					WithFallibleSegmentFactory(func() FallibleStorage { return segment }),
+				)
+			},
+			run: func(hashMap FallibleConcurrentHashMap) []interface{} {
+				err := hashMap.Delete(context.Background(), IntKey(5))
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := data.makeMap()
+			got := data.run(hashMap)
+
+			for _, segment := range hashMap.segments {
+				if _, ok := segment.(*MockFallibleStorage); ok {
+					mock.AssertExpectationsForObjects(test, segment)
+				}
+			}
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestFallibleConcurrentHashMap_Iterate(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		makeSegments func() []FallibleStorage
+		wantOk       assert.BoolAssertionFunc
+		wantErr      error
+	}{
+		{
+			name: "success",
+			makeSegments: func() []FallibleStorage {
+				var segments []FallibleStorage
+				for i := 0; i < 3; i++ {
+					segment := new(MockFallibleStorage)
+					segment.
+						On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+						Return(true, nil)
+
+					segments = append(segments, segment)
+				}
+
+				return segments
+			},
+			wantOk:  assert.True,
+			wantErr: nil,
+		},
+		{
+			name: "interrupt",
+			makeSegments: func() []FallibleStorage {
+				segment := new(MockFallibleStorage)
+				segment.
+					On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+					Return(false, nil)
+
+				return []FallibleStorage{new(MockFallibleStorage), segment}
+			},
+			wantOk:  assert.False,
+			wantErr: nil,
+		},
+		{
+			name: "error",
+			makeSegments: func() []FallibleStorage {
+				segment := new(MockFallibleStorage)
+				segment.
+					On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+					Return(true, errors.New("dummy"))
+
+				return []FallibleStorage{new(MockFallibleStorage), segment}
+			},
+			wantOk:  assert.False,
+			wantErr: errors.New("dummy"),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			// reset the random generator to make tests deterministic
+			rand.Seed(2)
+
+			segments := data.makeSegments()
+			hashMap := FallibleConcurrentHashMap{segments: segments}
+			gotOk, gotErr := hashMap.Iterate(
+				context.Background(),
+				func(key Key, value interface{}) bool { return true },
+			)
+
+			for _, segment := range segments {
+				mock.AssertExpectationsForObjects(test, segment)
+			}
+			data.wantOk(test, gotOk)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestFallibleConcurrentHashMap_withHasherAndEqualer(test *testing.T) {
+	ctx := context.Background()
+	hashMap := NewFallibleConcurrentHashMap(
+		WithConcurrentHasher(caseInsensitiveHasher),
+		WithConcurrentEqualer(caseInsensitiveEqualer),
+	)
+	assert.NoError(test, hashMap.Set(ctx, StringKey("key"), "one"))
+	assert.NoError(test, hashMap.Set(ctx, StringKey("KEY"), "two"))
+
+	gotValue, gotOk, gotErr := hashMap.Get(ctx, StringKey("Key"))
+	assert.Equal(test, "two", gotValue)
+	assert.True(test, gotOk)
+	assert.NoError(test, gotErr)
+
+	assert.NoError(test, hashMap.Delete(ctx, StringKey("kEY")))
+
+	gotValue, gotOk, gotErr = hashMap.Get(ctx, StringKey("key"))
+	assert.Nil(test, gotValue)
+	assert.False(test, gotOk)
+	assert.NoError(test, gotErr)
+}
@@ -0,0 +1,118 @@
+package hashmap
+
+import (
+	"context"
+)
+
+// FallibleSynchronizedHashMap ...
+//
+// It's the same as the SynchronizedHashMap structure, but it uses
+// the interface of a fallible storage as an inner map and propagates its
+// errors to the caller.
+//
+// It abandons lock acquisition when the passed context is done and returns
+// its error.
+//
+type FallibleSynchronizedHashMap struct {
+	lock     contextRWMutex
+	innerMap FallibleStorage
+}
+
+// NewFallibleSynchronizedHashMap ...
+func NewFallibleSynchronizedHashMap(
+	options ...SynchronizedOption,
+) *FallibleSynchronizedHashMap {
+	// you can't move the default synchronized config into a global variable
+	// because the default inner map should be new every time
+	config := SynchronizedConfig{innerMap: NewHashMap()}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.fallibleInnerMap == nil {
+		config.fallibleInnerMap = NewFallibleAdapter(config.innerMap)
+	}
+
+	return &FallibleSynchronizedHashMap{innerMap: config.fallibleInnerMap}
+}
+
+// Get ...
+func (hashMap *FallibleSynchronizedHashMap) Get(
+	ctx context.Context,
+	key Key,
+) (value interface{}, ok bool, err error) {
+	if err := hashMap.lock.RLockContext(ctx); err != nil {
+		return nil, false, err
+	}
+	defer hashMap.lock.RUnlock()
+
+	return hashMap.innerMap.Get(ctx, key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken without an error.
+//
+// A mutex lock is using only for iteration, not for handling (the handler
+// is called out of lock).
+//
+func (hashMap *FallibleSynchronizedHashMap) Iterate(
+	ctx context.Context,
+	handler Handler,
+) (ok bool, err error) {
+	if err := hashMap.lock.RLockContext(ctx); err != nil {
+		return false, err
+	}
+
+	locked := true
+	defer func() {
+		if locked {
+			hashMap.lock.RUnlock()
+		}
+	}()
+
+	var lockErr error
+	ok, err = hashMap.innerMap.Iterate(ctx, func(key Key, value interface{}) bool {
+		hashMap.lock.RUnlock()
+		locked = false
+
+		ok := handler(key, value)
+		if lockErr = hashMap.lock.RLockContext(ctx); lockErr != nil {
+			return false
+		}
+		locked = true
+
+		return ok
+	})
+	if err == nil {
+		err = lockErr
+	}
+
+	return ok, err
+}
+
+// Set ...
+func (hashMap *FallibleSynchronizedHashMap) Set(
+	ctx context.Context,
+	key Key,
+	value interface{},
+) error {
+	if err := hashMap.lock.LockContext(ctx); err != nil {
+		return err
+	}
+	defer hashMap.lock.Unlock()
+
+	return hashMap.innerMap.Set(ctx, key, value)
+}
+
+// Delete ...
+func (hashMap *FallibleSynchronizedHashMap) Delete(
+	ctx context.Context,
+	key Key,
+) error {
+	if err := hashMap.lock.LockContext(ctx); err != nil {
+		return err
+	}
+	defer hashMap.lock.Unlock()
+
+	return hashMap.innerMap.Delete(ctx, key)
+}
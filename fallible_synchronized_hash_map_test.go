@@ -0,0 +1,217 @@
+package hashmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewFallibleSynchronizedHashMap(test *testing.T) {
+	type args struct {
+		options []SynchronizedOption
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want *FallibleSynchronizedHashMap
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			want: &FallibleSynchronizedHashMap{
+				innerMap: FallibleAdapter{
+					storage: &HashMap{
+						config:  defaultConfig,
+						buckets: make([]bucket, defaultConfig.initialCapacity),
+						size:    0,
+					},
+				},
+			},
+		},
+		{
+			name: "with the set inner map",
+			args: args{
+				options: []SynchronizedOption{WithInnerMap(new(MockStorage))},
+			},
+			want: &FallibleSynchronizedHashMap{
+				innerMap: FallibleAdapter{storage: new(MockStorage)},
+			},
+		},
+		{
+			name: "with the set fallible inner map",
+			args: args{
+				options: []SynchronizedOption{
+					WithFallibleInnerMap(new(MockFallibleStorage)),
+				},
+			},
+			want: &FallibleSynchronizedHashMap{
+				innerMap: new(MockFallibleStorage),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewFallibleSynchronizedHashMap(data.args.options...)
+			resetSeeds(test, got)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestFallibleSynchronizedHashMap(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		makeMap func() *FallibleSynchronizedHashMap
+		run     func(hashMap *FallibleSynchronizedHashMap) []interface{}
+		want    []interface{}
+	}{
+		{
+			name:    "getting by a nonexistent key",
+			makeMap: func() *FallibleSynchronizedHashMap { return NewFallibleSynchronizedHashMap() },
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{nil, false, nil},
+		},
+		{
+			name: "setting by a nonexistent key",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				hashMap := NewFallibleSynchronizedHashMap()
+				hashMap.Set(context.Background(), IntKey(5), "five") // nolint: errcheck
+
+				return hashMap
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{"five", true, nil},
+		},
+		{
+			name: "deleting by an existing key",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				hashMap := NewFallibleSynchronizedHashMap()
+				hashMap.Set(context.Background(), IntKey(5), "five") // nolint: errcheck
+				hashMap.Delete(context.Background(), IntKey(5))      // nolint: errcheck
+
+				return hashMap
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				value, ok, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{value, ok, err}
+			},
+			want: []interface{}{nil, false, nil},
+		},
+		{
+			name: "getting with an error",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				innerMap := new(MockFallibleStorage)
+				innerMap.
+					On("Get", context.Background(), IntKey(5)).
+					Return(nil, false, errors.New("dummy"))
+
+				return NewFallibleSynchronizedHashMap(WithFallibleInnerMap(innerMap))
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				_, _, err := hashMap.Get(context.Background(), IntKey(5))
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+		{
+			name: "iteration with an error",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				innerMap := new(MockFallibleStorage)
+				innerMap.
+					On("Iterate", context.Background(), mock.AnythingOfType("Handler")).
+					Return(false, errors.New("dummy"))
+
+				return NewFallibleSynchronizedHashMap(WithFallibleInnerMap(innerMap))
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				_, err := hashMap.Iterate(
+					context.Background(),
+					func(key Key, value interface{}) bool { return true },
+				)
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+		{
+			name: "setting with an error",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				innerMap := new(MockFallibleStorage)
+				innerMap.
+					On("Set", context.Background(), IntKey(5), "five").
+					Return(errors.New("dummy"))
+
+				return NewFallibleSynchronizedHashMap(WithFallibleInnerMap(innerMap))
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				err := hashMap.Set(context.Background(), IntKey(5), "five")
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+		{
+			name: "deleting with an error",
+			makeMap: func() *FallibleSynchronizedHashMap {
+				innerMap := new(MockFallibleStorage)
+				innerMap.
+					On("Delete", context.Background(), IntKey(5)).
+					Return(errors.New("dummy"))
+
+				return NewFallibleSynchronizedHashMap(WithFallibleInnerMap(innerMap))
+			},
+			run: func(hashMap *FallibleSynchronizedHashMap) []interface{} {
+				err := hashMap.Delete(context.Background(), IntKey(5))
+				return []interface{}{err}
+			},
+			want: []interface{}{errors.New("dummy")},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := data.makeMap()
+			got := data.run(hashMap)
+
+			if _, ok := hashMap.innerMap.(*MockFallibleStorage); ok {
+				mock.AssertExpectationsForObjects(test, hashMap.innerMap)
+			}
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestFallibleSynchronizedHashMap_Iterate(test *testing.T) {
+	hashMap := NewFallibleSynchronizedHashMap()
+	for i := 0; i < 10; i++ {
+		hashMap.Set(context.Background(), IntKey(i), i) // nolint: errcheck
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotCount int
+	gotOk, gotErr := hashMap.Iterate(ctx, func(key Key, value interface{}) bool {
+		// the lock should be released during handling
+		hashMap.Set(context.Background(), key, value) // nolint: errcheck
+
+		gotCount++
+		if gotCount == 5 {
+			cancel()
+		}
+
+		return true
+	})
+
+	assert.Equal(test, 5, gotCount)
+	assert.False(test, gotOk)
+	assert.Equal(test, context.Canceled, gotErr)
+}
@@ -0,0 +1,273 @@
+package hashmap
+
+import (
+	"math/bits"
+)
+
+const (
+	hamtBitsPerLevel = 5
+	hamtLevelMask    = 1<<hamtBitsPerLevel - 1
+)
+
+// it's a node of a hash array mapped trie; each entry is either an item
+// or a child node; a node deeper than bits of a hash is a collision one,
+// it stores items with equal hashes as a list, and its bitmap isn't used
+type hamtNode struct {
+	bitmap  uint32
+	entries []hamtEntry
+	// a node can be modified in place only by a builder that owns it
+	owner *hamtOwner
+}
+
+type hamtEntry struct {
+	key   Key
+	value interface{}
+	child *hamtNode
+}
+
+// it identifies a transient builder; it isn't empty, because pointers
+// to distinct zero-size variables may be equal
+type hamtOwner struct {
+	_ byte
+}
+
+func (node *hamtNode) get(key Key, hash uint64, shift uint) (
+	value interface{},
+	ok bool,
+) {
+	for {
+		if isHAMTCollisionLevel(shift) {
+			index := node.findCollision(key)
+			if index == -1 {
+				return nil, false
+			}
+
+			return node.entries[index].value, true
+		}
+
+		bit, position := node.locate(hash, shift)
+		if node.bitmap&bit == 0 {
+			return nil, false
+		}
+
+		entry := node.entries[position]
+		if entry.child == nil {
+			if !entry.key.Equals(key) {
+				return nil, false
+			}
+
+			return entry.value, true
+		}
+
+		node, shift = entry.child, shift+hamtBitsPerLevel
+	}
+}
+
+func (node *hamtNode) iterate(handler Handler) bool {
+	for _, entry := range node.entries {
+		if entry.child != nil {
+			if ok := entry.child.iterate(handler); !ok {
+				return false
+			}
+
+			continue
+		}
+
+		if ok := handler(entry.key, entry.value); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// it returns a node with the item set; the original node is modified
+// in place only if it's owned by the owner
+func (node *hamtNode) set(
+	owner *hamtOwner,
+	key Key,
+	value interface{},
+	hash uint64,
+	shift uint,
+) (newNode *hamtNode, added bool) {
+	if isHAMTCollisionLevel(shift) {
+		newNode = node.editable(owner)
+		if index := node.findCollision(key); index != -1 {
+			newNode.entries[index].value = value
+			return newNode, false
+		}
+
+		newNode.entries = append(newNode.entries, hamtEntry{key: key, value: value})
+		return newNode, true
+	}
+
+	bit, position := node.locate(hash, shift)
+	if node.bitmap&bit == 0 {
+		newNode = node.editable(owner)
+		newNode.bitmap |= bit
+		newNode.entries = append(newNode.entries, hamtEntry{})
+		copy(newNode.entries[position+1:], newNode.entries[position:])
+		newNode.entries[position] = hamtEntry{key: key, value: value}
+
+		return newNode, true
+	}
+
+	entry := node.entries[position]
+	switch {
+	case entry.child != nil:
+		var newChild *hamtNode
+		newChild, added = entry.child.set(
+			owner,
+			key,
+			value,
+			hash,
+			shift+hamtBitsPerLevel,
+		)
+
+		newNode = node.editable(owner)
+		newNode.entries[position].child = newChild
+	case entry.key.Equals(key):
+		newNode = node.editable(owner)
+		newNode.entries[position].value = value
+	default:
+		// both items go down to a new child node
+		newChild := &hamtNode{owner: owner}
+		newChild, _ = newChild.set(
+			owner,
+			entry.key,
+			entry.value,
+			hamtHash(entry.key),
+			shift+hamtBitsPerLevel,
+		)
+		newChild, _ = newChild.set(
+			owner,
+			key,
+			value,
+			hash,
+			shift+hamtBitsPerLevel,
+		)
+
+		newNode = node.editable(owner)
+		newNode.entries[position] = hamtEntry{child: newChild}
+		added = true
+	}
+
+	return newNode, added
+}
+
+// it returns a node without the item or nil if the node becomes empty;
+// the original node is modified in place only if it's owned by the owner
+func (node *hamtNode) delete(
+	owner *hamtOwner,
+	key Key,
+	hash uint64,
+	shift uint,
+) (newNode *hamtNode, deleted bool) {
+	if isHAMTCollisionLevel(shift) {
+		index := node.findCollision(key)
+		if index == -1 {
+			return node, false
+		}
+
+		return node.withoutEntry(owner, index, 0), true
+	}
+
+	bit, position := node.locate(hash, shift)
+	if node.bitmap&bit == 0 {
+		return node, false
+	}
+
+	entry := node.entries[position]
+	if entry.child == nil {
+		if !entry.key.Equals(key) {
+			return node, false
+		}
+
+		return node.withoutEntry(owner, position, bit), true
+	}
+
+	newChild, deleted := entry.child.delete(
+		owner,
+		key,
+		hash,
+		shift+hamtBitsPerLevel,
+	)
+	if !deleted {
+		return node, false
+	}
+	if newChild == nil {
+		return node.withoutEntry(owner, position, bit), true
+	}
+
+	newNode = node.editable(owner)
+	// a child with a single item is collapsed to keep the trie compact
+	if len(newChild.entries) == 1 && newChild.entries[0].child == nil {
+		newNode.entries[position] = newChild.entries[0]
+	} else {
+		newNode.entries[position] = hamtEntry{child: newChild}
+	}
+
+	return newNode, true
+}
+
+// it returns the node itself if it's owned by the owner or its copy
+// owned by the owner otherwise
+func (node *hamtNode) editable(owner *hamtOwner) *hamtNode {
+	if owner != nil && node.owner == owner {
+		return node
+	}
+
+	entries := make([]hamtEntry, len(node.entries), len(node.entries)+1)
+	copy(entries, node.entries)
+
+	return &hamtNode{bitmap: node.bitmap, entries: entries, owner: owner}
+}
+
+func (node *hamtNode) withoutEntry(
+	owner *hamtOwner,
+	index int,
+	bit uint32,
+) *hamtNode {
+	if len(node.entries) == 1 {
+		return nil
+	}
+
+	newNode := node.editable(owner)
+	newNode.bitmap &^= bit
+	newNode.entries = append(
+		newNode.entries[:index],
+		newNode.entries[index+1:]...,
+	)
+
+	return newNode
+}
+
+func (node *hamtNode) locate(hash uint64, shift uint) (
+	bit uint32,
+	position int,
+) {
+	bit = 1 << ((hash >> shift) & hamtLevelMask)
+	position = bits.OnesCount32(node.bitmap & (bit - 1))
+	return bit, position
+}
+
+func (node *hamtNode) findCollision(key Key) int {
+	for index, entry := range node.entries {
+		if entry.key.Equals(key) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// it returns the mixed 64-bit hash of the key, so all its bits are used
+// as the hash path and weak hashes (e.g. ones that differ only in high bits)
+// are spread over the trie
+func hamtHash(key Key) uint64 {
+	return mixHash(hashKey(nil, key))
+}
+
+func isHAMTCollisionLevel(shift uint) bool {
+	return shift >= 64
+}
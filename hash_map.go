@@ -2,11 +2,18 @@ package hashmap
 
 import (
 	"math/rand"
+	"time"
 )
 
+// buckets are stored in the table inline, so a free bucket is marked
+// by the occupancy flag instead of a nil pointer
 type bucket struct {
 	key   Key
 	value interface{}
+	// it's a full hash of the key; it's compared before the key itself
+	// and reused on rehashing with the same seed
+	hash     uint64
+	occupied bool
 }
 
 // HashMap ...
@@ -15,27 +22,58 @@ type bucket struct {
 //
 type HashMap struct {
 	config  Config
-	buckets []*bucket
+	buckets []bucket
 	size    int
+	// a zero seed means hashes of keys are used as is
+	seed     uint64
+	reseeded bool
 }
 
 // NewHashMap ...
+//
+// Invalid options are normalized (see the Config.Validate() method).
+//
 func NewHashMap(options ...Option) *HashMap {
-	config := defaultConfig
-	for _, option := range options {
-		option(&config)
+	return newHashMapWithConfig(newConfig(options).normalize())
+}
+
+// NewHashMapE ...
+//
+// Unlike the NewHashMap() function, it returns an error on invalid options
+// (see the Config.Validate() method).
+//
+func NewHashMapE(options ...Option) (*HashMap, error) {
+	config := newConfig(options)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return newHashMapWithConfig(config), nil
+}
+
+func newHashMapWithConfig(config Config) *HashMap {
+	hashMap := newHashMapWithCapacity(config, config.initialCapacity)
+	hashMap.seed = config.hashSeed
+	if hashMap.seed == 0 {
+		hashMap.seed = newHashSeed()
 	}
 
-	return newHashMapWithCapacity(config, config.initialCapacity)
+	return hashMap
 }
 
 // Get ...
 func (hashMap HashMap) Get(key Key) (value interface{}, ok bool) {
-	index, ok := hashMap.find(key)
+	index, probeLength, ok := hashMap.find(key, hashMap.hash(key))
 	if !ok {
+		if hashMap.config.instrumentation != nil {
+			hashMap.config.instrumentation.OnGetMiss(probeLength)
+		}
 		return nil, false
 	}
 
+	if hashMap.config.instrumentation != nil {
+		hashMap.config.instrumentation.OnGetHit(probeLength)
+	}
 	return hashMap.buckets[index].value, true
 }
 
@@ -45,10 +83,13 @@ func (hashMap HashMap) Get(key Key) (value interface{}, ok bool) {
 //
 // It randomizes of iteration order.
 //
+// The handler shouldn't modify the map (see the HashMap.IterateMutable()
+// method).
+//
 func (hashMap HashMap) Iterate(handler Handler) bool {
 	for _, index := range rand.Perm(len(hashMap.buckets)) {
 		bucket := hashMap.buckets[index]
-		if bucket == nil {
+		if !bucket.occupied {
 			continue
 		}
 
@@ -60,59 +101,394 @@ func (hashMap HashMap) Iterate(handler Handler) bool {
 	return true
 }
 
+// Scan ...
+//
+// It returns a part of items starting from the cursor and a cursor
+// for the next call. Scanning starts from a zero cursor and is finished
+// when a zero cursor is returned. Items are returned by classes of their
+// hashes, and each class is returned entirely, so there may be more items
+// than the count. At least one class is scanned per call.
+//
+// Cursors are incremented in the reverse binary order (like in the SCAN
+// command of Redis), so every item that is present in the map during
+// the whole scanning is returned at least once, even if the map is rehashed
+// between calls. Items may be returned several times. The guarantee doesn't
+// hold if the map is reseeded (see the WithReseedThreshold() function).
+//
+// If a capacity of the map isn't a power of two (see the WithGrowFactor()
+// function), each call walks the whole table.
+//
+func (hashMap HashMap) Scan(cursor uint64, count int) (
+	items []ScanItem,
+	nextCursor uint64,
+) {
+	mask := scanMask(len(hashMap.buckets))
+	if mask+1 != uint64(len(hashMap.buckets)) {
+		return hashMap.scanTable(cursor, count, mask)
+	}
+
+	for {
+		items = hashMap.appendScanClass(items, cursor&mask, mask)
+		cursor = nextScanCursor(cursor, mask)
+		if cursor == 0 || len(items) >= count {
+			return items, cursor
+		}
+	}
+}
+
 // Set ...
 func (hashMap *HashMap) Set(key Key, value interface{}) {
-	index, ok := hashMap.find(key)
+	hash := hashMap.hash(key)
+	index, probeLength, ok := hashMap.find(key, hash)
+	if hashMap.config.instrumentation != nil {
+		hashMap.config.instrumentation.OnSet(probeLength)
+	}
 	if ok {
 		hashMap.buckets[index].value = value
 		return
 	}
 
-	hashMap.buckets[index] = &bucket{key, value}
+	hashMap.insertAt(index, probeLength, bucket{
+		key:      key,
+		value:    value,
+		hash:     hash,
+		occupied: true,
+	})
+}
+
+// Delete ...
+func (hashMap *HashMap) Delete(key Key) {
+	index, probeLength, ok := hashMap.find(key, hashMap.hash(key))
+	if hashMap.config.instrumentation != nil {
+		hashMap.config.instrumentation.OnDelete(probeLength)
+	}
+	if !ok {
+		return
+	}
+
+	hashMap.deleteAt(index)
+}
+
+// IterateMutable ...
+//
+// It calls the handler for each item and applies the returned action
+// to the item (see the MutationAction type). Values are replaced in place,
+// while deleting is postponed until the end of iteration, so items aren't
+// moved while they are being visited. The map isn't rehashed.
+//
+// The handler shouldn't modify the map itself.
+//
+func (hashMap *HashMap) IterateMutable(handler MutableHandler) {
+	var deletedBuckets []bucket
+	for index := range hashMap.buckets {
+		bucket := &hashMap.buckets[index]
+		if !bucket.occupied {
+			continue
+		}
+
+		switch action, newValue := handler(bucket.key, bucket.value); action {
+		case ReplaceItem:
+			bucket.value = newValue
+		case DeleteItem:
+			deletedBuckets = append(deletedBuckets, *bucket)
+		}
+	}
+
+	// backward shifting moves items, so their positions should be found again
+	for _, bucket := range deletedBuckets {
+		index, probeLength, _ := hashMap.find(bucket.key, bucket.hash)
+		if hashMap.config.instrumentation != nil {
+			hashMap.config.instrumentation.OnDelete(probeLength)
+		}
+		hashMap.deleteAt(index)
+	}
+}
+
+// DeleteIf ...
+//
+// It deletes items for which the predicate returns true
+// (see the HashMap.IterateMutable() method).
+//
+func (hashMap *HashMap) DeleteIf(
+	predicate func(key Key, value interface{}) bool,
+) {
+	hashMap.IterateMutable(deleteIfHandler(predicate))
+}
+
+// UpdateAll ...
+//
+// It replaces values of all items by results of the function
+// (see the HashMap.IterateMutable() method).
+//
+func (hashMap *HashMap) UpdateAll(
+	update func(key Key, value interface{}) interface{},
+) {
+	hashMap.IterateMutable(updateAllHandler(update))
+}
+
+// Entry ...
+//
+// It returns a handle of an item by the key (see the Entry structure).
+// The item is searched only once for all operations via the entry.
+//
+func (hashMap *HashMap) Entry(key Key) *Entry {
+	return newHashMapEntry(hashMap, key)
+}
+
+// WithEntry ...
+//
+// It calls the handler with an entry by the key (see the HashMap.Entry()
+// method). It's used by the SynchronizedHashMap and ConcurrentHashMap
+// structures.
+//
+func (hashMap *HashMap) WithEntry(key Key, handler EntryHandler) {
+	handler(hashMap.Entry(key))
+}
+
+// Size ...
+func (hashMap HashMap) Size() int {
+	return hashMap.size
+}
+
+// Stats ...
+//
+// It calculates diagnostics of the bucket table. It's useful for checking
+// quality of a hash function of keys.
+//
+func (hashMap HashMap) Stats() Stats {
+	capacity := len(hashMap.buckets)
+	stats := Stats{
+		Size:           hashMap.size,
+		Capacity:       capacity,
+		ProbeLengths:   make(map[int]int),
+		ClusterLengths: make(map[int]int),
+	}
+	if capacity == 0 {
+		return stats
+	}
+
+	emptyIndex := -1
+	for index, bucket := range hashMap.buckets {
+		if !bucket.occupied {
+			if emptyIndex == -1 {
+				emptyIndex = index
+			}
+
+			stats.EmptySlotRatio++
+			continue
+		}
+
+		homeIndex := hashMap.homeIndexByHash(bucket.hash)
+		stats.addProbeLength((index-homeIndex+capacity)%capacity + 1)
+	}
+	stats.EmptySlotRatio /= float64(capacity)
+
+	// start from an empty bucket to avoid splitting a cluster
+	// that wraps around the end of the bucket table
+	var clusterLength int
+	for offset := 1; offset <= capacity; offset++ {
+		index := (emptyIndex + offset) % capacity
+		if hashMap.buckets[index].occupied {
+			clusterLength++
+			continue
+		}
+
+		stats.addClusterLength(clusterLength)
+		clusterLength = 0
+	}
+	stats.addClusterLength(clusterLength)
+
+	return stats
+}
+
+func (hashMap *HashMap) update(key Key, handler updateHandler) {
+	entry := hashMap.Entry(key)
+	newValue, keep := handler(entry.Value(), entry.Exists())
+	if keep {
+		entry.Set(newValue)
+	} else {
+		entry.Delete()
+	}
+}
+
+// it copies buckets too, so modifications of the clone don't affect
+// the original map
+func (hashMap HashMap) clone() *HashMap {
+	buckets := make([]bucket, len(hashMap.buckets))
+	copy(buckets, hashMap.buckets)
+
+	hashMap.buckets = buckets
+	return &hashMap
+}
+
+func (hashMap HashMap) hash(key Key) uint64 {
+	if hashMap.seed == 0 {
+		return hashKey(hashMap.config.hasher, key)
+	}
+	// the custom hasher takes precedence over own seeded hashing of the key
+	if seededKey, ok := key.(SeededKey); ok && hashMap.config.hasher == nil {
+		return seededKey.SeededHash(hashMap.seed)
+	}
+
+	return mixHash(hashKey(hashMap.config.hasher, key) ^ hashMap.seed)
+}
+
+func (hashMap HashMap) homeIndex(key Key) int {
+	return hashMap.homeIndexByHash(hashMap.hash(key))
+}
+
+func (hashMap HashMap) homeIndexByHash(hash uint64) int {
+	return int(hash % uint64(len(hashMap.buckets)))
+}
+
+// the hash should be calculated by the HashMap.hash() method
+func (hashMap HashMap) find(key Key, hash uint64) (
+	index int,
+	probeLength int,
+	ok bool,
+) {
+	for index := hashMap.homeIndexByHash(hash); ; index++ {
+		probeLength++
+
+		modIndex := index % len(hashMap.buckets)
+		bucket := &hashMap.buckets[modIndex]
+		if !bucket.occupied {
+			return modIndex, probeLength, false
+		}
+		// comparing of hashes is cheaper than calling of the Key.Equals() method
+		if bucket.hash == hash &&
+			equalKeys(hashMap.config.equaler, bucket.key, key) {
+			return modIndex, probeLength, true
+		}
+	}
+}
+
+// it puts the bucket into the free position found by the HashMap.find()
+// method; it returns true if the map is rehashed, i.e. positions of items
+// are changed
+func (hashMap *HashMap) insertAt(
+	index int,
+	probeLength int,
+	bucket bucket,
+) (rehashed bool) {
+	hashMap.buckets[index] = bucket
 	hashMap.size++
 
 	loadFactor := float64(hashMap.size) / float64(len(hashMap.buckets))
 	if loadFactor > hashMap.config.maxLoadFactor {
 		hashMap.rehash()
+		return true
 	}
-}
 
-// Delete ...
-func (hashMap *HashMap) Delete(key Key) {
-	index, ok := hashMap.find(key)
-	if !ok {
-		return
+	reseedThreshold := hashMap.config.reseedThreshold
+	if reseedThreshold != 0 && probeLength > reseedThreshold &&
+		!hashMap.reseeded {
+		hashMap.reseed()
+		return true
 	}
 
-	hashMap.buckets[index] = nil
+	return false
+}
+
+func (hashMap *HashMap) deleteAt(index int) {
+	hashMap.buckets[index] = bucket{}
 	hashMap.size--
+
+	hashMap.shiftBackward(index)
 }
 
-func (hashMap HashMap) find(key Key) (index int, ok bool) {
-	for index := key.Hash(); ; index++ {
-		modIndex := index % len(hashMap.buckets)
-		bucket := hashMap.buckets[modIndex]
-		if bucket == nil {
-			return modIndex, false
+// it moves the following buckets of the cluster to the emptied bucket
+// if they aren't at their home positions, so probe sequences stay unbroken
+func (hashMap *HashMap) shiftBackward(emptyIndex int) {
+	capacity := len(hashMap.buckets)
+	for index := (emptyIndex + 1) % capacity; ; index = (index + 1) % capacity {
+		movedBucket := hashMap.buckets[index]
+		if !movedBucket.occupied {
+			return
 		}
-		if bucket.key.Equals(key) {
-			return modIndex, true
+
+		// the bucket can be moved only if its home position doesn't lie
+		// between the empty bucket and it
+		homeIndex := hashMap.homeIndexByHash(movedBucket.hash)
+		homeDistance := (index - homeIndex + capacity) % capacity
+		emptyDistance := (index - emptyIndex + capacity) % capacity
+		if homeDistance < emptyDistance {
+			continue
 		}
+
+		hashMap.buckets[emptyIndex] = movedBucket
+		hashMap.buckets[index] = bucket{}
+		emptyIndex = index
 	}
 }
 
 func (hashMap *HashMap) rehash() {
-	newCapacity := int(float64(len(hashMap.buckets)) * hashMap.config.growFactor)
-	newHashMap := newHashMapWithCapacity(hashMap.config, newCapacity)
-	hashMap.Iterate(func(key Key, value interface{}) bool {
-		newHashMap.Set(key, value)
-		return true
-	})
+	capacity := len(hashMap.buckets)
+	newCapacity := int(float64(capacity) * hashMap.config.growFactor)
+	// a small table multiplied by a small factor may remain the same
+	if newCapacity <= capacity {
+		newCapacity = capacity + 1
+	}
+
+	hashMap.rehashWithSeed(newCapacity, hashMap.seed)
+	hashMap.reseeded = false
+}
+
+func (hashMap *HashMap) reseed() {
+	hashMap.rehashWithSeed(len(hashMap.buckets), newHashSeed())
+	hashMap.reseeded = true
+}
+
+func (hashMap *HashMap) rehashWithSeed(capacity int, seed uint64) {
+	instrumentation := hashMap.config.instrumentation
+	if instrumentation != nil {
+		instrumentation.OnRehashStart(hashMap.size, len(hashMap.buckets))
+	}
+	startTime := time.Now()
+
+	// the new map shouldn't report its own events and reseed itself
+	newConfig := hashMap.config
+	newConfig.instrumentation = nil
+	newConfig.reseedThreshold = 0
+
+	newHashMap := newHashMapWithCapacity(newConfig, capacity)
+	newHashMap.seed = seed
+	for _, bucket := range hashMap.buckets {
+		if !bucket.occupied {
+			continue
+		}
+
+		// stored hashes are valid only for the same seed
+		if seed != hashMap.seed {
+			bucket.hash = newHashMap.hash(bucket.key)
+		}
 
+		newHashMap.insert(bucket)
+	}
+
+	newHashMap.config = hashMap.config
 	*hashMap = *newHashMap
+
+	if instrumentation != nil {
+		duration := time.Since(startTime)
+		instrumentation.OnRehashEnd(hashMap.size, len(hashMap.buckets), duration)
+	}
+}
+
+// it puts the bucket into the first free position of its probe sequence
+// without comparing of keys, so the key should be absent in the map
+func (hashMap *HashMap) insert(bucket bucket) {
+	capacity := len(hashMap.buckets)
+	index := hashMap.homeIndexByHash(bucket.hash)
+	for hashMap.buckets[index].occupied {
+		index = (index + 1) % capacity
+	}
+
+	hashMap.buckets[index] = bucket
+	hashMap.size++
 }
 
 func newHashMapWithCapacity(config Config, capacity int) *HashMap {
-	buckets := make([]*bucket, capacity)
+	buckets := make([]bucket, capacity)
 	return &HashMap{config: config, buckets: buckets, size: 0}
 }
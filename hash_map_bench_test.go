@@ -1,26 +1,11 @@
 package hashmap
 
 import (
-	"encoding/binary"
 	"fmt"
-	"hash/fnv"
 	"math/rand"
 	"testing"
 )
 
-type IntKey int
-
-func (key IntKey) Hash() int {
-	hash := fnv.New32()
-	binary.Write(hash, binary.LittleEndian, int32(key)) // nolint: errcheck
-
-	return int(hash.Sum32())
-}
-
-func (key IntKey) Equals(other Key) bool {
-	return key == other.(IntKey)
-}
-
 func BenchmarkBuiltinMap(benchmark *testing.B) {
 	for _, data := range []struct {
 		name      string
@@ -165,3 +150,142 @@ func BenchmarkHashMap(benchmark *testing.B) {
 		}
 	}
 }
+
+// it measures insertion of new keys into a table that doesn't grow; keys
+// and values are converted to interfaces in advance, so allocations/op shows
+// allocations of the map itself
+//
+// with buckets stored by pointers:
+//
+//	BenchmarkHashMap_insert  391.3 ns/op  48 B/op  1 allocs/op
+//	BenchmarkHashMap/Get/1000  101.3 ns/op  5 B/op  0 allocs/op
+//
+// with buckets stored inline:
+//
+//	BenchmarkHashMap_insert  342.3 ns/op  0 B/op  0 allocs/op
+//	BenchmarkHashMap/Get/1000  78.46 ns/op  5 B/op  0 allocs/op
+//
+func BenchmarkHashMap_insert(benchmark *testing.B) {
+	keys := make([]Key, benchmark.N)
+	values := make([]interface{}, benchmark.N)
+	for i := 0; i < benchmark.N; i++ {
+		keys[i], values[i] = IntKey(i), i
+	}
+
+	hashMap := NewHashMap(WithInitialCapacity(2*benchmark.N + 1))
+	benchmark.ReportAllocs()
+	benchmark.ResetTimer()
+
+	for i := 0; i < benchmark.N; i++ {
+		hashMap.Set(keys[i], values[i])
+	}
+}
+
+// it measures operations with keys whose hashing and comparing are
+// expensive; the hashes/op and equals/op metrics show how often these
+// operations are called
+//
+// thanks to cached hashes, a key is hashed once per operation and isn't
+// hashed again on growing, and only keys with an equal hash are compared;
+// without them, growing rehashes all keys and probing compares each visited
+// key; so the metrics are checked against these upper bounds
+func BenchmarkHashMap_expensiveKeys(benchmark *testing.B) {
+	for _, data := range []struct {
+		name      string
+		prepare   func(size int) *HashMap
+		benchmark func(size int, hashMap *HashMap)
+		// the bounds are for a single call of the benchmark function
+		maxHashes func(size int) int
+		maxEquals func(size int) int
+	}{
+		{
+			name: "Get",
+			prepare: func(size int) *HashMap {
+				hashMap := NewHashMap()
+				for i := 0; i < size; i++ {
+					hashMap.Set(newExpensiveKey(i), i)
+				}
+
+				return hashMap
+			},
+			benchmark: func(size int, hashMap *HashMap) {
+				hashMap.Get(newExpensiveKey(rand.Intn(size)))
+			},
+			maxHashes: func(size int) int { return 1 },
+			maxEquals: func(size int) int { return 1 },
+		},
+		{
+			name:    "Set",
+			prepare: func(size int) *HashMap { return NewHashMap() },
+			benchmark: func(size int, hashMap *HashMap) {
+				for i := 0; i < size; i++ {
+					hashMap.Set(newExpensiveKey(i), i)
+				}
+			},
+			maxHashes: func(size int) int { return size },
+			// only repeated calls compare keys, because they update the items
+			maxEquals: func(size int) int { return size },
+		},
+	} {
+		for size := 10; size <= 1e5; size *= 10 {
+			name := fmt.Sprintf("%s/%d", data.name, size)
+			benchmark.Run(name, func(benchmark *testing.B) {
+				hashMap := data.prepare(size)
+				expensiveKeyCounters = expensiveKeyStats{}
+				benchmark.ResetTimer()
+
+				for i := 0; i < benchmark.N; i++ {
+					data.benchmark(size, hashMap)
+				}
+
+				benchmark.StopTimer()
+				// custom metrics aren't supported by Go 1.11, so they are logged;
+				// logs of benchmarks are printed regardless of the -v flag
+				hashes := float64(expensiveKeyCounters.hashes) / float64(benchmark.N)
+				equals := float64(expensiveKeyCounters.equals) / float64(benchmark.N)
+				benchmark.Logf("%.2f hashes/op, %.2f equals/op", hashes, equals)
+
+				if maxHashes := data.maxHashes(size); hashes > float64(maxHashes) {
+					benchmark.Errorf("hashes/op exceeds %d", maxHashes)
+				}
+				if maxEquals := data.maxEquals(size); equals > float64(maxEquals) {
+					benchmark.Errorf("equals/op exceeds %d", maxEquals)
+				}
+			})
+		}
+	}
+}
+
+type expensiveKeyStats struct {
+	hashes int
+	equals int
+}
+
+// nolint: gochecknoglobals
+var expensiveKeyCounters expensiveKeyStats
+
+// it's a composite key with a long common prefix, so both hashing
+// and comparing read the whole key
+type expensiveKey struct {
+	prefix string
+	id     string
+}
+
+func newExpensiveKey(id int) expensiveKey {
+	return expensiveKey{
+		prefix: "/very/long/common/prefix/of/a/composite/key/",
+		id:     fmt.Sprint(id),
+	}
+}
+
+func (key expensiveKey) Hash() int {
+	expensiveKeyCounters.hashes++
+	return StringKey(key.prefix + key.id).Hash()
+}
+
+func (key expensiveKey) Equals(other Key) bool {
+	expensiveKeyCounters.equals++
+
+	otherKey := other.(expensiveKey)
+	return key.prefix+key.id == otherKey.prefix+otherKey.id
+}
@@ -2,6 +2,7 @@ package hashmap
 
 import (
 	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,7 +26,7 @@ func TestNewHashMap(test *testing.T) {
 			},
 			want: &HashMap{
 				config:  defaultConfig,
-				buckets: make([]*bucket, defaultConfig.initialCapacity),
+				buckets: make([]bucket, defaultConfig.initialCapacity),
 				size:    0,
 			},
 		},
@@ -41,23 +42,23 @@ func TestNewHashMap(test *testing.T) {
 
 					return config
 				}(),
-				buckets: make([]*bucket, 23),
+				buckets: make([]bucket, 23),
 				size:    0,
 			},
 		},
 		{
 			name: "with the set maximal load factor",
 			args: args{
-				options: []Option{WithMaxLoadFactor(23)},
+				options: []Option{WithMaxLoadFactor(0.5)},
 			},
 			want: &HashMap{
 				config: func() Config {
 					config := defaultConfig
-					config.maxLoadFactor = 23
+					config.maxLoadFactor = 0.5
 
 					return config
 				}(),
-				buckets: make([]*bucket, defaultConfig.initialCapacity),
+				buckets: make([]bucket, defaultConfig.initialCapacity),
 				size:    0,
 			},
 		},
@@ -73,7 +74,7 @@ func TestNewHashMap(test *testing.T) {
 
 					return config
 				}(),
-				buckets: make([]*bucket, defaultConfig.initialCapacity),
+				buckets: make([]bucket, defaultConfig.initialCapacity),
 				size:    0,
 			},
 		},
@@ -82,32 +83,182 @@ func TestNewHashMap(test *testing.T) {
 			args: args{
 				options: []Option{
 					WithInitialCapacity(12),
-					WithMaxLoadFactor(23),
+					WithMaxLoadFactor(0.5),
 					WithGrowFactor(42),
 				},
 			},
 			want: &HashMap{
 				config: Config{
 					initialCapacity: 12,
-					maxLoadFactor:   23,
+					maxLoadFactor:   0.5,
 					growFactor:      42,
 				},
-				buckets: make([]*bucket, 12),
+				buckets: make([]bucket, 12),
+				size:    0,
+			},
+		},
+		{
+			name: "with the normalized config",
+			args: args{
+				options: []Option{
+					WithInitialCapacity(0),
+					WithMaxLoadFactor(1),
+					WithGrowFactor(1),
+				},
+			},
+			want: &HashMap{
+				config: Config{
+					initialCapacity: 1,
+					maxLoadFactor:   maxValidLoadFactor,
+					growFactor:      defaultConfig.growFactor,
+				},
+				buckets: make([]bucket, 1),
+				size:    0,
+			},
+		},
+		{
+			name: "with the normalized negative config",
+			args: args{
+				options: []Option{
+					WithInitialCapacity(-23),
+					WithMaxLoadFactor(-0.5),
+					WithGrowFactor(0.5),
+				},
+			},
+			want: &HashMap{
+				config: Config{
+					initialCapacity: 1,
+					maxLoadFactor:   defaultConfig.maxLoadFactor,
+					growFactor:      defaultConfig.growFactor,
+				},
+				buckets: make([]bucket, 1),
 				size:    0,
 			},
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			got := NewHashMap(data.args.options...)
+			resetSeeds(test, got)
 
 			assert.Equal(test, data.want, got)
 		})
 	}
 }
 
+func TestNewHashMapE(test *testing.T) {
+	type args struct {
+		options []Option
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    *HashMap
+		wantErr error
+	}{
+		{
+			name: "with the valid config",
+			args: args{
+				options: []Option{
+					WithInitialCapacity(12),
+					WithMaxLoadFactor(0.5),
+					WithGrowFactor(1.5),
+				},
+			},
+			want: &HashMap{
+				config: Config{
+					initialCapacity: 12,
+					maxLoadFactor:   0.5,
+					growFactor:      1.5,
+				},
+				buckets: make([]bucket, 12),
+				size:    0,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "with the zero initial capacity",
+			args: args{
+				options: []Option{WithInitialCapacity(0)},
+			},
+			want:    nil,
+			wantErr: ErrInvalidInitialCapacity,
+		},
+		{
+			name: "with the maximal load factor equal to 1",
+			args: args{
+				options: []Option{WithMaxLoadFactor(1)},
+			},
+			want:    nil,
+			wantErr: ErrInvalidMaxLoadFactor,
+		},
+		{
+			name: "with the zero maximal load factor",
+			args: args{
+				options: []Option{WithMaxLoadFactor(0)},
+			},
+			want:    nil,
+			wantErr: ErrInvalidMaxLoadFactor,
+		},
+		{
+			name: "with the grow factor equal to 1",
+			args: args{
+				options: []Option{WithGrowFactor(1)},
+			},
+			want:    nil,
+			wantErr: ErrInvalidGrowFactor,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewHashMapE(data.args.options...)
+			if got != nil {
+				resetSeeds(test, got)
+			}
+
+			assert.Equal(test, data.want, got)
+			assert.Equal(test, data.wantErr, errorCause(err))
+		})
+	}
+}
+
+func TestHashMap_normalized(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		options []Option
+	}{
+		{
+			name:    "with the zero initial capacity",
+			options: []Option{WithInitialCapacity(0)},
+		},
+		{
+			name:    "with the maximal load factor equal to 1",
+			options: []Option{WithInitialCapacity(1), WithMaxLoadFactor(1)},
+		},
+		{
+			name:    "with the grow factor equal to 1",
+			options: []Option{WithInitialCapacity(1), WithGrowFactor(1)},
+		},
+		{
+			name:    "with the small grow factor",
+			options: []Option{WithInitialCapacity(1), WithGrowFactor(1.1)},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewHashMap(data.options...)
+			for i := 0; i < 100; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+
+			_, ok := hashMap.Get(IntKey(100))
+			assert.False(test, ok)
+			assert.Equal(test, 100, hashMap.Size())
+		})
+	}
+}
+
 func TestHashMap_Get(test *testing.T) {
 	type fields struct {
-		makeBuckets func() []*bucket
+		makeBuckets func() []bucket
 	}
 	type args struct {
 		makeKey func() Key
@@ -123,8 +274,8 @@ func TestHashMap_Get(test *testing.T) {
 		{
 			name: "without buckets",
 			fields: fields{
-				makeBuckets: func() []*bucket {
-					return make([]*bucket, defaultConfig.initialCapacity)
+				makeBuckets: func() []bucket {
+					return make([]bucket, defaultConfig.initialCapacity)
 				},
 			},
 			args: args{
@@ -141,14 +292,29 @@ func TestHashMap_Get(test *testing.T) {
 		{
 			name: "with few buckets and a match at the start",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: new(MockKey), value: "six"}
-					buckets[7] = &bucket{key: new(MockKey), value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      new(MockKey),
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      new(MockKey),
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -167,7 +333,7 @@ func TestHashMap_Get(test *testing.T) {
 		{
 			name: "with few buckets and a match at the end",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -177,10 +343,25 @@ func TestHashMap_Get(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -196,10 +377,46 @@ func TestHashMap_Get(test *testing.T) {
 			wantValue: "seven",
 			wantOk:    assert.True,
 		},
+		{
+			name: "with few buckets and different hashes",
+			fields: fields{
+				makeBuckets: func() []bucket {
+					sixKey := new(MockKey)
+					sixKey.On("Equals", mock.Anything).Return(true)
+
+					// the Key.Equals() method of the first key shouldn't be called
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      new(MockKey),
+						value:    "five",
+						hash:     21,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+
+					return buckets
+				},
+			},
+			args: args{
+				makeKey: func() Key {
+					key := new(MockKey)
+					key.On("Hash").Return(5)
+
+					return key
+				},
+			},
+			wantValue: "six",
+			wantOk:    assert.True,
+		},
 		{
 			name: "with few buckets and no match",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -209,10 +426,25 @@ func TestHashMap_Get(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(false)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -237,7 +469,7 @@ func TestHashMap_Get(test *testing.T) {
 			gotValue, gotOk := hashMap.Get(key)
 
 			for _, bucket := range buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -250,7 +482,7 @@ func TestHashMap_Get(test *testing.T) {
 
 func TestHashMap_Iterate(test *testing.T) {
 	type fields struct {
-		buckets []*bucket
+		buckets []bucket
 	}
 
 	for _, data := range []struct {
@@ -263,7 +495,7 @@ func TestHashMap_Iterate(test *testing.T) {
 		{
 			name: "without buckets",
 			fields: fields{
-				buckets: make([]*bucket, defaultConfig.initialCapacity),
+				buckets: make([]bucket, defaultConfig.initialCapacity),
 			},
 			interruptOnCount: 10,
 			wantBuckets:      nil,
@@ -272,10 +504,10 @@ func TestHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets and without an interrupt",
 			fields: fields{
-				buckets: []*bucket{
-					5: {key: new(MockKey), value: "five"},
-					6: {key: new(MockKey), value: "six"},
-					7: {key: new(MockKey), value: "seven"},
+				buckets: []bucket{
+					5: {key: new(MockKey), value: "five", occupied: true},
+					6: {key: new(MockKey), value: "six", occupied: true},
+					7: {key: new(MockKey), value: "seven", occupied: true},
 				},
 			},
 			interruptOnCount: 10,
@@ -289,10 +521,10 @@ func TestHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets and with an interrupt",
 			fields: fields{
-				buckets: []*bucket{
-					5: {key: new(MockKey), value: "five"},
-					6: {key: new(MockKey), value: "six"},
-					7: {key: new(MockKey), value: "seven"},
+				buckets: []bucket{
+					5: {key: new(MockKey), value: "five", occupied: true},
+					6: {key: new(MockKey), value: "six", occupied: true},
+					7: {key: new(MockKey), value: "seven", occupied: true},
 				},
 			},
 			interruptOnCount: 2,
@@ -310,13 +542,13 @@ func TestHashMap_Iterate(test *testing.T) {
 			var gotBuckets []bucket
 			hashMap := HashMap{buckets: data.fields.buckets}
 			gotOk := hashMap.Iterate(func(key Key, value interface{}) bool {
-				gotBuckets = append(gotBuckets, bucket{key, value})
+				gotBuckets = append(gotBuckets, bucket{key: key, value: value})
 				// interrupt after a specified count of got buckets
 				return len(gotBuckets) < data.interruptOnCount
 			})
 
 			for _, bucket := range data.fields.buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -328,29 +560,29 @@ func TestHashMap_Iterate(test *testing.T) {
 
 func TestHashMap_Iterate_order(test *testing.T) {
 	hashMap := HashMap{
-		buckets: []*bucket{
-			5: {key: new(MockKey), value: "five"},
-			6: {key: new(MockKey), value: "six"},
-			7: {key: new(MockKey), value: "seven"},
+		buckets: []bucket{
+			5: {key: new(MockKey), value: "five", occupied: true},
+			6: {key: new(MockKey), value: "six", occupied: true},
+			7: {key: new(MockKey), value: "seven", occupied: true},
 		},
 	}
 
 	var gotBucketsOne []bucket
 	rand.Seed(1)
 	gotOkOne := hashMap.Iterate(func(key Key, value interface{}) bool {
-		gotBucketsOne = append(gotBucketsOne, bucket{key, value})
+		gotBucketsOne = append(gotBucketsOne, bucket{key: key, value: value})
 		return true
 	})
 
 	var gotBucketsTwo []bucket
 	rand.Seed(2)
 	gotOkTwo := hashMap.Iterate(func(key Key, value interface{}) bool {
-		gotBucketsTwo = append(gotBucketsTwo, bucket{key, value})
+		gotBucketsTwo = append(gotBucketsTwo, bucket{key: key, value: value})
 		return true
 	})
 
 	for _, bucket := range hashMap.buckets {
-		if bucket != nil {
+		if bucket.occupied {
 			mock.AssertExpectationsForObjects(test, bucket.key)
 		}
 	}
@@ -375,7 +607,7 @@ func TestHashMap_Iterate_order(test *testing.T) {
 func TestHashMap_Set(test *testing.T) {
 	type fields struct {
 		config      Config
-		makeBuckets func() []*bucket
+		makeBuckets func() []bucket
 		size        int
 	}
 	type args struct {
@@ -393,8 +625,8 @@ func TestHashMap_Set(test *testing.T) {
 			name: "without buckets",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
-					return make([]*bucket, defaultConfig.initialCapacity)
+				makeBuckets: func() []bucket {
+					return make([]bucket, defaultConfig.initialCapacity)
 				},
 				size: 0,
 			},
@@ -415,14 +647,29 @@ func TestHashMap_Set(test *testing.T) {
 			name: "with few buckets and a match at the start",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: new(MockKey), value: "six"}
-					buckets[7] = &bucket{key: new(MockKey), value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      new(MockKey),
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      new(MockKey),
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -443,7 +690,7 @@ func TestHashMap_Set(test *testing.T) {
 			name: "with few buckets and a match at the end",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -453,10 +700,25 @@ func TestHashMap_Set(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -477,7 +739,7 @@ func TestHashMap_Set(test *testing.T) {
 			name: "with few buckets and no match",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -487,10 +749,25 @@ func TestHashMap_Set(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(false)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -513,15 +790,35 @@ func TestHashMap_Set(test *testing.T) {
 			name: "with a load factor over the maximum and a match",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					threeKey := new(MockKey)
 					threeKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, 5)
-					buckets[0] = &bucket{key: new(MockKey), value: "zero"}
-					buckets[1] = &bucket{key: new(MockKey), value: "one"}
-					buckets[2] = &bucket{key: new(MockKey), value: "two"}
-					buckets[3] = &bucket{key: threeKey, value: "three"}
+					buckets := make([]bucket, 5)
+					buckets[0] = bucket{
+						key:      new(MockKey),
+						value:    "zero",
+						hash:     0,
+						occupied: true,
+					}
+					buckets[1] = bucket{
+						key:      new(MockKey),
+						value:    "one",
+						hash:     1,
+						occupied: true,
+					}
+					buckets[2] = bucket{
+						key:      new(MockKey),
+						value:    "two",
+						hash:     2,
+						occupied: true,
+					}
+					buckets[3] = bucket{
+						key:      threeKey,
+						value:    "three",
+						hash:     3,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -542,25 +839,36 @@ func TestHashMap_Set(test *testing.T) {
 			name: "with a load factor over the maximum and no match",
 			fields: fields{
 				config: defaultConfig,
-				makeBuckets: func() []*bucket {
-					zeroKey := new(MockKey)
-					zeroKey.On("Hash").Return(0)
-
-					oneKey := new(MockKey)
-					oneKey.On("Hash").Return(1)
-
-					twoKey := new(MockKey)
-					twoKey.On("Hash").Return(2)
-
+				makeBuckets: func() []bucket {
 					threeKey := new(MockKey)
-					threeKey.On("Hash").Return(3)
 					threeKey.On("Equals", mock.Anything).Return(false)
 
-					buckets := make([]*bucket, 5)
-					buckets[0] = &bucket{key: zeroKey, value: "zero"}
-					buckets[1] = &bucket{key: oneKey, value: "one"}
-					buckets[2] = &bucket{key: twoKey, value: "two"}
-					buckets[3] = &bucket{key: threeKey, value: "three"}
+					// stored hashes are reused on rehashing
+					buckets := make([]bucket, 5)
+					buckets[0] = bucket{
+						key:      new(MockKey),
+						value:    "zero",
+						hash:     0,
+						occupied: true,
+					}
+					buckets[1] = bucket{
+						key:      new(MockKey),
+						value:    "one",
+						hash:     1,
+						occupied: true,
+					}
+					buckets[2] = bucket{
+						key:      new(MockKey),
+						value:    "two",
+						hash:     2,
+						occupied: true,
+					}
+					buckets[3] = bucket{
+						key:      threeKey,
+						value:    "three",
+						hash:     3,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -595,7 +903,7 @@ func TestHashMap_Set(test *testing.T) {
 			gotValue, gotOk := hashMap.Get(key)
 
 			for _, bucket := range buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -610,7 +918,7 @@ func TestHashMap_Set(test *testing.T) {
 
 func TestHashMap_Delete(test *testing.T) {
 	type fields struct {
-		makeBuckets func() []*bucket
+		makeBuckets func() []bucket
 		size        int
 	}
 	type args struct {
@@ -626,8 +934,8 @@ func TestHashMap_Delete(test *testing.T) {
 		{
 			name: "without buckets",
 			fields: fields{
-				makeBuckets: func() []*bucket {
-					return make([]*bucket, defaultConfig.initialCapacity)
+				makeBuckets: func() []bucket {
+					return make([]bucket, defaultConfig.initialCapacity)
 				},
 				size: 0,
 			},
@@ -644,14 +952,29 @@ func TestHashMap_Delete(test *testing.T) {
 		{
 			name: "with few buckets and a match at the start",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: new(MockKey), value: "six"}
-					buckets[7] = &bucket{key: new(MockKey), value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      new(MockKey),
+						value:    "six",
+						hash:     6,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      new(MockKey),
+						value:    "seven",
+						hash:     7,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -670,7 +993,7 @@ func TestHashMap_Delete(test *testing.T) {
 		{
 			name: "with few buckets and a match at the end",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -680,10 +1003,25 @@ func TestHashMap_Delete(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(true)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -702,7 +1040,7 @@ func TestHashMap_Delete(test *testing.T) {
 		{
 			name: "with few buckets and no match",
 			fields: fields{
-				makeBuckets: func() []*bucket {
+				makeBuckets: func() []bucket {
 					fiveKey := new(MockKey)
 					fiveKey.On("Equals", mock.Anything).Return(false)
 
@@ -712,10 +1050,25 @@ func TestHashMap_Delete(test *testing.T) {
 					sevenKey := new(MockKey)
 					sevenKey.On("Equals", mock.Anything).Return(false)
 
-					buckets := make([]*bucket, defaultConfig.initialCapacity)
-					buckets[5] = &bucket{key: fiveKey, value: "five"}
-					buckets[6] = &bucket{key: sixKey, value: "six"}
-					buckets[7] = &bucket{key: sevenKey, value: "seven"}
+					buckets := make([]bucket, defaultConfig.initialCapacity)
+					buckets[5] = bucket{
+						key:      fiveKey,
+						value:    "five",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[6] = bucket{
+						key:      sixKey,
+						value:    "six",
+						hash:     5,
+						occupied: true,
+					}
+					buckets[7] = bucket{
+						key:      sevenKey,
+						value:    "seven",
+						hash:     5,
+						occupied: true,
+					}
 
 					return buckets
 				},
@@ -742,7 +1095,7 @@ func TestHashMap_Delete(test *testing.T) {
 			_, gotGetOk := hashMap.Get(key)
 
 			for _, bucket := range buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -752,3 +1105,580 @@ func TestHashMap_Delete(test *testing.T) {
 		})
 	}
 }
+
+func TestHashMap_Delete_shifting(test *testing.T) {
+	buckets := make([]bucket, 8)
+	buckets[6] = bucket{
+		key:      collidingKey{id: 1, hash: 6},
+		value:    "one",
+		hash:     6,
+		occupied: true,
+	}
+	buckets[7] = bucket{
+		key:      collidingKey{id: 2, hash: 6},
+		value:    "two",
+		hash:     6,
+		occupied: true,
+	}
+	buckets[0] = bucket{
+		key:      collidingKey{id: 3, hash: 0},
+		value:    "three",
+		hash:     0,
+		occupied: true,
+	}
+	buckets[1] = bucket{
+		key:      collidingKey{id: 4, hash: 7},
+		value:    "four",
+		hash:     7,
+		occupied: true,
+	}
+	buckets[2] = bucket{
+		key:      collidingKey{id: 5, hash: 2},
+		value:    "five",
+		hash:     2,
+		occupied: true,
+	}
+
+	hashMap := HashMap{buckets: buckets, size: 5}
+	hashMap.Delete(collidingKey{id: 1, hash: 6})
+
+	wantBuckets := make([]bucket, 8)
+	wantBuckets[6] = bucket{
+		key:      collidingKey{id: 2, hash: 6},
+		value:    "two",
+		hash:     6,
+		occupied: true,
+	}
+	wantBuckets[7] = bucket{
+		key:      collidingKey{id: 4, hash: 7},
+		value:    "four",
+		hash:     7,
+		occupied: true,
+	}
+	wantBuckets[0] = bucket{
+		key:      collidingKey{id: 3, hash: 0},
+		value:    "three",
+		hash:     0,
+		occupied: true,
+	}
+	wantBuckets[2] = bucket{
+		key:      collidingKey{id: 5, hash: 2},
+		value:    "five",
+		hash:     2,
+		occupied: true,
+	}
+	assert.Equal(test, wantBuckets, hashMap.buckets)
+	assert.Equal(test, 4, hashMap.size)
+}
+
+func TestHashMap_instrumentation(test *testing.T) {
+	instrumentation := new(MockInstrumentation)
+	instrumentation.On("OnSet", 1).Times(4)
+	instrumentation.On("OnSet", 2).Once()
+	instrumentation.On("OnGetHit", 2).Once()
+	instrumentation.On("OnGetMiss", 1).Once()
+	instrumentation.On("OnDelete", 2).Once()
+	instrumentation.On("OnRehashStart", 4, 5).Once()
+	instrumentation.
+		On("OnRehashEnd", 4, 10, mock.AnythingOfType("time.Duration")).
+		Once()
+
+	hashMap := NewHashMap(
+		WithInitialCapacity(5),
+		WithInstrumentation(instrumentation),
+	)
+	// positions of keys should be predictable
+	hashMap.seed = 0
+	hashMap.Set(collidingKey{id: 1, hash: 0}, "one")
+	hashMap.Set(collidingKey{id: 2, hash: 5}, "two")
+	hashMap.Set(collidingKey{id: 3, hash: 2}, "three")
+	hashMap.Get(collidingKey{id: 2, hash: 5})
+	hashMap.Get(collidingKey{id: 4, hash: 3})
+	hashMap.Delete(collidingKey{id: 2, hash: 5})
+	hashMap.Set(collidingKey{id: 4, hash: 3}, "four")
+	// it triggers the rehashing that shouldn't report events of the new map
+	hashMap.Set(collidingKey{id: 5, hash: 4}, "five")
+
+	mock.AssertExpectationsForObjects(test, instrumentation)
+}
+
+func TestHashMap_seed(test *testing.T) {
+	test.Run("with a random seed", func(test *testing.T) {
+		hashMap := NewHashMap()
+		otherHashMap := NewHashMap()
+
+		assert.NotZero(test, hashMap.seed)
+		assert.NotEqual(test, hashMap.seed, otherHashMap.seed)
+	})
+
+	test.Run("with the set seed", func(test *testing.T) {
+		hashMap := NewHashMap(WithHashSeed(23))
+		otherHashMap := NewHashMap(WithHashSeed(23))
+
+		assert.Equal(test, uint64(23), hashMap.seed)
+		for i := 0; i < 100; i++ {
+			key := collidingKey{id: i, hash: i}
+			assert.Equal(test, hashMap.homeIndex(key), otherHashMap.homeIndex(key))
+		}
+	})
+
+	test.Run("with a seeded key", func(test *testing.T) {
+		hashMap := NewHashMap(WithHashSeed(23))
+
+		got := hashMap.hash(StringKey("one"))
+
+		assert.Equal(test, StringKey("one").SeededHash(23), got)
+	})
+
+	test.Run("with a negative hash", func(test *testing.T) {
+		hashMap := NewHashMap(WithHashSeed(23))
+		hashMap.Set(collidingKey{id: 1, hash: -23}, "one")
+
+		gotValue, gotOk := hashMap.Get(collidingKey{id: 1, hash: -23})
+
+		assert.Equal(test, "one", gotValue)
+		assert.True(test, gotOk)
+	})
+}
+
+func TestHashMap_reseed(test *testing.T) {
+	hashMap := NewHashMap(
+		WithInitialCapacity(100),
+		WithHashSeed(23),
+		WithReseedThreshold(2),
+	)
+	for i := 0; i < 50; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i * 100}, i)
+	}
+
+	assert.NotEqual(test, uint64(23), hashMap.seed)
+	assert.True(test, hashMap.reseeded)
+	assert.Len(test, hashMap.buckets, 100)
+	assert.Equal(test, 50, hashMap.size)
+	for i := 0; i < 50; i++ {
+		gotValue, gotOk := hashMap.Get(collidingKey{id: i, hash: i * 100})
+
+		assert.Equal(test, i, gotValue)
+		assert.True(test, gotOk)
+	}
+
+	// the growth should allow the next reseeding
+	for i := 50; i < 100; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i * 100}, i)
+	}
+
+	assert.Len(test, hashMap.buckets, 200)
+	assert.Equal(test, 100, hashMap.size)
+}
+
+func TestHashMap_withHasherAndEqualer(test *testing.T) {
+	test.Run("case-insensitive keys", func(test *testing.T) {
+		hashMap := NewHashMap(
+			WithHasher(caseInsensitiveHasher),
+			WithEqualer(caseInsensitiveEqualer),
+		)
+		hashMap.Set(StringKey("key"), "one")
+		hashMap.Set(StringKey("KEY"), "two")
+
+		gotValue, gotOk := hashMap.Get(StringKey("Key"))
+		assert.Equal(test, "two", gotValue)
+		assert.True(test, gotOk)
+		assert.Equal(test, 1, hashMap.Size())
+
+		hashMap.Delete(StringKey("kEY"))
+		assert.Equal(test, 0, hashMap.Size())
+	})
+
+	test.Run("hashing of the same key type differently", func(test *testing.T) {
+		hashMapOne := NewHashMap(WithHashSeed(23))
+		hashMapTwo := NewHashMap(
+			WithHashSeed(23),
+			WithHasher(func(key Key) uint64 { return uint64(key.(IntKey)) }),
+		)
+		hashMapOne.Set(IntKey(5), "five")
+		hashMapTwo.Set(IntKey(5), "five")
+
+		assert.NotEqual(
+			test,
+			hashMapOne.hash(IntKey(5)),
+			hashMapTwo.hash(IntKey(5)),
+		)
+		assert.Equal(test, mixHash(5^23), hashMapTwo.hash(IntKey(5)))
+
+		gotValue, gotOk := hashMapTwo.Get(IntKey(5))
+		assert.Equal(test, "five", gotValue)
+		assert.True(test, gotOk)
+	})
+
+	test.Run("default delegation to the key", func(test *testing.T) {
+		key := new(MockKey)
+		key.On("Hash").Return(23)
+		key.On("Equals", key).Return(true)
+
+		hashMap := NewHashMap()
+		hashMap.seed = 0
+		hashMap.Set(key, "value")
+		hashMap.Set(key, "value")
+
+		assert.Equal(test, uint64(23), hashMap.hash(key))
+		assert.Equal(test, 1, hashMap.Size())
+		mock.AssertExpectationsForObjects(test, key)
+	})
+}
+
+func TestHashMap_withHash64Key(test *testing.T) {
+	hashMap := NewHashMap(WithInitialCapacity(12))
+	hashMap.seed = 0
+
+	key := hash64Key{collidingKey: collidingKey{id: 1, hash: 5}}
+	hashMap.Set(key, "one")
+
+	// 2^63 + 5 = 1 (mod 12), not 5 as with the Key.Hash() method
+	assert.Equal(test, key, hashMap.buckets[1].key)
+	gotValue, gotOk := hashMap.Get(key)
+	assert.Equal(test, "one", gotValue)
+	assert.True(test, gotOk)
+}
+
+func TestHashMap_Scan(test *testing.T) {
+	type args struct {
+		cursor uint64
+		count  int
+	}
+
+	for _, data := range []struct {
+		name           string
+		capacity       int
+		keys           []collidingKey
+		args           args
+		wantItems      []ScanItem
+		wantNextCursor uint64
+	}{
+		{
+			name:           "empty",
+			capacity:       8,
+			keys:           nil,
+			args:           args{cursor: 0, count: 10},
+			wantItems:      nil,
+			wantNextCursor: 0,
+		},
+		{
+			name:     "from the start",
+			capacity: 8,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 1},
+				{id: 2, hash: 2},
+				{id: 3, hash: 3},
+				{id: 4, hash: 4},
+				{id: 5, hash: 5},
+			},
+			args: args{cursor: 0, count: 2},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 0, hash: 0}, Value: 0},
+				{Key: collidingKey{id: 4, hash: 4}, Value: 4},
+			},
+			wantNextCursor: 2,
+		},
+		{
+			name:     "from the middle",
+			capacity: 8,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 1},
+				{id: 2, hash: 2},
+				{id: 3, hash: 3},
+				{id: 4, hash: 4},
+				{id: 5, hash: 5},
+			},
+			args: args{cursor: 2, count: 2},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 2, hash: 2}, Value: 2},
+				{Key: collidingKey{id: 1, hash: 1}, Value: 1},
+			},
+			wantNextCursor: 5,
+		},
+		{
+			name:     "until the end",
+			capacity: 8,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 1},
+				{id: 2, hash: 2},
+				{id: 3, hash: 3},
+				{id: 4, hash: 4},
+				{id: 5, hash: 5},
+			},
+			args: args{cursor: 3, count: 2},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 3, hash: 3}, Value: 3},
+			},
+			wantNextCursor: 0,
+		},
+		{
+			name:     "with the entire class",
+			capacity: 8,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 8},
+				{id: 2, hash: 16},
+				{id: 3, hash: 4},
+			},
+			args: args{cursor: 0, count: 1},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 0, hash: 0}, Value: 0},
+				{Key: collidingKey{id: 1, hash: 8}, Value: 1},
+				{Key: collidingKey{id: 2, hash: 16}, Value: 2},
+			},
+			wantNextCursor: 4,
+		},
+		{
+			name:     "with a capacity that isn't a power of two, from the start",
+			capacity: 6,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 1},
+				{id: 2, hash: 2},
+				{id: 3, hash: 3},
+			},
+			args: args{cursor: 0, count: 2},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 0, hash: 0}, Value: 0},
+				{Key: collidingKey{id: 2, hash: 2}, Value: 2},
+			},
+			wantNextCursor: 6,
+		},
+		{
+			name:     "with a capacity that isn't a power of two, until the end",
+			capacity: 6,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 1},
+				{id: 2, hash: 2},
+				{id: 3, hash: 3},
+			},
+			args: args{cursor: 6, count: 10},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 1, hash: 1}, Value: 1},
+				{Key: collidingKey{id: 3, hash: 3}, Value: 3},
+			},
+			wantNextCursor: 0,
+		},
+		{
+			name:     "with a capacity that isn't a power of two, with the entire class",
+			capacity: 6,
+			keys: []collidingKey{
+				{id: 0, hash: 0},
+				{id: 1, hash: 8},
+				{id: 2, hash: 4},
+			},
+			args: args{cursor: 0, count: 1},
+			wantItems: []ScanItem{
+				{Key: collidingKey{id: 0, hash: 0}, Value: 0},
+				{Key: collidingKey{id: 1, hash: 8}, Value: 1},
+			},
+			wantNextCursor: 4,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewHashMap(WithInitialCapacity(data.capacity))
+			hashMap.seed = 0
+			for _, key := range data.keys {
+				hashMap.Set(key, key.id)
+			}
+
+			gotItems, gotNextCursor := hashMap.Scan(data.args.cursor, data.args.count)
+
+			assert.Len(test, hashMap.buckets, data.capacity)
+			assert.ElementsMatch(test, data.wantItems, gotItems)
+			assert.Equal(test, data.wantNextCursor, gotNextCursor)
+		})
+	}
+}
+
+func TestHashMap_Scan_withRehashing(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		growFactor float64
+	}{
+		{
+			name:       "with capacities that are powers of two",
+			growFactor: 2,
+		},
+		{
+			name:       "with capacities that aren't powers of two",
+			growFactor: 1.5,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewHashMap(
+				WithInitialCapacity(8),
+				WithGrowFactor(data.growFactor),
+			)
+			for i := 0; i < 6; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+
+			gotValues := make(map[interface{}]struct{})
+			var cursor uint64
+			for call := 0; ; call++ {
+				var items []ScanItem
+				items, cursor = hashMap.Scan(cursor, 2)
+				for _, item := range items {
+					gotValues[item.Value] = struct{}{}
+				}
+				if cursor == 0 {
+					break
+				}
+
+				if call >= 3 {
+					continue
+				}
+
+				// the map is grown between first calls
+				for i := 0; i < 50; i++ {
+					key := 100 + call*50 + i
+					hashMap.Set(IntKey(key), key)
+				}
+			}
+
+			assert.True(test, len(hashMap.buckets) > 8)
+			for i := 0; i < 6; i++ {
+				assert.Contains(test, gotValues, i)
+			}
+		})
+	}
+}
+
+func TestHashMap_IterateMutable(test *testing.T) {
+	// deleting of the first colliding keys shifts the following ones
+	// backward, including ones of the wrapped cluster
+	hashMap := NewHashMap(WithInitialCapacity(8))
+	hashMap.seed = 0
+	hashes := []int{6, 6, 6, 7, 1, 2}
+	for id, hash := range hashes {
+		hashMap.Set(collidingKey{id: id, hash: hash}, id)
+	}
+
+	visitedIDs := make(map[int]int)
+	hashMap.IterateMutable(
+		func(key Key, value interface{}) (MutationAction, interface{}) {
+			id := key.(collidingKey).id
+			visitedIDs[id]++
+
+			switch {
+			case id < 2:
+				return DeleteItem, nil
+			case id%2 == 0:
+				return ReplaceItem, value.(int) * 10
+			default:
+				return KeepItem, nil
+			}
+		},
+	)
+
+	assert.Equal(test, map[int]int{0: 1, 1: 1, 2: 1, 3: 1, 4: 1, 5: 1}, visitedIDs)
+	assert.Equal(test, 4, hashMap.Size())
+	for id, wantValue := range map[int]interface{}{
+		0: nil,
+		1: nil,
+		2: 20,
+		3: 3,
+		4: 40,
+		5: 5,
+	} {
+		gotValue, gotOk := hashMap.Get(collidingKey{id: id, hash: hashes[id]})
+
+		assert.Equal(test, wantValue, gotValue, id)
+		assert.Equal(test, wantValue != nil, gotOk, id)
+	}
+}
+
+func TestHashMap_DeleteIf(test *testing.T) {
+	hashMap := NewHashMap()
+	for i := 0; i < 100; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	hashMap.DeleteIf(func(key Key, value interface{}) bool {
+		return value.(int)%2 == 0
+	})
+
+	assert.Equal(test, 50, hashMap.Size())
+	for i := 0; i < 100; i++ {
+		_, gotOk := hashMap.Get(IntKey(i))
+		assert.Equal(test, i%2 != 0, gotOk, i)
+	}
+}
+
+func TestHashMap_UpdateAll(test *testing.T) {
+	hashMap := NewHashMap()
+	for i := 0; i < 100; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	hashMap.UpdateAll(func(key Key, value interface{}) interface{} {
+		return value.(int) * 10
+	})
+
+	assert.Equal(test, 100, hashMap.Size())
+	for i := 0; i < 100; i++ {
+		gotValue, _ := hashMap.Get(IntKey(i))
+		assert.Equal(test, i*10, gotValue, i)
+	}
+}
+
+func caseInsensitiveHasher(key Key) uint64 {
+	return uint64(StringKey(strings.ToLower(string(key.(StringKey)))).Hash())
+}
+
+func caseInsensitiveEqualer(a Key, b Key) bool {
+	return strings.EqualFold(string(a.(StringKey)), string(b.(StringKey)))
+}
+
+type collidingKey struct {
+	id   int
+	hash int
+}
+
+func (key collidingKey) Hash() int {
+	return key.hash
+}
+
+func (key collidingKey) Equals(other Key) bool {
+	return key.id == other.(collidingKey).id
+}
+
+// it checks that all maps in the tree of the storage are seeded and resets
+// their seeds, so the storage can be compared with an expected one
+// it returns the sentinel error that is detailed by the passed one
+// (see the detailedError structure)
+func errorCause(err error) error {
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		return causer.Cause()
+	}
+
+	return err
+}
+
+func resetSeeds(test *testing.T, storage interface{}) {
+	switch storage := storage.(type) {
+	case *HashMap:
+		assert.NotZero(test, storage.seed)
+		storage.seed = 0
+	case *SynchronizedHashMap:
+		resetSeeds(test, storage.innerMap)
+	case *FallibleSynchronizedHashMap:
+		resetSeeds(test, storage.innerMap)
+	case FallibleAdapter:
+		resetSeeds(test, storage.storage)
+	case ConcurrentHashMap:
+		for _, segment := range storage.segments {
+			resetSeeds(test, segment)
+		}
+	case FallibleConcurrentHashMap:
+		for _, segment := range storage.segments {
+			resetSeeds(test, segment)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package hashmap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// it returns an unpredictable non-zero seed
+func newHashSeed() uint64 {
+	var seed uint64
+	if err := binary.Read(rand.Reader, binary.LittleEndian, &seed); err != nil {
+		// it's still better than no seed at all
+		seed = mixHash(uint64(time.Now().UnixNano()))
+	}
+	if seed == 0 {
+		seed = 1
+	}
+
+	return seed
+}
+
+// it's the finalizer of the SplitMix64 generator; it spreads changes of any
+// input bit over all output bits
+func mixHash(hash uint64) uint64 {
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+
+	return hash
+}
@@ -0,0 +1,154 @@
+package hashmap
+
+// KeyHandler ...
+type KeyHandler func(key Key) bool
+
+// HashSet ...
+//
+// It stores keys in an inner storage with empty values.
+//
+// It's safe for concurrent access only if it's created
+// by the NewSynchronizedHashSet() or NewConcurrentHashSet() functions.
+//
+type HashSet struct {
+	storage        Storage
+	storageFactory StorageFactory
+}
+
+// NewHashSet ...
+//
+// It uses the HashMap structure as an inner storage.
+//
+func NewHashSet(options ...Option) *HashSet {
+	return newHashSet(func() Storage { return NewHashMap(options...) })
+}
+
+// NewSynchronizedHashSet ...
+//
+// It uses the SynchronizedHashMap structure as an inner storage.
+//
+// The inner map given by the WithInnerMap() option is used only by this set;
+// sets returned by the set operations use a new HashMap structure instead
+// of it.
+//
+func NewSynchronizedHashSet(options ...SynchronizedOption) *HashSet {
+	return &HashSet{
+		storage: NewSynchronizedHashMap(options...),
+		storageFactory: func() Storage {
+			// the option appended last replaces the inner map of this set,
+			// so the sets don't share it
+			resultOptions := append(
+				options[:len(options):len(options)],
+				WithInnerMap(NewHashMap()),
+			)
+			return NewSynchronizedHashMap(resultOptions...)
+		},
+	}
+}
+
+// NewConcurrentHashSet ...
+//
+// It uses the ConcurrentHashMap structure as an inner storage.
+//
+func NewConcurrentHashSet(options ...ConcurrentOption) *HashSet {
+	return newHashSet(func() Storage { return NewConcurrentHashMap(options...) })
+}
+
+// Add ...
+func (set *HashSet) Add(key Key) {
+	set.storage.Set(key, struct{}{})
+}
+
+// Contains ...
+func (set *HashSet) Contains(key Key) bool {
+	_, ok := set.storage.Get(key)
+	return ok
+}
+
+// Remove ...
+func (set *HashSet) Remove(key Key) {
+	set.storage.Delete(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// Iteration order is defined by the inner storage.
+//
+func (set *HashSet) Iterate(handler KeyHandler) bool {
+	return set.storage.Iterate(func(key Key, value interface{}) bool {
+		return handler(key)
+	})
+}
+
+// Size ...
+//
+// If the inner storage doesn't implement the Sizer interface, keys are counted
+// via iteration.
+//
+func (set *HashSet) Size() int {
+	return storageSize(set.storage)
+}
+
+// Union ...
+//
+// It returns a new set of the same variant as this one.
+//
+func (set *HashSet) Union(other *HashSet) *HashSet {
+	result := set.empty()
+	for _, operand := range []*HashSet{set, other} {
+		operand.Iterate(func(key Key) bool {
+			result.Add(key)
+			return true
+		})
+	}
+
+	return result
+}
+
+// Intersect ...
+//
+// It returns a new set of the same variant as this one.
+//
+func (set *HashSet) Intersect(other *HashSet) *HashSet {
+	return set.filter(func(key Key) bool { return other.Contains(key) })
+}
+
+// Difference ...
+//
+// It returns a new set of the same variant as this one with keys
+// that are absent in the other set.
+//
+func (set *HashSet) Difference(other *HashSet) *HashSet {
+	return set.filter(func(key Key) bool { return !other.Contains(key) })
+}
+
+// IsSubset ...
+//
+// It checks that all keys of this set are present in the other set.
+//
+func (set *HashSet) IsSubset(other *HashSet) bool {
+	return set.Iterate(func(key Key) bool { return other.Contains(key) })
+}
+
+func (set *HashSet) empty() *HashSet {
+	return newHashSet(set.storageFactory)
+}
+
+func (set *HashSet) filter(predicate KeyHandler) *HashSet {
+	result := set.empty()
+	set.Iterate(func(key Key) bool {
+		if predicate(key) {
+			result.Add(key)
+		}
+
+		return true
+	})
+
+	return result
+}
+
+func newHashSet(storageFactory StorageFactory) *HashSet {
+	return &HashSet{storage: storageFactory(), storageFactory: storageFactory}
+}
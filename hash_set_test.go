@@ -0,0 +1,110 @@
+package hashmap
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashSet(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		makeSet func() *HashSet
+	}{
+		{
+			name:    "with the plain set",
+			makeSet: func() *HashSet { return NewHashSet() },
+		},
+		{
+			name:    "with the synchronized set",
+			makeSet: func() *HashSet { return NewSynchronizedHashSet() },
+		},
+		{
+			name: "with the synchronized set and the inner map",
+			makeSet: func() *HashSet {
+				return NewSynchronizedHashSet(WithInnerMap(NewHashMap()))
+			},
+		},
+		{
+			name:    "with the concurrent set",
+			makeSet: func() *HashSet { return NewConcurrentHashSet() },
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			makeSet := func(keys ...int) *HashSet {
+				set := data.makeSet()
+				for _, key := range keys {
+					set.Add(IntKey(key))
+				}
+
+				return set
+			}
+
+			set := makeSet(1, 2, 3, 4)
+			set.Add(IntKey(4))
+			set.Remove(IntKey(4))
+			set.Remove(IntKey(5))
+			other := makeSet(2, 3, 5)
+
+			assert.True(test, set.Contains(IntKey(1)))
+			assert.False(test, set.Contains(IntKey(4)))
+			assert.Equal(test, 3, set.Size())
+			assert.Equal(test, []int{1, 2, 3, 5}, sortedSetKeys(set.Union(other)))
+			assert.Equal(test, []int{2, 3}, sortedSetKeys(set.Intersect(other)))
+			assert.Equal(test, []int{1}, sortedSetKeys(set.Difference(other)))
+			assert.Equal(test, []int{1, 2, 3}, sortedSetKeys(set))
+			assert.False(test, set.IsSubset(other))
+			assert.True(test, makeSet(2, 3).IsSubset(set))
+			assert.True(test, makeSet().IsSubset(set))
+			assert.IsType(test, set.storage, set.Union(other).storage)
+		})
+	}
+}
+
+func TestHashSet_Iterate(test *testing.T) {
+	set := NewHashSet()
+	for i := 0; i < 10; i++ {
+		set.Add(IntKey(i))
+	}
+
+	var gotKeys []Key
+	gotOk := set.Iterate(func(key Key) bool {
+		gotKeys = append(gotKeys, key)
+		return len(gotKeys) < 5
+	})
+
+	assert.Len(test, gotKeys, 5)
+	assert.False(test, gotOk)
+}
+
+func TestNewSynchronizedHashSet_withInnerMap(test *testing.T) {
+	makeSet := func(keys ...int) *HashSet {
+		set := NewSynchronizedHashSet(WithInnerMap(NewHashMap()))
+		for _, key := range keys {
+			set.Add(IntKey(key))
+		}
+
+		return set
+	}
+
+	set := makeSet(1, 2)
+	other := makeSet(3)
+
+	assert.Equal(test, 0, set.Intersect(other).Size())
+	assert.Equal(test, []int{1, 2, 3}, sortedSetKeys(set.Union(other)))
+	assert.Equal(test, []int{1, 2}, sortedSetKeys(set.Difference(other)))
+	assert.Equal(test, []int{1, 2}, sortedSetKeys(set))
+	assert.Equal(test, []int{3}, sortedSetKeys(other))
+}
+
+func sortedSetKeys(set *HashSet) []int {
+	var keys []int
+	set.Iterate(func(key Key) bool {
+		keys = append(keys, int(key.(IntKey)))
+		return true
+	})
+	sort.Ints(keys)
+
+	return keys
+}
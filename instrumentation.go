@@ -0,0 +1,75 @@
+package hashmap
+
+import (
+	"time"
+)
+
+//go:generate mockery -name=Instrumentation -inpkg -case=underscore -testonly
+
+// Instrumentation ...
+//
+// It's called by maps on their events. Its implementation should be safe
+// for concurrent access if it's shared by maps that are accessed
+// concurrently.
+//
+type Instrumentation interface {
+	// it's called when the HashMap.Get() method finds the key;
+	// the probe length is a count of buckets checked during the search
+	OnGetHit(probeLength int)
+	// it's called when the HashMap.Get() method doesn't find the key;
+	// the probe length is a count of buckets checked during the search
+	OnGetMiss(probeLength int)
+	// it's called by the HashMap.Set() method;
+	// the probe length is a count of buckets checked during the search
+	OnSet(probeLength int)
+	// it's called by the HashMap.Delete() method;
+	// the probe length is a count of buckets checked during the search
+	OnDelete(probeLength int)
+	// it's called by the HashMap structure before rehashing
+	OnRehashStart(size int, capacity int)
+	// it's called by the HashMap structure after rehashing;
+	// the capacity is a new one
+	OnRehashEnd(size int, capacity int, duration time.Duration)
+	// it's called by the SynchronizedHashMap structure on each lock
+	// acquisition; the wait is a time spent on the acquisition
+	OnLockContention(wait time.Duration)
+	// it's called by the ConcurrentHashMap structure after each modification
+	// of a segment that is able to report its size (see the Sizer interface)
+	OnSegmentLoad(segment int, size int)
+}
+
+// NopInstrumentation ...
+//
+// It ignores all events. It can be embedded to implement only required
+// methods of the Instrumentation interface.
+//
+type NopInstrumentation struct{}
+
+// OnGetHit ...
+func (NopInstrumentation) OnGetHit(probeLength int) {}
+
+// OnGetMiss ...
+func (NopInstrumentation) OnGetMiss(probeLength int) {}
+
+// OnSet ...
+func (NopInstrumentation) OnSet(probeLength int) {}
+
+// OnDelete ...
+func (NopInstrumentation) OnDelete(probeLength int) {}
+
+// OnRehashStart ...
+func (NopInstrumentation) OnRehashStart(size int, capacity int) {}
+
+// OnRehashEnd ...
+func (NopInstrumentation) OnRehashEnd(
+	size int,
+	capacity int,
+	duration time.Duration,
+) {
+}
+
+// OnLockContention ...
+func (NopInstrumentation) OnLockContention(wait time.Duration) {}
+
+// OnSegmentLoad ...
+func (NopInstrumentation) OnSegmentLoad(segment int, size int) {}
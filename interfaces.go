@@ -1,13 +1,60 @@
 package hashmap
 
+import (
+	"context"
+)
+
 //go:generate mockery -name=Key -inpkg -case=underscore -testonly
 
 // Key ...
+//
+// All bits of a hash are significant, negative hashes are allowed. Maps mix
+// hashes before use, so a hash doesn't have to be well-distributed, but equal
+// keys should have equal hashes. On 32-bit platforms a hash has only 32 bits,
+// so a key is able to provide more via the Hash64Key interface.
+//
 type Key interface {
 	Hash() int
 	Equals(key Key) bool
 }
 
+// Hash64Key ...
+//
+// It's an optional interface of a key that is able to return a 64-bit hash
+// regardless of the platform. Maps prefer it over the Key.Hash() method.
+// Its hash should be consistent with the Key.Equals() method, like
+// the Key.Hash() one.
+//
+type Hash64Key interface {
+	Key
+
+	Hash64() uint64
+}
+
+// SeededKey ...
+//
+// It's an optional interface of a key that is able to hash itself with
+// a seed of a map. Its hashing should be keyed by the seed (e.g. via
+// the SipHash function), so that it's safe against hash flooding.
+//
+type SeededKey interface {
+	Key
+
+	SeededHash(seed uint64) uint64
+}
+
+// OrderedKey ...
+//
+// It's an optional interface of a key that is required by sorted maps.
+// The Less method should define a strict weak ordering that is consistent
+// with the Equals method.
+//
+type OrderedKey interface {
+	Key
+
+	Less(other Key) bool
+}
+
 //go:generate mockery -name=Storage -inpkg -case=underscore -testonly
 
 // Storage ...
@@ -17,3 +64,26 @@ type Storage interface {
 	Set(key Key, value interface{})
 	Delete(key Key)
 }
+
+//go:generate mockery -name=FallibleStorage -inpkg -case=underscore -testonly
+
+// FallibleStorage ...
+//
+// It's the same as the Storage interface, but its operations are able
+// to fail and to be cancelled via a context.
+//
+type FallibleStorage interface {
+	Get(ctx context.Context, key Key) (value interface{}, ok bool, err error)
+	Iterate(ctx context.Context, handler Handler) (ok bool, err error)
+	Set(ctx context.Context, key Key, value interface{}) error
+	Delete(ctx context.Context, key Key) error
+}
+
+// Sizer ...
+//
+// It's an optional interface of a storage that is able to report a count
+// of its items.
+//
+type Sizer interface {
+	Size() int
+}
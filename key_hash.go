@@ -0,0 +1,122 @@
+package hashmap
+
+import (
+	"encoding/binary"
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// it's the fractional part of the golden ratio; it separates combined hashes
+// from zeros
+const goldenRatio64 = 0x9e3779b97f4a7c15
+
+// CombineHashes ...
+//
+// It mixes the hash of a field into the hash of a whole key. The combination
+// depends on the order of fields and spreads changes of any field over all
+// bits of the result. It's a helper for implementing of the key interface
+// (e.g. by the code generated by the hashmap-keygen command).
+//
+func CombineHashes(hash uint64, fieldHash uint64) uint64 {
+	return mixHash(hash ^ (fieldHash + goldenRatio64))
+}
+
+// HashKey ...
+//
+// It prefers the Hash64Key interface over the Key.Hash() method
+// like the HashMap structure does.
+//
+func HashKey(key Key) uint64 {
+	return hashKey(nil, key)
+}
+
+// HashString ...
+//
+// It's consistent with the == operator.
+//
+func HashString(value string) uint64 {
+	return StringKey(value).Hash64()
+}
+
+// HashStringFold ...
+//
+// It's consistent with the strings.EqualFold() function: runes are replaced
+// by the smallest runes equivalent to them under Unicode case folding.
+//
+func HashStringFold(value string) uint64 {
+	data := make([]byte, 0, len(value))
+	for _, symbol := range value {
+		data = appendRune(data, foldRune(symbol))
+	}
+
+	return sipHash(builtinHashKey0, builtinHashKey1, data)
+}
+
+// HashUint64 ...
+//
+// It's consistent with the == operator. Signed integers should be converted
+// to the uint64 type as is.
+//
+func HashUint64(value uint64) uint64 {
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], value)
+
+	return sipHash(builtinHashKey0, builtinHashKey1, data[:])
+}
+
+// HashBool ...
+//
+// It's consistent with the == operator.
+//
+func HashBool(value bool) uint64 {
+	if value {
+		return HashUint64(1)
+	}
+
+	return HashUint64(0)
+}
+
+// HashFloat64 ...
+//
+// It's consistent with the == operator: positive and negative zeros have
+// equal hashes.
+//
+func HashFloat64(value float64) uint64 {
+	if value == 0 {
+		value = 0
+	}
+
+	return HashUint64(math.Float64bits(value))
+}
+
+// HashComplex128 ...
+//
+// It's consistent with the == operator.
+//
+func HashComplex128(value complex128) uint64 {
+	return CombineHashes(HashFloat64(real(value)), HashFloat64(imag(value)))
+}
+
+// it returns the smallest rune of the orbit of the rune under Unicode case
+// folding, so runes that are equal via the strings.EqualFold() function
+// are mapped to the same one
+func foldRune(symbol rune) rune {
+	smallestSymbol := symbol
+	for folded := unicode.SimpleFold(symbol); folded != symbol; {
+		if folded < smallestSymbol {
+			smallestSymbol = folded
+		}
+
+		folded = unicode.SimpleFold(folded)
+	}
+
+	return smallestSymbol
+}
+
+func appendRune(data []byte, symbol rune) []byte {
+	var buffer [utf8.UTFMax]byte
+	size := utf8.EncodeRune(buffer[:], symbol)
+
+	return append(data, buffer[:size]...)
+}
@@ -0,0 +1,104 @@
+package hashmap
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombineHashes(test *testing.T) {
+	hashOne := CombineHashes(CombineHashes(0, 1), 2)
+	hashTwo := CombineHashes(CombineHashes(0, 2), 1)
+
+	assert.Equal(test, hashOne, CombineHashes(CombineHashes(0, 1), 2))
+	assert.NotEqual(test, hashOne, hashTwo)
+	assert.NotZero(test, CombineHashes(0, 0))
+}
+
+func TestHashKey(test *testing.T) {
+	key := hash64Key{collidingKey: collidingKey{id: 1, hash: 1}}
+	assert.Equal(test, key.Hash64(), HashKey(key))
+}
+
+func TestHashString(test *testing.T) {
+	assert.Equal(test, StringKey("one").Hash64(), HashString("one"))
+	assert.NotEqual(test, HashString("one"), HashString("One"))
+}
+
+func TestHashStringFold(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		valueOne  string
+		valueTwo  string
+		wantEqual bool
+	}{
+		{
+			name:      "with equal strings",
+			valueOne:  "one",
+			valueTwo:  "one",
+			wantEqual: true,
+		},
+		{
+			name:      "with strings different in a case",
+			valueOne:  "one",
+			valueTwo:  "oNE",
+			wantEqual: true,
+		},
+		{
+			name:      "with non-ASCII strings different in a case",
+			valueOne:  "привет",
+			valueTwo:  "ПРИВЕТ",
+			wantEqual: true,
+		},
+		{
+			name:      "with the Kelvin sign",
+			valueOne:  "K",
+			valueTwo:  "k",
+			wantEqual: true,
+		},
+		{
+			name:      "with different strings",
+			valueOne:  "one",
+			valueTwo:  "two",
+			wantEqual: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			assert.Equal(
+				test,
+				data.wantEqual,
+				strings.EqualFold(data.valueOne, data.valueTwo),
+			)
+
+			hashOne := HashStringFold(data.valueOne)
+			hashTwo := HashStringFold(data.valueTwo)
+			assert.Equal(test, data.wantEqual, hashOne == hashTwo)
+		})
+	}
+}
+
+func TestHashUint64(test *testing.T) {
+	assert.Equal(test, IntKey(23).Hash64(), HashUint64(23))
+	assert.NotEqual(test, HashUint64(23), HashUint64(42))
+}
+
+func TestHashBool(test *testing.T) {
+	assert.Equal(test, HashUint64(1), HashBool(true))
+	assert.Equal(test, HashUint64(0), HashBool(false))
+}
+
+func TestHashFloat64(test *testing.T) {
+	assert.Equal(test, HashFloat64(0), HashFloat64(math.Copysign(0, -1)))
+	assert.NotEqual(test, HashFloat64(1.5), HashFloat64(2.5))
+}
+
+func TestHashComplex128(test *testing.T) {
+	assert.Equal(
+		test,
+		HashComplex128(complex(0, 1)),
+		HashComplex128(complex(math.Copysign(0, -1), 1)),
+	)
+	assert.NotEqual(test, HashComplex128(complex(1, 2)), HashComplex128(2+1i))
+}
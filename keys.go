@@ -0,0 +1,105 @@
+package hashmap
+
+import (
+	"encoding/binary"
+)
+
+// nolint: gochecknoglobals
+var (
+	// the process-wide key of the SipHash function; it's used when built-in keys
+	// are hashed without a seed of a map
+	builtinHashKey0 = newHashSeed()
+	builtinHashKey1 = newHashSeed()
+)
+
+// StringKey ...
+//
+// It uses the keyed SipHash function, so it's safe against hash flooding.
+//
+type StringKey string
+
+// Hash ...
+func (key StringKey) Hash() int {
+	return truncateHash(key.Hash64())
+}
+
+// Hash64 ...
+func (key StringKey) Hash64() uint64 {
+	return key.SeededHash(builtinHashKey0)
+}
+
+// SeededHash ...
+func (key StringKey) SeededHash(seed uint64) uint64 {
+	return sipHash(seed, builtinHashKey1, []byte(key))
+}
+
+// Equals ...
+func (key StringKey) Equals(other Key) bool {
+	return key == other.(StringKey)
+}
+
+// Less ...
+func (key StringKey) Less(other Key) bool {
+	return key < other.(StringKey)
+}
+
+// IntKey ...
+//
+// It uses the keyed SipHash function, so it's safe against hash flooding.
+//
+type IntKey int
+
+// Hash ...
+func (key IntKey) Hash() int {
+	return truncateHash(key.Hash64())
+}
+
+// Hash64 ...
+func (key IntKey) Hash64() uint64 {
+	return key.SeededHash(builtinHashKey0)
+}
+
+// SeededHash ...
+func (key IntKey) SeededHash(seed uint64) uint64 {
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], uint64(key))
+
+	return sipHash(seed, builtinHashKey1, data[:])
+}
+
+// Equals ...
+func (key IntKey) Equals(other Key) bool {
+	return key == other.(IntKey)
+}
+
+// Less ...
+func (key IntKey) Less(other Key) bool {
+	return key < other.(IntKey)
+}
+
+// it converts the hash to a non-negative integer of the platform size
+func truncateHash(hash uint64) int {
+	return int(uint(hash) >> 1)
+}
+
+// it calls the hasher if it's set or the Key.Hash64() method if it's
+// implemented or the Key.Hash() method otherwise
+func hashKey(hasher Hasher, key Key) uint64 {
+	if hasher != nil {
+		return hasher(key)
+	}
+	if hash64Key, ok := key.(Hash64Key); ok {
+		return hash64Key.Hash64()
+	}
+
+	return uint64(key.Hash())
+}
+
+// it calls the equaler if it's set or the Key.Equals() method otherwise
+func equalKeys(equaler Equaler, a Key, b Key) bool {
+	if equaler == nil {
+		return a.Equals(b)
+	}
+
+	return equaler(a, b)
+}
@@ -0,0 +1,90 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringKey(test *testing.T) {
+	key := StringKey("one")
+
+	assert.True(test, key.Hash() >= 0)
+	assert.Equal(test, key.Hash(), StringKey("one").Hash())
+	assert.Equal(test, truncateHash(key.Hash64()), key.Hash())
+	assert.Equal(test, key.SeededHash(23), StringKey("one").SeededHash(23))
+	assert.NotEqual(test, key.SeededHash(23), key.SeededHash(42))
+	assert.NotEqual(test, key.SeededHash(23), StringKey("two").SeededHash(23))
+	assert.True(test, key.Equals(StringKey("one")))
+	assert.False(test, key.Equals(StringKey("two")))
+}
+
+func TestIntKey(test *testing.T) {
+	key := IntKey(-23)
+
+	assert.True(test, key.Hash() >= 0)
+	assert.Equal(test, key.Hash(), IntKey(-23).Hash())
+	assert.Equal(test, truncateHash(key.Hash64()), key.Hash())
+	assert.Equal(test, key.SeededHash(23), IntKey(-23).SeededHash(23))
+	assert.NotEqual(test, key.SeededHash(23), key.SeededHash(42))
+	assert.NotEqual(test, key.SeededHash(23), IntKey(42).SeededHash(23))
+	assert.True(test, key.Equals(IntKey(-23)))
+	assert.False(test, key.Equals(IntKey(42)))
+}
+
+func Test_hashKey(test *testing.T) {
+	type args struct {
+		hasher Hasher
+		key    Key
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{
+			name: "with a key without a 64-bit hash",
+			args: args{
+				hasher: nil,
+				key:    collidingKey{id: 1, hash: -1},
+			},
+			want: 1<<64 - 1,
+		},
+		{
+			name: "with a key with a 64-bit hash",
+			args: args{
+				hasher: nil,
+				key:    hash64Key{collidingKey: collidingKey{id: 1, hash: 1}},
+			},
+			want: 1<<63 | 1,
+		},
+		{
+			name: "with a hasher",
+			args: args{
+				hasher: func(key Key) uint64 { return 23 },
+				key:    hash64Key{collidingKey: collidingKey{id: 1, hash: 1}},
+			},
+			want: 23,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := hashKey(data.args.hasher, data.args.key)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+// it's a key whose 64-bit hash differs from its own one in the highest bit
+type hash64Key struct {
+	collidingKey
+}
+
+func (key hash64Key) Hash64() uint64 {
+	return 1<<63 | uint64(key.hash)
+}
+
+func (key hash64Key) Equals(other Key) bool {
+	return key.id == other.(hash64Key).id
+}
@@ -0,0 +1,180 @@
+package hashmap
+
+import (
+	"container/list"
+)
+
+type linkedEntry struct {
+	key   Key
+	value interface{}
+}
+
+// LinkedHashMap ...
+//
+// It keeps a doubly linked list through its items, so its iteration order
+// is predictable. By default, it's insertion order: setting by an existing key
+// doesn't change the order.
+//
+// It's not safe for concurrent access. Note that in access order the getting
+// modifies the map, so the SynchronizedHashMap structure takes the exclusive
+// lock for getting from it.
+//
+type LinkedHashMap struct {
+	innerMap    Storage
+	order       *list.List
+	accessOrder bool
+}
+
+// NewLinkedHashMap ...
+func NewLinkedHashMap(options ...LinkedOption) *LinkedHashMap {
+	// you can't move the default linked config into a global variable
+	// because the default inner map should be new every time
+	config := LinkedConfig{innerMap: NewHashMap()}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &LinkedHashMap{
+		innerMap:    config.innerMap,
+		order:       list.New(),
+		accessOrder: config.accessOrder,
+	}
+}
+
+// Get ...
+func (hashMap *LinkedHashMap) Get(key Key) (value interface{}, ok bool) {
+	element, ok := hashMap.find(key)
+	if !ok {
+		return nil, false
+	}
+
+	if hashMap.accessOrder {
+		hashMap.order.MoveToBack(element)
+	}
+
+	return element.Value.(*linkedEntry).value, true
+}
+
+// it reports that the getting moves the item in access order
+func (hashMap *LinkedHashMap) mutatesOnGet() bool {
+	return hashMap.accessOrder
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It iterates from the front (the oldest item) to the back (the newest one).
+// The handler may delete the current item.
+//
+func (hashMap *LinkedHashMap) Iterate(handler Handler) bool {
+	for element := hashMap.order.Front(); element != nil; {
+		// get the next element in advance in case the current one is deleted
+		next := element.Next()
+
+		entry := element.Value.(*linkedEntry)
+		if ok := handler(entry.key, entry.value); !ok {
+			return false
+		}
+
+		element = next
+	}
+
+	return true
+}
+
+// Set ...
+func (hashMap *LinkedHashMap) Set(key Key, value interface{}) {
+	element, ok := hashMap.find(key)
+	if ok {
+		element.Value.(*linkedEntry).value = value
+		if hashMap.accessOrder {
+			hashMap.order.MoveToBack(element)
+		}
+
+		return
+	}
+
+	element = hashMap.order.PushBack(&linkedEntry{key, value})
+	hashMap.innerMap.Set(key, element)
+}
+
+// Delete ...
+func (hashMap *LinkedHashMap) Delete(key Key) {
+	element, ok := hashMap.find(key)
+	if !ok {
+		return
+	}
+
+	hashMap.order.Remove(element)
+	hashMap.innerMap.Delete(key)
+}
+
+// Size ...
+func (hashMap *LinkedHashMap) Size() int {
+	return hashMap.order.Len()
+}
+
+// Oldest ...
+//
+// It returns the item at the front of the order.
+//
+func (hashMap *LinkedHashMap) Oldest() (key Key, value interface{}, ok bool) {
+	return unpackLinkedElement(hashMap.order.Front())
+}
+
+// Newest ...
+//
+// It returns the item at the back of the order.
+//
+func (hashMap *LinkedHashMap) Newest() (key Key, value interface{}, ok bool) {
+	return unpackLinkedElement(hashMap.order.Back())
+}
+
+// MoveToFront ...
+//
+// It returns false if the key is absent.
+//
+func (hashMap *LinkedHashMap) MoveToFront(key Key) bool {
+	element, ok := hashMap.find(key)
+	if !ok {
+		return false
+	}
+
+	hashMap.order.MoveToFront(element)
+	return true
+}
+
+// MoveToBack ...
+//
+// It returns false if the key is absent.
+//
+func (hashMap *LinkedHashMap) MoveToBack(key Key) bool {
+	element, ok := hashMap.find(key)
+	if !ok {
+		return false
+	}
+
+	hashMap.order.MoveToBack(element)
+	return true
+}
+
+func (hashMap *LinkedHashMap) find(key Key) (element *list.Element, ok bool) {
+	value, ok := hashMap.innerMap.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*list.Element), true
+}
+
+func unpackLinkedElement(
+	element *list.Element,
+) (key Key, value interface{}, ok bool) {
+	if element == nil {
+		return nil, nil, false
+	}
+
+	entry := element.Value.(*linkedEntry)
+	return entry.key, entry.value, true
+}
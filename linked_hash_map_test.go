@@ -0,0 +1,243 @@
+package hashmap
+
+import (
+	"container/list"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewLinkedHashMap(test *testing.T) {
+	type args struct {
+		options []LinkedOption
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want *LinkedHashMap
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			want: &LinkedHashMap{
+				innerMap: &HashMap{
+					config:  defaultConfig,
+					buckets: make([]bucket, defaultConfig.initialCapacity),
+					size:    0,
+				},
+				order:       list.New(),
+				accessOrder: false,
+			},
+		},
+		{
+			name: "with the set config",
+			args: args{
+				options: []LinkedOption{
+					WithLinkedInnerMap(new(MockStorage)),
+					WithAccessOrder(true),
+				},
+			},
+			want: &LinkedHashMap{
+				innerMap:    new(MockStorage),
+				order:       list.New(),
+				accessOrder: true,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewLinkedHashMap(data.args.options...)
+			resetSeeds(test, got.innerMap)
+
+			_, ok := got.innerMap.(interface {
+				AssertExpectations(assert.TestingT) bool // nolint: staticcheck
+			})
+			if ok {
+				mock.AssertExpectationsForObjects(test, got.innerMap)
+			}
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestLinkedHashMap(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		options     []LinkedOption
+		action      func(hashMap *LinkedHashMap)
+		wantKeys    []Key
+		wantOldest  Key
+		wantNewest  Key
+		wantMissing []Key
+	}{
+		{
+			name:       "with insertion order",
+			options:    nil,
+			action:     func(hashMap *LinkedHashMap) {},
+			wantKeys:   []Key{IntKey(1), IntKey(2), IntKey(3), IntKey(4)},
+			wantOldest: IntKey(1),
+			wantNewest: IntKey(4),
+		},
+		{
+			name:    "with insertion order and getting and setting",
+			options: nil,
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.Get(IntKey(1))
+				hashMap.Set(IntKey(2), 2)
+			},
+			wantKeys:   []Key{IntKey(1), IntKey(2), IntKey(3), IntKey(4)},
+			wantOldest: IntKey(1),
+			wantNewest: IntKey(4),
+		},
+		{
+			name:    "with access order and getting and setting",
+			options: []LinkedOption{WithAccessOrder(true)},
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.Get(IntKey(1))
+				hashMap.Set(IntKey(2), 2)
+			},
+			wantKeys:   []Key{IntKey(3), IntKey(4), IntKey(1), IntKey(2)},
+			wantOldest: IntKey(3),
+			wantNewest: IntKey(2),
+		},
+		{
+			name:    "with moving",
+			options: nil,
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.MoveToFront(IntKey(3))
+				hashMap.MoveToBack(IntKey(1))
+			},
+			wantKeys:   []Key{IntKey(3), IntKey(2), IntKey(4), IntKey(1)},
+			wantOldest: IntKey(3),
+			wantNewest: IntKey(1),
+		},
+		{
+			name:    "with deleting",
+			options: nil,
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.Delete(IntKey(1))
+				hashMap.Delete(IntKey(3))
+				hashMap.Delete(IntKey(5))
+			},
+			wantKeys:    []Key{IntKey(2), IntKey(4)},
+			wantOldest:  IntKey(2),
+			wantNewest:  IntKey(4),
+			wantMissing: []Key{IntKey(1), IntKey(3)},
+		},
+		{
+			name:    "with deleting during iteration",
+			options: nil,
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.Iterate(func(key Key, value interface{}) bool {
+					if value.(int)%2 != 0 {
+						hashMap.Delete(key)
+					}
+
+					return true
+				})
+			},
+			wantKeys:    []Key{IntKey(2), IntKey(4)},
+			wantOldest:  IntKey(2),
+			wantNewest:  IntKey(4),
+			wantMissing: []Key{IntKey(1), IntKey(3)},
+		},
+		{
+			name:    "with reinsertion",
+			options: nil,
+			action: func(hashMap *LinkedHashMap) {
+				hashMap.Delete(IntKey(2))
+				hashMap.Set(IntKey(2), 2)
+			},
+			wantKeys:   []Key{IntKey(1), IntKey(3), IntKey(4), IntKey(2)},
+			wantOldest: IntKey(1),
+			wantNewest: IntKey(2),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewLinkedHashMap(data.options...)
+			for i := 1; i <= 4; i++ {
+				hashMap.Set(IntKey(i), i)
+			}
+
+			data.action(hashMap)
+
+			var gotKeys []Key
+			hashMap.Iterate(func(key Key, value interface{}) bool {
+				assert.Equal(test, int(key.(IntKey)), value)
+
+				gotKeys = append(gotKeys, key)
+				return true
+			})
+
+			gotOldest, _, gotOldestOk := hashMap.Oldest()
+			gotNewest, _, gotNewestOk := hashMap.Newest()
+
+			assert.Equal(test, data.wantKeys, gotKeys)
+			assert.Equal(test, len(data.wantKeys), hashMap.Size())
+			assert.Equal(test, data.wantOldest, gotOldest)
+			assert.True(test, gotOldestOk)
+			assert.Equal(test, data.wantNewest, gotNewest)
+			assert.True(test, gotNewestOk)
+			for _, key := range data.wantMissing {
+				_, ok := hashMap.Get(key)
+				assert.False(test, ok)
+			}
+		})
+	}
+}
+
+func TestLinkedHashMap_withoutItems(test *testing.T) {
+	hashMap := NewLinkedHashMap()
+
+	_, _, gotOldestOk := hashMap.Oldest()
+	_, _, gotNewestOk := hashMap.Newest()
+	gotMoveToFrontOk := hashMap.MoveToFront(IntKey(1))
+	gotMoveToBackOk := hashMap.MoveToBack(IntKey(1))
+
+	assert.False(test, gotOldestOk)
+	assert.False(test, gotNewestOk)
+	assert.False(test, gotMoveToFrontOk)
+	assert.False(test, gotMoveToBackOk)
+}
+
+func TestLinkedHashMap_Iterate_interruption(test *testing.T) {
+	hashMap := NewLinkedHashMap()
+	for i := 1; i <= 4; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	var gotKeys []Key
+	gotOk := hashMap.Iterate(func(key Key, value interface{}) bool {
+		gotKeys = append(gotKeys, key)
+		return len(gotKeys) < 2
+	})
+
+	assert.Equal(test, []Key{IntKey(1), IntKey(2)}, gotKeys)
+	assert.False(test, gotOk)
+}
+
+func TestLinkedHashMap_asInnerMap(test *testing.T) {
+	synchronizedHashMap := NewSynchronizedHashMap(
+		WithInnerMap(NewLinkedHashMap()),
+	)
+	concurrentHashMap := NewConcurrentHashMap(
+		WithConcurrencyLevel(1),
+		WithSegmentFactory(func() Storage { return NewLinkedHashMap() }),
+	)
+	for _, storage := range []Storage{synchronizedHashMap, concurrentHashMap} {
+		for i := 1; i <= 4; i++ {
+			storage.Set(IntKey(i), i)
+		}
+
+		var gotKeys []Key
+		storage.Iterate(func(key Key, value interface{}) bool {
+			gotKeys = append(gotKeys, key)
+			return true
+		})
+
+		assert.Equal(test, []Key{IntKey(1), IntKey(2), IntKey(3), IntKey(4)}, gotKeys)
+	}
+}
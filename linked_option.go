@@ -0,0 +1,35 @@
+package hashmap
+
+// LinkedConfig ...
+type LinkedConfig struct {
+	innerMap    Storage
+	accessOrder bool
+}
+
+// LinkedOption ...
+type LinkedOption func(options *LinkedConfig)
+
+// WithLinkedInnerMap ...
+//
+// The inner map is used for searching of entries by keys; it should be empty.
+//
+// Default: an instance of the HashMap structure with default options.
+//
+func WithLinkedInnerMap(innerMap Storage) LinkedOption {
+	return func(options *LinkedConfig) {
+		options.innerMap = innerMap
+	}
+}
+
+// WithAccessOrder ...
+//
+// If it's enabled, an item is moved to the back of the order on each getting
+// and setting (e.g. for implementing an LRU cache).
+//
+// Default: false (insertion order).
+//
+func WithAccessOrder(accessOrder bool) LinkedOption {
+	return func(options *LinkedConfig) {
+		options.accessOrder = accessOrder
+	}
+}
@@ -0,0 +1,150 @@
+package hashmap
+
+// MultiMap ...
+//
+// It stores several values by one key. Values are kept in an inner storage
+// as slices that are never modified in place, so slices returned
+// by the GetAll() method stay unchanged.
+//
+// It's safe for concurrent access only if it's created
+// by the NewSynchronizedMultiMap() or NewConcurrentMultiMap() functions.
+//
+type MultiMap struct {
+	storage Storage
+}
+
+// NewMultiMap ...
+//
+// It uses the HashMap structure as an inner storage.
+//
+func NewMultiMap(options ...Option) *MultiMap {
+	return &MultiMap{storage: NewHashMap(options...)}
+}
+
+// NewSynchronizedMultiMap ...
+//
+// It uses the SynchronizedHashMap structure as an inner storage.
+//
+func NewSynchronizedMultiMap(options ...SynchronizedOption) *MultiMap {
+	return &MultiMap{storage: NewSynchronizedHashMap(options...)}
+}
+
+// NewConcurrentMultiMap ...
+//
+// It uses the ConcurrentHashMap structure as an inner storage.
+//
+func NewConcurrentMultiMap(options ...ConcurrentOption) *MultiMap {
+	return &MultiMap{storage: NewConcurrentHashMap(options...)}
+}
+
+// Put ...
+//
+// It appends the value to values by the key.
+//
+func (multiMap *MultiMap) Put(key Key, value interface{}) {
+	updateStorage(multiMap.storage, key, func(
+		values interface{},
+		ok bool,
+	) (interface{}, bool) {
+		var oldValues []interface{}
+		if ok {
+			oldValues = values.([]interface{})
+		}
+
+		newValues := make([]interface{}, len(oldValues), len(oldValues)+1)
+		copy(newValues, oldValues)
+
+		return append(newValues, value), true
+	})
+}
+
+// GetAll ...
+//
+// It returns values by the key in order of their putting or nil if the key
+// is absent. The caller shouldn't modify the returned slice.
+//
+func (multiMap *MultiMap) GetAll(key Key) []interface{} {
+	values, ok := multiMap.storage.Get(key)
+	if !ok {
+		return nil
+	}
+
+	return values.([]interface{})
+}
+
+// RemoveValue ...
+//
+// It removes the first occurrence of the value by the key and returns true
+// if the value was found. Values are compared via the == operator, so they
+// should be comparable.
+//
+// If there are no more values by the key, the key is removed too.
+//
+func (multiMap *MultiMap) RemoveValue(key Key, value interface{}) bool {
+	var removed bool
+	updateStorage(multiMap.storage, key, func(
+		values interface{},
+		ok bool,
+	) (interface{}, bool) {
+		if !ok {
+			return nil, false
+		}
+
+		oldValues := values.([]interface{})
+		for index, oldValue := range oldValues {
+			if oldValue != value {
+				continue
+			}
+
+			removed = true
+			if len(oldValues) == 1 {
+				return nil, false
+			}
+
+			newValues := make([]interface{}, 0, len(oldValues)-1)
+			newValues = append(newValues, oldValues[:index]...)
+			return append(newValues, oldValues[index+1:]...), true
+		}
+
+		return oldValues, true
+	})
+
+	return removed
+}
+
+// RemoveAll ...
+//
+// It removes all values by the key.
+//
+func (multiMap *MultiMap) RemoveAll(key Key) {
+	multiMap.storage.Delete(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// The handler is called for each value separately. Iteration order of keys
+// is defined by the inner storage; values by one key are iterated in order
+// of their putting.
+//
+func (multiMap *MultiMap) Iterate(handler Handler) bool {
+	return multiMap.storage.Iterate(func(key Key, values interface{}) bool {
+		for _, value := range values.([]interface{}) {
+			if ok := handler(key, value); !ok {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// KeyCount ...
+//
+// If the inner storage doesn't implement the Sizer interface, keys are counted
+// via iteration.
+//
+func (multiMap *MultiMap) KeyCount() int {
+	return storageSize(multiMap.storage)
+}
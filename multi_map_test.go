@@ -0,0 +1,88 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiMap(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		makeMultiMap func() *MultiMap
+	}{
+		{
+			name:         "with the plain multimap",
+			makeMultiMap: func() *MultiMap { return NewMultiMap() },
+		},
+		{
+			name:         "with the synchronized multimap",
+			makeMultiMap: func() *MultiMap { return NewSynchronizedMultiMap() },
+		},
+		{
+			name:         "with the concurrent multimap",
+			makeMultiMap: func() *MultiMap { return NewConcurrentMultiMap() },
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			multiMap := data.makeMultiMap()
+			multiMap.Put(IntKey(1), "one")
+			multiMap.Put(IntKey(1), "two")
+			multiMap.Put(IntKey(1), "one")
+			multiMap.Put(IntKey(2), "three")
+			multiMap.Put(IntKey(3), "four")
+
+			gotValues := multiMap.GetAll(IntKey(1))
+			gotRemoved := multiMap.RemoveValue(IntKey(1), "one")
+			gotRemovedMissing := multiMap.RemoveValue(IntKey(1), "five")
+			gotRemovedLast := multiMap.RemoveValue(IntKey(2), "three")
+			gotRemovedAbsent := multiMap.RemoveValue(IntKey(4), "six")
+			multiMap.RemoveAll(IntKey(3))
+
+			var gotCount int
+			multiMap.Iterate(func(key Key, value interface{}) bool {
+				gotCount++
+				return true
+			})
+
+			// the slice got before the removing should stay unchanged
+			assert.Equal(test, []interface{}{"one", "two", "one"}, gotValues)
+			assert.Equal(
+				test,
+				[]interface{}{"two", "one"},
+				multiMap.GetAll(IntKey(1)),
+			)
+			assert.True(test, gotRemoved)
+			assert.False(test, gotRemovedMissing)
+			assert.True(test, gotRemovedLast)
+			assert.False(test, gotRemovedAbsent)
+			assert.Nil(test, multiMap.GetAll(IntKey(2)))
+			assert.Nil(test, multiMap.GetAll(IntKey(3)))
+			assert.Equal(test, 1, multiMap.KeyCount())
+			assert.Equal(test, 2, gotCount)
+		})
+	}
+}
+
+func TestMultiMap_concurrentPutting(test *testing.T) {
+	multiMap := NewConcurrentMultiMap()
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < 10; j++ {
+				multiMap.Put(IntKey(j), i)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	for j := 0; j < 10; j++ {
+		assert.Len(test, multiMap.GetAll(IntKey(j)), 10)
+	}
+}
@@ -0,0 +1,85 @@
+package hashmap
+
+// MutationAction ...
+type MutationAction int
+
+// ...
+const (
+	// KeepItem ...
+	KeepItem MutationAction = iota
+	// ReplaceItem ...
+	ReplaceItem
+	// DeleteItem ...
+	DeleteItem
+)
+
+// MutableHandler ...
+//
+// It returns an action that should be applied to the item; the new value
+// is used only by the ReplaceItem action.
+//
+type MutableHandler func(key Key, value interface{}) (
+	action MutationAction,
+	newValue interface{},
+)
+
+// it's implemented by storages that are able to modify their items
+// during iteration
+type mutableIterator interface {
+	IterateMutable(handler MutableHandler)
+}
+
+// if the storage doesn't implement the mutableIterator interface, actions
+// are collected via its Iterate() method and applied after iteration via
+// its Set() and Delete() methods, i.e. not atomically
+func iterateStorageMutable(storage Storage, handler MutableHandler) {
+	if iterator, ok := storage.(mutableIterator); ok {
+		iterator.IterateMutable(handler)
+		return
+	}
+
+	type mutation struct {
+		key      Key
+		action   MutationAction
+		newValue interface{}
+	}
+
+	var mutations []mutation
+	storage.Iterate(func(key Key, value interface{}) bool {
+		action, newValue := handler(key, value)
+		if action != KeepItem {
+			mutations = append(mutations, mutation{key, action, newValue})
+		}
+
+		return true
+	})
+
+	for _, mutation := range mutations {
+		switch mutation.action {
+		case ReplaceItem:
+			storage.Set(mutation.key, mutation.newValue)
+		case DeleteItem:
+			storage.Delete(mutation.key)
+		}
+	}
+}
+
+func deleteIfHandler(
+	predicate func(key Key, value interface{}) bool,
+) MutableHandler {
+	return func(key Key, value interface{}) (MutationAction, interface{}) {
+		if predicate(key, value) {
+			return DeleteItem, nil
+		}
+
+		return KeepItem, nil
+	}
+}
+
+func updateAllHandler(
+	update func(key Key, value interface{}) interface{},
+) MutableHandler {
+	return func(key Key, value interface{}) (MutationAction, interface{}) {
+		return ReplaceItem, update(key, value)
+	}
+}
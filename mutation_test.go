@@ -0,0 +1,104 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_iterateStorageMutable(test *testing.T) {
+	test.Run("with a mutable iterator", func(test *testing.T) {
+		hashMap := NewHashMap()
+		hashMap.Set(IntKey(1), 1)
+		hashMap.Set(IntKey(2), 2)
+
+		iterateStorageMutable(
+			hashMap,
+			func(key Key, value interface{}) (MutationAction, interface{}) {
+				if key == IntKey(1) {
+					return DeleteItem, nil
+				}
+
+				return ReplaceItem, value.(int) * 10
+			},
+		)
+
+		gotValue, gotOk := hashMap.Get(IntKey(2))
+		assert.Equal(test, 20, gotValue)
+		assert.True(test, gotOk)
+		assert.Equal(test, 1, hashMap.Size())
+	})
+
+	test.Run("without a mutable iterator", func(test *testing.T) {
+		storage := new(MockStorage)
+		storage.
+			On("Iterate", mock.AnythingOfType("hashmap.Handler")).
+			Return(true).
+			Run(func(args mock.Arguments) {
+				handler := args.Get(0).(Handler)
+				handler(IntKey(1), 1)
+				handler(IntKey(2), 2)
+				handler(IntKey(3), 3)
+			}).
+			Once()
+		storage.On("Set", IntKey(2), 20).Return().Once()
+		storage.On("Delete", IntKey(3)).Return().Once()
+
+		iterateStorageMutable(
+			storage,
+			func(key Key, value interface{}) (MutationAction, interface{}) {
+				switch key {
+				case IntKey(2):
+					return ReplaceItem, value.(int) * 10
+				case IntKey(3):
+					return DeleteItem, nil
+				default:
+					return KeepItem, nil
+				}
+			},
+		)
+
+		mock.AssertExpectationsForObjects(test, storage)
+	})
+}
+
+func Test_deleteIfHandler(test *testing.T) {
+	handler := deleteIfHandler(func(key Key, value interface{}) bool {
+		return value.(int) > 1
+	})
+
+	for _, data := range []struct {
+		name       string
+		value      interface{}
+		wantAction MutationAction
+	}{
+		{
+			name:       "with a true predicate result",
+			value:      2,
+			wantAction: DeleteItem,
+		},
+		{
+			name:       "with a false predicate result",
+			value:      1,
+			wantAction: KeepItem,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotAction, gotNewValue := handler(IntKey(23), data.value)
+
+			assert.Equal(test, data.wantAction, gotAction)
+			assert.Nil(test, gotNewValue)
+		})
+	}
+}
+
+func Test_updateAllHandler(test *testing.T) {
+	handler := updateAllHandler(func(key Key, value interface{}) interface{} {
+		return int(key.(IntKey)) + value.(int)
+	})
+	gotAction, gotNewValue := handler(IntKey(23), 42)
+
+	assert.Equal(test, ReplaceItem, gotAction)
+	assert.Equal(test, 65, gotNewValue)
+}
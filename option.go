@@ -1,10 +1,35 @@
 package hashmap
 
+import (
+	"errors"
+	"math"
+)
+
+// Hasher ...
+//
+// It's a hash function of keys that is used instead of the Key.Hash() method.
+// It should return equal hashes for keys that are equal according
+// to the equaler (see the Equaler type).
+//
+type Hasher func(key Key) uint64
+
+// Equaler ...
+//
+// It's a comparison function of keys that is used instead
+// of the Key.Equals() method.
+//
+type Equaler func(a Key, b Key) bool
+
 // Config ...
 type Config struct {
 	initialCapacity int
 	maxLoadFactor   float64
 	growFactor      float64
+	instrumentation Instrumentation
+	hashSeed        uint64
+	reseedThreshold int
+	hasher          Hasher
+	equaler         Equaler
 }
 
 // nolint: gochecknoglobals
@@ -16,11 +41,62 @@ var (
 	}
 )
 
+// nolint: gochecknoglobals
+var (
+	// ErrInvalidInitialCapacity ...
+	ErrInvalidInitialCapacity = errors.New("initial capacity isn't positive")
+	// ErrInvalidMaxLoadFactor ...
+	ErrInvalidMaxLoadFactor = errors.New(
+		"maximal load factor isn't in the (0, 1) range",
+	)
+	// ErrInvalidGrowFactor ...
+	ErrInvalidGrowFactor = errors.New("grow factor isn't greater than 1")
+)
+
+// it's the closest valid value to 1; a table with the load factor equal to 1
+// is full, so searching by a nonexistent key never ends
+const maxValidLoadFactor = 0.95
+
+// Validate ...
+//
+// It returns an error if the config breaks the HashMap structure: a zero
+// initial capacity causes division by zero, a maximal load factor of 1
+// or higher allows filling of the table, and a grow factor of 1 or lower
+// doesn't grow the table.
+//
+// The returned error details one of the ErrInvalid* errors, which is returned
+// by its Cause() and Unwrap() methods.
+//
+func (config Config) Validate() error {
+	if config.initialCapacity <= 0 {
+		return newDetailedError(
+			ErrInvalidInitialCapacity,
+			"%d",
+			config.initialCapacity,
+		)
+	}
+	if !(config.maxLoadFactor > 0 && config.maxLoadFactor < 1) {
+		return newDetailedError(
+			ErrInvalidMaxLoadFactor,
+			"%g",
+			config.maxLoadFactor,
+		)
+	}
+	if !(config.growFactor > 1) || math.IsInf(config.growFactor, 1) {
+		return newDetailedError(ErrInvalidGrowFactor, "%g", config.growFactor)
+	}
+
+	return nil
+}
+
 // Option ...
 type Option func(options *Config)
 
 // WithInitialCapacity ...
 //
+// It should be positive, a non-positive one is replaced by 1
+// (see also the NewHashMapE() function).
+//
 // Default: 16.
 //
 func WithInitialCapacity(initialCapacity int) Option {
@@ -31,6 +107,10 @@ func WithInitialCapacity(initialCapacity int) Option {
 
 // WithMaxLoadFactor ...
 //
+// It should be in the (0, 1) range; a factor of 1 or higher is replaced
+// by 0.95, a non-positive one is replaced by the default one
+// (see also the NewHashMapE() function).
+//
 // Default: 0.75.
 //
 func WithMaxLoadFactor(maxLoadFactor float64) Option {
@@ -41,6 +121,9 @@ func WithMaxLoadFactor(maxLoadFactor float64) Option {
 
 // WithGrowFactor ...
 //
+// It should be greater than 1, another one is replaced by the default one
+// (see also the NewHashMapE() function).
+//
 // Default: 2.
 //
 func WithGrowFactor(growFactor float64) Option {
@@ -48,3 +131,98 @@ func WithGrowFactor(growFactor float64) Option {
 		options.growFactor = growFactor
 	}
 }
+
+// WithInstrumentation ...
+//
+// Default: nil (events aren't reported).
+//
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(options *Config) {
+		options.instrumentation = instrumentation
+	}
+}
+
+// WithHashSeed ...
+//
+// The seed is mixed into hashes of keys (see also the SeededKey interface).
+// A zero seed means a random one.
+//
+// Default: a random seed that is unique for each map.
+//
+func WithHashSeed(hashSeed uint64) Option {
+	return func(options *Config) {
+		options.hashSeed = hashSeed
+	}
+}
+
+// WithReseedThreshold ...
+//
+// If a probe length of the setting by a new key exceeds the threshold,
+// the map gets a new random seed and rehashes its items. It's a protection
+// from hash flooding. The map is reseeded at most once between its growths.
+//
+// Default: 0 (reseeding is disabled).
+//
+func WithReseedThreshold(maxProbeLength int) Option {
+	return func(options *Config) {
+		options.reseedThreshold = maxProbeLength
+	}
+}
+
+// WithHasher ...
+//
+// It allows to hash keys differently from their Key.Hash() method, e.g.
+// case-insensitively. It should be used together with the consistent equaler
+// (see the WithEqualer() function). The seed is mixed into its results.
+//
+// Default: nil (the Key.Hash() method is used).
+//
+func WithHasher(hasher Hasher) Option {
+	return func(options *Config) {
+		options.hasher = hasher
+	}
+}
+
+// WithEqualer ...
+//
+// It allows to compare keys differently from their Key.Equals() method,
+// e.g. case-insensitively. It should be used together with the consistent
+// hasher (see the WithHasher() function).
+//
+// Default: nil (the Key.Equals() method is used).
+//
+func WithEqualer(equaler Equaler) Option {
+	return func(options *Config) {
+		options.equaler = equaler
+	}
+}
+
+func newConfig(options []Option) Config {
+	config := defaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+// it replaces invalid values (see the Config.Validate() method) by the closest
+// valid ones or by default ones if there are no such values
+func (config Config) normalize() Config {
+	if config.initialCapacity <= 0 {
+		config.initialCapacity = 1
+	}
+
+	switch {
+	case config.maxLoadFactor >= 1:
+		config.maxLoadFactor = maxValidLoadFactor
+	case !(config.maxLoadFactor > 0):
+		config.maxLoadFactor = defaultConfig.maxLoadFactor
+	}
+
+	if !(config.growFactor > 1) || math.IsInf(config.growFactor, 1) {
+		config.growFactor = defaultConfig.growFactor
+	}
+
+	return config
+}
@@ -0,0 +1,169 @@
+package hashmap
+
+// PersistentHashMap ...
+//
+// It's an immutable hash map based on a hash array mapped trie. Its
+// modification methods return new versions of the map that share unchanged
+// nodes with the original one, so keeping old versions is cheap.
+//
+// It's safe for concurrent access, because it's never modified. The zero
+// value is an empty map.
+//
+type PersistentHashMap struct {
+	root *hamtNode
+	size int
+}
+
+// NewPersistentHashMap ...
+func NewPersistentHashMap() PersistentHashMap {
+	return PersistentHashMap{}
+}
+
+// Get ...
+func (hashMap PersistentHashMap) Get(key Key) (value interface{}, ok bool) {
+	if hashMap.root == nil {
+		return nil, false
+	}
+
+	return hashMap.root.get(key, hamtHash(key), 0)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It doesn't randomize of iteration order, but the order depends on hashes
+// of keys.
+//
+func (hashMap PersistentHashMap) Iterate(handler Handler) bool {
+	if hashMap.root == nil {
+		return true
+	}
+
+	return hashMap.root.iterate(handler)
+}
+
+// Set ...
+//
+// It returns a new version of the map, the original one isn't changed.
+//
+func (hashMap PersistentHashMap) Set(
+	key Key,
+	value interface{},
+) PersistentHashMap {
+	return hashMap.set(nil, key, value)
+}
+
+// Delete ...
+//
+// It returns a new version of the map, the original one isn't changed.
+//
+func (hashMap PersistentHashMap) Delete(key Key) PersistentHashMap {
+	return hashMap.delete(nil, key)
+}
+
+// Size ...
+func (hashMap PersistentHashMap) Size() int {
+	return hashMap.size
+}
+
+// Transient ...
+//
+// It returns a builder that starts from the map. The map itself
+// isn't changed.
+//
+func (hashMap PersistentHashMap) Transient() *PersistentHashMapBuilder {
+	return &PersistentHashMapBuilder{hashMap: hashMap, owner: new(hamtOwner)}
+}
+
+func (hashMap PersistentHashMap) set(
+	owner *hamtOwner,
+	key Key,
+	value interface{},
+) PersistentHashMap {
+	root := hashMap.root
+	if root == nil {
+		root = &hamtNode{owner: owner}
+	}
+
+	newRoot, added := root.set(owner, key, value, hamtHash(key), 0)
+	newHashMap := PersistentHashMap{root: newRoot, size: hashMap.size}
+	if added {
+		newHashMap.size++
+	}
+
+	return newHashMap
+}
+
+func (hashMap PersistentHashMap) delete(
+	owner *hamtOwner,
+	key Key,
+) PersistentHashMap {
+	if hashMap.root == nil {
+		return hashMap
+	}
+
+	newRoot, deleted := hashMap.root.delete(owner, key, hamtHash(key), 0)
+	if !deleted {
+		return hashMap
+	}
+
+	return PersistentHashMap{root: newRoot, size: hashMap.size - 1}
+}
+
+// PersistentHashMapBuilder ...
+//
+// It's a transient version of the PersistentHashMap structure for bulk
+// construction. It modifies nodes created by itself in place instead
+// of copying them.
+//
+// It's not safe for concurrent access.
+//
+type PersistentHashMapBuilder struct {
+	hashMap PersistentHashMap
+	owner   *hamtOwner
+}
+
+// Get ...
+func (builder *PersistentHashMapBuilder) Get(key Key) (
+	value interface{},
+	ok bool,
+) {
+	return builder.hashMap.Get(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// The builder shouldn't be modified during iteration.
+//
+func (builder *PersistentHashMapBuilder) Iterate(handler Handler) bool {
+	return builder.hashMap.Iterate(handler)
+}
+
+// Set ...
+func (builder *PersistentHashMapBuilder) Set(key Key, value interface{}) {
+	builder.hashMap = builder.hashMap.set(builder.owner, key, value)
+}
+
+// Delete ...
+func (builder *PersistentHashMapBuilder) Delete(key Key) {
+	builder.hashMap = builder.hashMap.delete(builder.owner, key)
+}
+
+// Size ...
+func (builder *PersistentHashMapBuilder) Size() int {
+	return builder.hashMap.Size()
+}
+
+// Persistent ...
+//
+// It returns a persistent version of the built map. The builder can be used
+// further, but its next modifications won't affect the returned map.
+//
+func (builder *PersistentHashMapBuilder) Persistent() PersistentHashMap {
+	// nodes of the returned map shouldn't be modified in place anymore
+	builder.owner = new(hamtOwner)
+	return builder.hashMap
+}
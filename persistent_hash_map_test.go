@@ -0,0 +1,289 @@
+package hashmap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentHashMap(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		keys []Key
+	}{
+		{
+			name: "with distinct hashes",
+			keys: []Key{
+				collidingKey{id: 1, hash: 1},
+				collidingKey{id: 2, hash: 2},
+				collidingKey{id: 3, hash: 33},
+			},
+		},
+		{
+			name: "with hashes equal on several levels",
+			keys: []Key{
+				collidingKey{id: 1, hash: 1},
+				collidingKey{id: 2, hash: 1 | 1<<20},
+				collidingKey{id: 3, hash: 1 | 2<<20},
+			},
+		},
+		{
+			name: "with equal hashes",
+			keys: []Key{
+				collidingKey{id: 1, hash: 23},
+				collidingKey{id: 2, hash: 23},
+				collidingKey{id: 3, hash: 23},
+			},
+		},
+		{
+			name: "with negative hashes",
+			keys: []Key{
+				collidingKey{id: 1, hash: -1},
+				collidingKey{id: 2, hash: -1},
+				collidingKey{id: 3, hash: -2},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewPersistentHashMap()
+			for index, key := range data.keys {
+				hashMap = hashMap.Set(key, index)
+			}
+			hashMap = hashMap.Set(data.keys[0], "updated")
+
+			assert.Equal(test, len(data.keys), hashMap.Size())
+			value, ok := hashMap.Get(data.keys[0])
+			assert.Equal(test, "updated", value)
+			assert.True(test, ok)
+			for index, key := range data.keys[1:] {
+				value, ok := hashMap.Get(key)
+				assert.Equal(test, index+1, value)
+				assert.True(test, ok)
+			}
+
+			_, ok = hashMap.Get(collidingKey{id: 100, hash: 23})
+			assert.False(test, ok)
+
+			for index, key := range data.keys {
+				hashMap = hashMap.Delete(key)
+				hashMap = hashMap.Delete(key)
+
+				assert.Equal(test, len(data.keys)-index-1, hashMap.Size())
+				_, ok := hashMap.Get(key)
+				assert.False(test, ok)
+				for _, otherKey := range data.keys[index+1:] {
+					_, ok := hashMap.Get(otherKey)
+					assert.True(test, ok)
+				}
+			}
+			assert.Nil(test, hashMap.root)
+		})
+	}
+}
+
+func TestPersistentHashMap_versions(test *testing.T) {
+	var versions []PersistentHashMap
+	hashMap := NewPersistentHashMap()
+	for i := 0; i < 100; i++ {
+		versions = append(versions, hashMap)
+		hashMap = hashMap.Set(IntKey(i), i)
+	}
+	for i := 0; i < 100; i += 2 {
+		hashMap = hashMap.Delete(IntKey(i))
+	}
+
+	for size, version := range versions {
+		assert.Equal(test, size, version.Size())
+		for i := 0; i < 100; i++ {
+			_, ok := version.Get(IntKey(i))
+			assert.Equal(test, i < size, ok)
+		}
+	}
+
+	assert.Equal(test, 50, hashMap.Size())
+	for i := 0; i < 100; i++ {
+		_, ok := hashMap.Get(IntKey(i))
+		assert.Equal(test, i%2 == 1, ok)
+	}
+}
+
+func TestPersistentHashMap_sharing(test *testing.T) {
+	hashMap := NewPersistentHashMap()
+	for i := 0; i < 1000; i++ {
+		hashMap = hashMap.Set(IntKey(i), i)
+	}
+	newHashMap := hashMap.Set(IntKey(0), "updated")
+
+	var sharedCount int
+	for index, entry := range newHashMap.root.entries {
+		if entry.child != nil && entry.child == hashMap.root.entries[index].child {
+			sharedCount++
+		}
+	}
+
+	assert.NotEqual(test, hashMap.root, newHashMap.root)
+	assert.Equal(test, len(hashMap.root.entries)-1, sharedCount)
+}
+
+func TestPersistentHashMap_highBitsHashes(test *testing.T) {
+	hashMap := NewPersistentHashMap()
+	for i := 0; i < 32; i++ {
+		hashMap = hashMap.Set(highBitsKey(i), i)
+	}
+
+	for i := 0; i < 32; i++ {
+		gotValue, gotOk := hashMap.Get(highBitsKey(i))
+
+		assert.Equal(test, i, gotValue)
+		assert.True(test, gotOk)
+	}
+	// without mixing, all the keys would share the whole hash path
+	// and would be stored in a single collision node
+	assert.True(test, hamtDepth(hashMap.root) < 5)
+}
+
+func TestPersistentHashMap_Iterate(test *testing.T) {
+	test.Run("with an empty map", func(test *testing.T) {
+		var count int
+		ok := NewPersistentHashMap().Iterate(func(key Key, value interface{}) bool {
+			count++
+			return true
+		})
+
+		assert.True(test, ok)
+		assert.Equal(test, 0, count)
+	})
+
+	test.Run("with full iteration", func(test *testing.T) {
+		hashMap := NewPersistentHashMap()
+		for i := 0; i < 100; i++ {
+			hashMap = hashMap.Set(IntKey(i), i*i)
+		}
+
+		items := make(map[Key]interface{})
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			items[key] = value
+			return true
+		})
+
+		assert.True(test, ok)
+		assert.Len(test, items, 100)
+		for i := 0; i < 100; i++ {
+			assert.Equal(test, i*i, items[IntKey(i)])
+		}
+	})
+
+	test.Run("with interrupted iteration", func(test *testing.T) {
+		hashMap := NewPersistentHashMap()
+		for i := 0; i < 100; i++ {
+			hashMap = hashMap.Set(IntKey(i), i*i)
+		}
+
+		var count int
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			count++
+			return count < 23
+		})
+
+		assert.False(test, ok)
+		assert.Equal(test, 23, count)
+	})
+}
+
+func TestPersistentHashMap_random(test *testing.T) {
+	hashMap := NewPersistentHashMap()
+	expectedItems := make(map[Key]interface{})
+	for i := 0; i < 10000; i++ {
+		// few distinct hashes make collisions on all levels
+		id := rand.Intn(1000)
+		key := collidingKey{id: id, hash: id % 37}
+		if rand.Intn(3) == 0 {
+			hashMap = hashMap.Delete(key)
+			delete(expectedItems, key)
+
+			continue
+		}
+
+		hashMap = hashMap.Set(key, i)
+		expectedItems[key] = i
+	}
+
+	items := make(map[Key]interface{})
+	hashMap.Iterate(func(key Key, value interface{}) bool {
+		items[key] = value
+		return true
+	})
+
+	assert.Equal(test, len(expectedItems), hashMap.Size())
+	assert.Equal(test, expectedItems, items)
+}
+
+func TestPersistentHashMapBuilder(test *testing.T) {
+	original := NewPersistentHashMap().Set(IntKey(0), 0)
+
+	builder := original.Transient()
+	for i := 1; i < 100; i++ {
+		builder.Set(IntKey(i), i)
+	}
+	builder.Delete(IntKey(0))
+	builder.Delete(IntKey(1))
+	built := builder.Persistent()
+
+	builder.Set(IntKey(1), "updated")
+	builder.Delete(IntKey(2))
+
+	assert.Equal(test, 1, original.Size())
+	_, ok := original.Get(IntKey(1))
+	assert.False(test, ok)
+
+	assert.Equal(test, 98, built.Size())
+	_, ok = built.Get(IntKey(1))
+	assert.False(test, ok)
+	value, _ := built.Get(IntKey(2))
+	assert.Equal(test, 2, value)
+
+	assert.Equal(test, 98, builder.Size())
+	value, _ = builder.Get(IntKey(1))
+	assert.Equal(test, "updated", value)
+	_, ok = builder.Get(IntKey(2))
+	assert.False(test, ok)
+
+	var count int
+	builder.Iterate(func(key Key, value interface{}) bool {
+		count++
+		return true
+	})
+	assert.Equal(test, 98, count)
+}
+
+// it's a key whose 64-bit hashes differ only in high bits, and whose own
+// hashes are equal
+type highBitsKey int
+
+func (key highBitsKey) Hash() int {
+	return 0
+}
+
+func (key highBitsKey) Hash64() uint64 {
+	return uint64(key) << 48
+}
+
+func (key highBitsKey) Equals(other Key) bool {
+	return key == other.(highBitsKey)
+}
+
+func hamtDepth(node *hamtNode) int {
+	var maxChildDepth int
+	for _, entry := range node.entries {
+		if entry.child == nil {
+			continue
+		}
+
+		if childDepth := hamtDepth(entry.child); childDepth > maxChildDepth {
+			maxChildDepth = childDepth
+		}
+	}
+
+	return maxChildDepth + 1
+}
@@ -0,0 +1,178 @@
+// +build go1.13
+
+// Package promcollector implements an adapter of the hashmap.Instrumentation
+// interface to the Prometheus client collector interface.
+package promcollector
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector ...
+//
+// It implements the hashmap.Instrumentation interface and the Prometheus
+// client collector interface. It's safe for concurrent access.
+//
+type Collector struct {
+	gets             *prometheus.CounterVec
+	sets             prometheus.Counter
+	deletes          prometheus.Counter
+	probeLength      prometheus.Histogram
+	rehashes         prometheus.Counter
+	rehashInProgress prometheus.Gauge
+	rehashDuration   prometheus.Histogram
+	lockWait         prometheus.Histogram
+	segmentSize      *prometheus.GaugeVec
+}
+
+// NewCollector ...
+func NewCollector(namespace string, subsystem string) Collector {
+	return Collector{
+		gets: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "gets_total",
+				Help:      "Count of the getting by a result (a hit or a miss).",
+			},
+			[]string{"result"},
+		),
+		sets: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "sets_total",
+			Help:      "Count of the setting.",
+		}),
+		deletes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "deletes_total",
+			Help:      "Count of the deleting.",
+		}),
+		probeLength: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "probe_length",
+			Help:      "Count of buckets checked during a key search.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+		}),
+		rehashes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rehashes_total",
+			Help:      "Count of the rehashing.",
+		}),
+		rehashInProgress: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rehashes_in_progress",
+			Help:      "Count of the rehashing in progress.",
+		}),
+		rehashDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "rehash_duration_seconds",
+			Help:      "Duration of the rehashing.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		lockWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "lock_wait_seconds",
+			Help:      "Time spent on a lock acquisition.",
+			Buckets:   prometheus.ExponentialBuckets(1e-7, 10, 8),
+		}),
+		segmentSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "segment_size",
+				Help:      "Count of items in a segment.",
+			},
+			[]string{"segment"},
+		),
+	}
+}
+
+// OnGetHit ...
+func (collector Collector) OnGetHit(probeLength int) {
+	collector.gets.WithLabelValues("hit").Inc()
+	collector.probeLength.Observe(float64(probeLength))
+}
+
+// OnGetMiss ...
+func (collector Collector) OnGetMiss(probeLength int) {
+	collector.gets.WithLabelValues("miss").Inc()
+	collector.probeLength.Observe(float64(probeLength))
+}
+
+// OnSet ...
+func (collector Collector) OnSet(probeLength int) {
+	collector.sets.Inc()
+	collector.probeLength.Observe(float64(probeLength))
+}
+
+// OnDelete ...
+func (collector Collector) OnDelete(probeLength int) {
+	collector.deletes.Inc()
+	collector.probeLength.Observe(float64(probeLength))
+}
+
+// OnRehashStart ...
+func (collector Collector) OnRehashStart(size int, capacity int) {
+	collector.rehashInProgress.Inc()
+}
+
+// OnRehashEnd ...
+func (collector Collector) OnRehashEnd(
+	size int,
+	capacity int,
+	duration time.Duration,
+) {
+	collector.rehashInProgress.Dec()
+	collector.rehashes.Inc()
+	collector.rehashDuration.Observe(duration.Seconds())
+}
+
+// OnLockContention ...
+func (collector Collector) OnLockContention(wait time.Duration) {
+	collector.lockWait.Observe(wait.Seconds())
+}
+
+// OnSegmentLoad ...
+func (collector Collector) OnSegmentLoad(segment int, size int) {
+	collector.segmentSize.
+		WithLabelValues(strconv.Itoa(segment)).
+		Set(float64(size))
+}
+
+// Describe ...
+func (collector Collector) Describe(descriptions chan<- *prometheus.Desc) {
+	for _, metric := range collector.metrics() {
+		metric.Describe(descriptions)
+	}
+}
+
+// Collect ...
+func (collector Collector) Collect(metrics chan<- prometheus.Metric) {
+	for _, metric := range collector.metrics() {
+		metric.Collect(metrics)
+	}
+}
+
+func (collector Collector) metrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		collector.gets,
+		collector.sets,
+		collector.deletes,
+		collector.probeLength,
+		collector.rehashes,
+		collector.rehashInProgress,
+		collector.rehashDuration,
+		collector.lockWait,
+		collector.segmentSize,
+	}
+}
@@ -0,0 +1,53 @@
+// +build go1.13
+
+package promcollector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCollector(test *testing.T) {
+	var instrumentation hashmap.Instrumentation = NewCollector("test", "map")
+	instrumentation.OnGetHit(1)
+	instrumentation.OnGetHit(2)
+	instrumentation.OnGetMiss(3)
+	instrumentation.OnSet(1)
+	instrumentation.OnDelete(1)
+	instrumentation.OnRehashStart(12, 16)
+	instrumentation.OnRehashEnd(12, 32, time.Millisecond)
+	instrumentation.OnLockContention(time.Microsecond)
+	instrumentation.OnSegmentLoad(5, 23)
+
+	collector := instrumentation.(Collector)
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(test, registry.Register(collector))
+
+	assert.Equal(
+		test,
+		2.0,
+		testutil.ToFloat64(collector.gets.WithLabelValues("hit")),
+	)
+	assert.Equal(
+		test,
+		1.0,
+		testutil.ToFloat64(collector.gets.WithLabelValues("miss")),
+	)
+	assert.Equal(test, 1.0, testutil.ToFloat64(collector.sets))
+	assert.Equal(test, 1.0, testutil.ToFloat64(collector.deletes))
+	assert.Equal(test, 1.0, testutil.ToFloat64(collector.rehashes))
+	assert.Equal(test, 0.0, testutil.ToFloat64(collector.rehashInProgress))
+	assert.Equal(
+		test,
+		23.0,
+		testutil.ToFloat64(collector.segmentSize.WithLabelValues("5")),
+	)
+	// the count of all metrics, including ones of each vector label
+	assert.Equal(test, 10, testutil.CollectAndCount(collector))
+}
@@ -0,0 +1,519 @@
+package hashmap
+
+import (
+	"math/bits"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type reshardableSegment struct {
+	// it's held in the read mode by operations over the storage
+	// and in the write mode by resharding
+	lock    sync.RWMutex
+	storage Storage
+	// the segment contains keys which low bits of directory hashes equal
+	// to the prefix (see the reshardableHash() function); a count of these bits
+	// is the depth
+	prefix uint
+	depth  uint
+	// a retired segment is replaced in the directory by its successors
+	// and its keys are moved to them
+	retired    bool
+	successors []*reshardableSegment
+}
+
+type reshardableItem struct {
+	key   Key
+	value interface{}
+}
+
+// it selects keys which low bits of directory hashes equal to the prefix
+type hashFilter struct {
+	prefix uint
+	depth  uint
+}
+
+func (filter hashFilter) matches(hash uint64) bool {
+	return uint(hash)&(1<<filter.depth-1) == filter.prefix
+}
+
+// it returns the narrower one of the filter and of the segment prefix;
+// they always overlap, because successors of a segment cover its prefix
+func (filter hashFilter) narrow(segment *reshardableSegment) hashFilter {
+	if segment.depth > filter.depth {
+		return hashFilter{prefix: segment.prefix, depth: segment.depth}
+	}
+
+	return filter
+}
+
+// ReshardableHashMap ...
+//
+// It's the same as the ConcurrentHashMap structure, but it's able to split
+// and merge its segments online, while reads and writes continue. It uses
+// extendible hashing: a segment is selected by high bits of a mixed key hash
+// via a directory, and only keys of a split or merged segment are moved.
+//
+// Resharding can be triggered manually (see the Split() and Merge() methods)
+// or automatically by thresholds of a segment size and of contention
+// (see the WithSplitThreshold(), WithMergeThreshold()
+// and WithContentionThreshold() options).
+//
+type ReshardableHashMap struct {
+	config ConcurrentConfig
+	// it serializes resharding operations
+	reshardingLock sync.Mutex
+	directoryLock  sync.RWMutex
+	// its length is always 2 to the power of the global depth
+	directory   []*reshardableSegment
+	globalDepth uint
+	maxDepth    uint
+}
+
+// NewReshardableHashMap ...
+//
+// The concurrency level is rounded up to a power of two.
+//
+func NewReshardableHashMap(options ...ConcurrentOption) *ReshardableHashMap {
+	config := newConcurrentConfig(options)
+
+	globalDepth := log2(ceilPowerOfTwo(config.concurrencyLevel))
+	var directory []*reshardableSegment
+	for prefix := uint(0); prefix < 1<<globalDepth; prefix++ {
+		directory = append(directory, &reshardableSegment{
+			storage: config.segmentFactory(),
+			prefix:  prefix,
+			depth:   globalDepth,
+		})
+	}
+
+	return &ReshardableHashMap{
+		config:      config,
+		directory:   directory,
+		globalDepth: globalDepth,
+		maxDepth:    log2(ceilPowerOfTwo(config.maxConcurrencyLevel)),
+	}
+}
+
+// Get ...
+func (hashMap *ReshardableHashMap) Get(key Key) (value interface{}, ok bool) {
+	hashMap.withSegment(key, func(storage Storage) {
+		value, ok = storage.Get(key)
+	})
+
+	return value, ok
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It randomizes of iteration order over segments.
+//
+// Items of each segment are copied under its lock, and the handler is called
+// out of lock. So iteration isn't blocked by resharding: if a segment
+// is resharded before its turn, its successors are iterated instead.
+//
+func (hashMap *ReshardableHashMap) Iterate(handler Handler) bool {
+	hashMap.directoryLock.RLock()
+	segments := uniqueSegments(hashMap.directory)
+	hashMap.directoryLock.RUnlock()
+
+	for _, index := range rand.Perm(len(segments)) {
+		segment := segments[index]
+		filter := hashFilter{prefix: segment.prefix, depth: segment.depth}
+		hasher := hashMap.config.hasher
+		if ok := iterateSegment(segment, filter, hasher, handler); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Set ...
+func (hashMap *ReshardableHashMap) Set(key Key, value interface{}) {
+	segment, contended := hashMap.withSegment(key, func(storage Storage) {
+		storage.Set(key, value)
+	})
+
+	splitThreshold := hashMap.config.splitThreshold
+	if contended ||
+		splitThreshold != 0 && storageSize(segment.storage) > splitThreshold {
+		hashMap.split(segment)
+	}
+}
+
+// Delete ...
+func (hashMap *ReshardableHashMap) Delete(key Key) {
+	segment, contended := hashMap.withSegment(key, func(storage Storage) {
+		storage.Delete(key)
+	})
+
+	if contended {
+		hashMap.split(segment)
+	} else if hashMap.config.mergeThreshold != 0 {
+		hashMap.merge(segment, hashMap.config.mergeThreshold)
+	}
+}
+
+// Size ...
+//
+// If a segment doesn't implement the Sizer interface, its items are counted
+// via iteration.
+//
+func (hashMap *ReshardableHashMap) Size() int {
+	hashMap.directoryLock.RLock()
+	segments := uniqueSegments(hashMap.directory)
+	hashMap.directoryLock.RUnlock()
+
+	var size int
+	for _, segment := range segments {
+		filter := hashFilter{prefix: segment.prefix, depth: segment.depth}
+		size += segmentSize(segment, filter, hashMap.config.hasher)
+	}
+
+	return size
+}
+
+// SegmentCount ...
+func (hashMap *ReshardableHashMap) SegmentCount() int {
+	hashMap.directoryLock.RLock()
+	defer hashMap.directoryLock.RUnlock()
+
+	return len(uniqueSegments(hashMap.directory))
+}
+
+// Split ...
+//
+// It splits the segment that contains the key into two ones. It returns
+// false if the segment can't be split because of the maximal concurrency
+// level.
+//
+func (hashMap *ReshardableHashMap) Split(key Key) bool {
+	return hashMap.split(hashMap.selectSegment(key))
+}
+
+// Merge ...
+//
+// It merges the segment that contains the key with its buddy (a segment
+// that differs only in the highest bit of a prefix). It returns false
+// if the segment has no buddy (e.g. the buddy is split further).
+//
+func (hashMap *ReshardableHashMap) Merge(key Key) bool {
+	return hashMap.merge(hashMap.selectSegment(key), 0)
+}
+
+func (hashMap *ReshardableHashMap) selectSegment(key Key) *reshardableSegment {
+	hashMap.directoryLock.RLock()
+	defer hashMap.directoryLock.RUnlock()
+
+	index := uint(hashMap.hash(key)) & (1<<hashMap.globalDepth - 1)
+	return hashMap.directory[index]
+}
+
+// it returns the directory hash of the key (see the reshardableHash()
+// function); it uses the hasher if it's set (see the WithConcurrentHasher()
+// function)
+func (hashMap *ReshardableHashMap) hash(key Key) uint64 {
+	return reshardableHash(hashMap.config.hasher, key)
+}
+
+// it returns true as the second result if the action took longer
+// than the contention threshold
+func (hashMap *ReshardableHashMap) withSegment(
+	key Key,
+	action func(storage Storage),
+) (segment *reshardableSegment, contended bool) {
+	for {
+		// the directory lock isn't held during waiting for the segment lock,
+		// because resharding holds them in the reverse order
+		segment = hashMap.selectSegment(key)
+		segment.lock.RLock()
+		if segment.retired {
+			segment.lock.RUnlock()
+			continue
+		}
+
+		threshold := hashMap.config.contentionThreshold
+		var startTime time.Time
+		if threshold != 0 {
+			startTime = time.Now()
+		}
+
+		action(segment.storage)
+		segment.lock.RUnlock()
+
+		contended = threshold != 0 && time.Since(startTime) > threshold
+		return segment, contended
+	}
+}
+
+func (hashMap *ReshardableHashMap) split(segment *reshardableSegment) bool {
+	// a prefix and a depth of a segment are immutable, so they can be read
+	// without its lock
+	if segment.depth >= hashMap.maxDepth {
+		return false
+	}
+
+	hashMap.reshardingLock.Lock()
+	defer hashMap.reshardingLock.Unlock()
+
+	segment.lock.Lock()
+	defer segment.lock.Unlock()
+
+	if segment.retired {
+		return false
+	}
+
+	var successors []*reshardableSegment
+	for _, prefix := range []uint{
+		segment.prefix,
+		segment.prefix | 1<<segment.depth,
+	} {
+		successors = append(successors, &reshardableSegment{
+			storage: hashMap.config.segmentFactory(),
+			prefix:  prefix,
+			depth:   segment.depth + 1,
+		})
+	}
+
+	segment.storage.Iterate(func(key Key, value interface{}) bool {
+		successor := successors[uint(hashMap.hash(key))>>segment.depth&1]
+		successor.storage.Set(key, value)
+
+		return true
+	})
+
+	hashMap.directoryLock.Lock()
+	defer hashMap.directoryLock.Unlock()
+
+	if segment.depth == hashMap.globalDepth {
+		hashMap.directory = append(hashMap.directory, hashMap.directory...)
+		hashMap.globalDepth++
+	}
+	for index, other := range hashMap.directory {
+		if other == segment {
+			hashMap.directory[index] = successors[uint(index)>>segment.depth&1]
+		}
+	}
+
+	segment.retired = true
+	segment.successors = successors
+
+	return true
+}
+
+// if the threshold isn't zero, the segments are merged only if their total
+// size is less than the threshold
+func (hashMap *ReshardableHashMap) merge(
+	segment *reshardableSegment,
+	threshold int,
+) bool {
+	if segment.depth == 0 {
+		return false
+	}
+	// check the threshold in advance to avoid the resharding lock
+	// on each deleting
+	if threshold != 0 && !hashMap.isMergeable(segment, threshold) {
+		return false
+	}
+
+	hashMap.reshardingLock.Lock()
+	defer hashMap.reshardingLock.Unlock()
+
+	buddy := hashMap.selectBuddy(segment)
+	if buddy.depth != segment.depth {
+		return false
+	}
+
+	segments := []*reshardableSegment{segment, buddy}
+	for _, segment := range segments {
+		segment.lock.Lock()
+		defer segment.lock.Unlock()
+
+		// the segment can be retired between the selecting and the locking
+		if segment.retired {
+			return false
+		}
+	}
+
+	if threshold != 0 &&
+		storageSize(segment.storage)+storageSize(buddy.storage) >= threshold {
+		return false
+	}
+
+	depth := segment.depth - 1
+	successor := &reshardableSegment{
+		storage: hashMap.config.segmentFactory(),
+		prefix:  segment.prefix & (1<<depth - 1),
+		depth:   depth,
+	}
+	for _, segment := range segments {
+		segment.storage.Iterate(func(key Key, value interface{}) bool {
+			successor.storage.Set(key, value)
+			return true
+		})
+	}
+
+	hashMap.directoryLock.Lock()
+	defer hashMap.directoryLock.Unlock()
+
+	for index, other := range hashMap.directory {
+		if other == segment || other == buddy {
+			hashMap.directory[index] = successor
+		}
+	}
+	hashMap.shrinkDirectory()
+
+	for _, segment := range segments {
+		segment.retired = true
+		segment.successors = []*reshardableSegment{successor}
+	}
+
+	return true
+}
+
+func (hashMap *ReshardableHashMap) selectBuddy(
+	segment *reshardableSegment,
+) *reshardableSegment {
+	hashMap.directoryLock.RLock()
+	defer hashMap.directoryLock.RUnlock()
+
+	return hashMap.directory[segment.prefix^1<<(segment.depth-1)]
+}
+
+func (hashMap *ReshardableHashMap) isMergeable(
+	segment *reshardableSegment,
+	threshold int,
+) bool {
+	buddy := hashMap.selectBuddy(segment)
+	return buddy.depth == segment.depth &&
+		storageSize(segment.storage)+storageSize(buddy.storage) < threshold
+}
+
+// it halves the directory while its halves are the same
+func (hashMap *ReshardableHashMap) shrinkDirectory() {
+	for hashMap.globalDepth > 0 {
+		half := len(hashMap.directory) / 2
+		for index := 0; index < half; index++ {
+			if hashMap.directory[index] != hashMap.directory[half+index] {
+				return
+			}
+		}
+
+		hashMap.directory = hashMap.directory[:half]
+		hashMap.globalDepth--
+	}
+}
+
+func iterateSegment(
+	segment *reshardableSegment,
+	filter hashFilter,
+	hasher Hasher,
+	handler Handler,
+) bool {
+	segment.lock.RLock()
+	if segment.retired {
+		segment.lock.RUnlock()
+
+		// the filter excludes keys that have been moved to the successors
+		// from other segments (e.g. from a buddy on merging)
+		filter = filter.narrow(segment)
+		for _, successor := range segment.successors {
+			if ok := iterateSegment(successor, filter, hasher, handler); !ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	var items []reshardableItem
+	segment.storage.Iterate(func(key Key, value interface{}) bool {
+		if filter.matches(reshardableHash(hasher, key)) {
+			items = append(items, reshardableItem{key, value})
+		}
+
+		return true
+	})
+	segment.lock.RUnlock()
+
+	for _, item := range items {
+		if ok := handler(item.key, item.value); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func segmentSize(
+	segment *reshardableSegment,
+	filter hashFilter,
+	hasher Hasher,
+) int {
+	segment.lock.RLock()
+	defer segment.lock.RUnlock()
+
+	if segment.retired {
+		filter = filter.narrow(segment)
+
+		var size int
+		for _, successor := range segment.successors {
+			size += segmentSize(successor, filter, hasher)
+		}
+
+		return size
+	}
+
+	if filter == (hashFilter{prefix: segment.prefix, depth: segment.depth}) {
+		return storageSize(segment.storage)
+	}
+
+	var size int
+	segment.storage.Iterate(func(key Key, value interface{}) bool {
+		if filter.matches(reshardableHash(hasher, key)) {
+			size++
+		}
+
+		return true
+	})
+
+	return size
+}
+
+// it returns a directory hash of the key: extendible hashing selects segments
+// by its low bits, which are the reversed high bits of the mixed key hash;
+// so segments are selected by the high bits like in the ConcurrentHashMap
+// structure (see the segmentIndex() function), and the depth of a segment
+// is still a count of the low bits of the directory hash
+func reshardableHash(hasher Hasher, key Key) uint64 {
+	return bits.Reverse64(mixHash(hashKey(hasher, key)))
+}
+
+func uniqueSegments(directory []*reshardableSegment) []*reshardableSegment {
+	var segments []*reshardableSegment
+	for index, segment := range directory {
+		// each segment is first met at the index equal to its prefix
+		if uint(index) == segment.prefix {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+func ceilPowerOfTwo(number int) int {
+	if number <= 1 {
+		return 1
+	}
+
+	return 1 << uint(bits.Len(uint(number-1)))
+}
+
+// the number should be a power of two
+func log2(number int) uint {
+	return uint(bits.TrailingZeros(uint(number)))
+}
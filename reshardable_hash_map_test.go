@@ -0,0 +1,235 @@
+package hashmap
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReshardableHashMap(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(5),
+		WithMaxConcurrencyLevel(100),
+	)
+
+	assert.Len(test, hashMap.directory, 8)
+	assert.Equal(test, uint(3), hashMap.globalDepth)
+	assert.Equal(test, uint(7), hashMap.maxDepth)
+	for index, segment := range hashMap.directory {
+		assert.Equal(test, uint(index), segment.prefix)
+		assert.Equal(test, uint(3), segment.depth)
+		assert.IsType(test, &SynchronizedHashMap{}, segment.storage)
+	}
+}
+
+func TestReshardableHashMap_manualResharding(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(2),
+		WithMaxConcurrencyLevel(4),
+	)
+	for i := 0; i < 100; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i}, i)
+	}
+
+	firstKey := collidingKey{id: 0, hash: 0}
+	// it's a key of the other segment
+	secondKey := collidingKey{id: 1, hash: 1}
+	for hashMap.selectSegment(secondKey) == hashMap.selectSegment(firstKey) {
+		secondKey.id++
+		secondKey.hash++
+	}
+
+	gotSplitOk := hashMap.Split(firstKey)
+	assert.True(test, gotSplitOk)
+	assert.Equal(test, 3, hashMap.SegmentCount())
+	assert.Equal(test, uint(2), hashMap.globalDepth)
+
+	// the maximal concurrency level is reached for this segment
+	gotSplitOk = hashMap.Split(firstKey)
+	assert.False(test, gotSplitOk)
+
+	// the buddy of the other segment is split
+	gotMergeOk := hashMap.Merge(secondKey)
+	assert.False(test, gotMergeOk)
+
+	gotMergeOk = hashMap.Merge(firstKey)
+	assert.True(test, gotMergeOk)
+	assert.Equal(test, 2, hashMap.SegmentCount())
+	assert.Equal(test, uint(1), hashMap.globalDepth)
+
+	gotMergeOk = hashMap.Merge(firstKey)
+	assert.True(test, gotMergeOk)
+	assert.Equal(test, 1, hashMap.SegmentCount())
+	assert.Equal(test, uint(0), hashMap.globalDepth)
+
+	gotMergeOk = hashMap.Merge(firstKey)
+	assert.False(test, gotMergeOk)
+
+	assert.Equal(test, 100, hashMap.Size())
+	for i := 0; i < 100; i++ {
+		gotValue, gotOk := hashMap.Get(collidingKey{id: i, hash: i})
+
+		assert.Equal(test, i, gotValue)
+		assert.True(test, gotOk)
+	}
+}
+
+func TestReshardableHashMap_withHasherAndEqualer(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(4),
+		WithMaxConcurrencyLevel(16),
+		WithConcurrentHasher(caseInsensitiveHasher),
+		WithConcurrentEqualer(caseInsensitiveEqualer),
+	)
+	hashMap.Set(StringKey("key"), "one")
+	hashMap.Set(StringKey("KEY"), "two")
+
+	// keys are moved by the hasher on resharding too
+	for hashMap.Split(StringKey("key")) {
+	}
+
+	gotValue, gotOk := hashMap.Get(StringKey("Key"))
+	assert.Equal(test, "two", gotValue)
+	assert.True(test, gotOk)
+	assert.Equal(test, 1, hashMap.Size())
+
+	hashMap.Delete(StringKey("kEY"))
+	assert.Equal(test, 0, hashMap.Size())
+}
+
+func TestReshardableHashMap_selectSegment_weakHashes(test *testing.T) {
+	hashMap := NewReshardableHashMap(WithConcurrencyLevel(16))
+
+	// the low bits of these hashes are the same, so the segment selection
+	// by them would put all the keys into a single segment
+	touchedSegments := make(map[*reshardableSegment]struct{})
+	for i := 0; i < 256; i++ {
+		segment := hashMap.selectSegment(collidingKey{id: i, hash: i << 4})
+		touchedSegments[segment] = struct{}{}
+	}
+
+	assert.Len(test, touchedSegments, 16)
+}
+
+func TestReshardableHashMap_automaticResharding(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(1),
+		WithSplitThreshold(10),
+		WithMergeThreshold(5),
+	)
+	for i := 0; i < 100; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i}, i)
+	}
+
+	assert.True(test, hashMap.SegmentCount() >= 10)
+	assert.Equal(test, 100, hashMap.Size())
+	for _, segment := range uniqueSegments(hashMap.directory) {
+		assert.True(test, storageSize(segment.storage) <= 10)
+	}
+
+	for i := 0; i < 100; i++ {
+		hashMap.Delete(collidingKey{id: i, hash: i})
+	}
+
+	assert.Equal(test, 1, hashMap.SegmentCount())
+	assert.Equal(test, 0, hashMap.Size())
+}
+
+func TestReshardableHashMap_contention(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(1),
+		WithMaxConcurrencyLevel(4),
+		// any operation is considered as contended
+		WithContentionThreshold(time.Nanosecond),
+	)
+	for i := 0; i < 10; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i}, i)
+	}
+
+	assert.Equal(test, 4, hashMap.SegmentCount())
+	assert.Equal(test, 10, hashMap.Size())
+}
+
+func TestReshardableHashMap_Iterate(test *testing.T) {
+	hashMap := NewReshardableHashMap(WithConcurrencyLevel(2))
+	for i := 0; i < 10; i++ {
+		hashMap.Set(collidingKey{id: i, hash: i}, i)
+	}
+
+	var gotValues []int
+	hashMap.Iterate(func(key Key, value interface{}) bool {
+		gotValues = append(gotValues, value.(int))
+
+		// resharding during iteration shouldn't lead to missed
+		// or duplicated items
+		if len(gotValues) == 1 {
+			hashMap.Split(collidingKey{id: 0, hash: 0})
+			hashMap.Split(collidingKey{id: 1, hash: 1})
+		}
+		if len(gotValues) == 2 {
+			hashMap.Merge(collidingKey{id: 0, hash: 0})
+		}
+
+		return true
+	})
+	sort.Ints(gotValues)
+
+	assert.Equal(test, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, gotValues)
+}
+
+func TestReshardableHashMap_concurrentResharding(test *testing.T) {
+	hashMap := NewReshardableHashMap(
+		WithConcurrencyLevel(2),
+		WithMaxConcurrencyLevel(16),
+	)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < 100; j++ {
+				key := collidingKey{id: i*100 + j, hash: i*100 + j}
+				hashMap.Set(key, i)
+				hashMap.Get(key)
+
+				switch {
+				case j%10 == 0:
+					hashMap.Split(key)
+				case j%10 == 5:
+					hashMap.Merge(key)
+				}
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, 1000, hashMap.Size())
+	for i := 0; i < 1000; i++ {
+		_, gotOk := hashMap.Get(collidingKey{id: i, hash: i})
+		assert.True(test, gotOk)
+	}
+}
+
+func TestCeilPowerOfTwo(test *testing.T) {
+	for _, data := range []struct {
+		number int
+		want   int
+	}{
+		{number: 0, want: 1},
+		{number: 1, want: 1},
+		{number: 2, want: 2},
+		{number: 3, want: 4},
+		{number: 16, want: 16},
+		{number: 17, want: 32},
+	} {
+		got := ceilPowerOfTwo(data.number)
+
+		assert.Equal(test, data.want, got)
+	}
+}
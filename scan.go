@@ -0,0 +1,117 @@
+package hashmap
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// ScanItem ...
+type ScanItem struct {
+	Key   Key
+	Value interface{}
+}
+
+// it's implemented by storages that are able to return their items
+// by parts (see the HashMap.Scan() method)
+type scanner interface {
+	Scan(cursor uint64, count int) (items []ScanItem, nextCursor uint64)
+}
+
+// if the storage doesn't implement the scanner interface, all its items
+// are returned at once via iteration, so the scanning is finished
+func scanStorage(storage Storage, cursor uint64, count int) (
+	items []ScanItem,
+	nextCursor uint64,
+) {
+	if scanner, ok := storage.(scanner); ok {
+		return scanner.Scan(cursor, count)
+	}
+
+	storage.Iterate(func(key Key, value interface{}) bool {
+		items = append(items, ScanItem{Key: key, Value: value})
+		return true
+	})
+
+	return items, 0
+}
+
+// it returns a mask of low bits of hashes that is used by the scanning
+// of the table; the mask corresponds to the closest power of two that isn't
+// less than the capacity
+func scanMask(capacity int) uint64 {
+	return uint64(1)<<uint(bits.Len(uint(capacity-1))) - 1
+}
+
+// it increments the reversed cursor, i.e. it increments high bits
+// of the masked cursor first; so classes of hashes visited with a smaller
+// mask correspond to groups of classes visited with a bigger one,
+// and vice versa
+func nextScanCursor(cursor uint64, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+
+	return bits.Reverse64(cursor)
+}
+
+// it's a position of a class of hashes in order of the scanning
+func scanPosition(class uint64) uint64 {
+	return bits.Reverse64(class)
+}
+
+// it appends items of the class of hashes (i.e. with the same masked hash);
+// the capacity should be a power of two, so the class matches the home
+// position of its items and they are placed in the cluster that starts
+// from it
+func (hashMap HashMap) appendScanClass(
+	items []ScanItem,
+	class uint64,
+	mask uint64,
+) []ScanItem {
+	capacity := len(hashMap.buckets)
+	for index := int(class); ; index = (index + 1) % capacity {
+		bucket := hashMap.buckets[index]
+		if !bucket.occupied {
+			return items
+		}
+
+		if bucket.hash&mask == class {
+			items = append(items, ScanItem{Key: bucket.key, Value: bucket.value})
+		}
+	}
+}
+
+// it's used if the capacity isn't a power of two, so items of the same class
+// of hashes are spread over the table; it walks the whole table and returns
+// items of the following classes in order of the scanning
+func (hashMap HashMap) scanTable(cursor uint64, count int, mask uint64) (
+	items []ScanItem,
+	nextCursor uint64,
+) {
+	startPosition := scanPosition(cursor & mask)
+
+	var buckets []bucket
+	for _, bucket := range hashMap.buckets {
+		if bucket.occupied && scanPosition(bucket.hash&mask) >= startPosition {
+			buckets = append(buckets, bucket)
+		}
+	}
+	sort.Slice(buckets, func(i int, j int) bool {
+		return scanPosition(buckets[i].hash&mask) <
+			scanPosition(buckets[j].hash&mask)
+	})
+
+	for index, bucket := range buckets {
+		class := bucket.hash & mask
+		// the class should be returned entirely
+		isLastOfClass := index == len(buckets)-1 ||
+			buckets[index+1].hash&mask != class
+
+		items = append(items, ScanItem{Key: bucket.key, Value: bucket.value})
+		if isLastOfClass && len(items) >= count {
+			return items, nextScanCursor(class, mask)
+		}
+	}
+
+	return items, 0
+}
@@ -0,0 +1,81 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_scanStorage(test *testing.T) {
+	storage := new(MockStorage)
+	storage.
+		On("Iterate", mock.AnythingOfType("hashmap.Handler")).
+		Return(true).
+		Run(func(args mock.Arguments) {
+			handler := args.Get(0).(Handler)
+			handler(IntKey(1), "one")
+			handler(IntKey(2), "two")
+		}).
+		Once()
+
+	gotItems, gotNextCursor := scanStorage(storage, 0, 1)
+
+	mock.AssertExpectationsForObjects(test, storage)
+	assert.Equal(test, []ScanItem{
+		{Key: IntKey(1), Value: "one"},
+		{Key: IntKey(2), Value: "two"},
+	}, gotItems)
+	assert.Zero(test, gotNextCursor)
+}
+
+func Test_nextScanCursor(test *testing.T) {
+	for _, data := range []struct {
+		name string
+		mask uint64
+		want []uint64
+	}{
+		{
+			name: "with a zero mask",
+			mask: 0,
+			want: []uint64{0},
+		},
+		{
+			name: "with a nonzero mask",
+			mask: 7,
+			want: []uint64{4, 2, 6, 1, 5, 3, 7, 0},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var got []uint64
+			var cursor uint64
+			for {
+				cursor = nextScanCursor(cursor, data.mask)
+				got = append(got, cursor)
+				if cursor == 0 {
+					break
+				}
+			}
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func Test_scanMask(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		capacity int
+		want     uint64
+	}{
+		{name: "with a capacity of 1", capacity: 1, want: 0},
+		{name: "with a power of two", capacity: 16, want: 15},
+		{name: "with not a power of two", capacity: 12, want: 15},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := scanMask(data.capacity)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
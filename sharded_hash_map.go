@@ -0,0 +1,144 @@
+package hashmap
+
+import (
+	"math/rand"
+	"sync"
+	"unsafe"
+)
+
+// it's a size of a CPU cache line on most of modern processors
+const cacheLineSize = 64
+
+type shardedSegmentContent struct {
+	lock     sync.RWMutex
+	innerMap HashMap
+}
+
+// it's a size of padding of the sharded segment content to a multiple
+// of the cache line size; it's zero if the size is already a multiple
+const shardedSegmentPadding = (cacheLineSize -
+	unsafe.Sizeof(shardedSegmentContent{})%cacheLineSize) % cacheLineSize
+
+// it's padded to a multiple of the cache line size, so locks of adjacent
+// segments don't share a cache line
+type shardedSegment struct {
+	shardedSegmentContent
+
+	_ [shardedSegmentPadding]byte
+}
+
+// ShardedHashMap ...
+//
+// It's safe for concurrent access because it uses data sharding like
+// the ConcurrentHashMap structure. Unlike it, segments are instances
+// of the HashMap structure with inline locks that are laid out in a single
+// padded array, so there's no interface dispatch and no false sharing
+// of segments.
+//
+// It uses only the concurrency level and the segment options
+// (see the WithSegmentOptions() function) from the passed options.
+// A segment is selected by the hasher of the segment options, if it's set.
+//
+type ShardedHashMap struct {
+	segments []shardedSegment
+	hasher   Hasher
+}
+
+// NewShardedHashMap ...
+func NewShardedHashMap(options ...ConcurrentOption) *ShardedHashMap {
+	config := newConcurrentConfig(options)
+
+	segments := make([]shardedSegment, config.concurrencyLevel)
+	for index := range segments {
+		segments[index].innerMap = *NewHashMap(config.segmentOptions...)
+	}
+
+	return &ShardedHashMap{
+		segments: segments,
+		hasher:   newConfig(config.segmentOptions).hasher,
+	}
+}
+
+// Get ...
+func (hashMap *ShardedHashMap) Get(key Key) (value interface{}, ok bool) {
+	segment := hashMap.selectSegment(key)
+	segment.lock.RLock()
+	defer segment.lock.RUnlock()
+
+	return segment.innerMap.Get(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It randomizes of iteration order over items and their keys and over segments.
+//
+// A mutex lock of a segment is using only for iteration, not for handling
+// (the handler is called out of lock).
+//
+func (hashMap *ShardedHashMap) Iterate(handler Handler) bool {
+	for _, index := range rand.Perm(len(hashMap.segments)) {
+		if ok := hashMap.segments[index].iterate(handler); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Set ...
+func (hashMap *ShardedHashMap) Set(key Key, value interface{}) {
+	segment := hashMap.selectSegment(key)
+	segment.lock.Lock()
+	defer segment.lock.Unlock()
+
+	segment.innerMap.Set(key, value)
+}
+
+// Delete ...
+func (hashMap *ShardedHashMap) Delete(key Key) {
+	segment := hashMap.selectSegment(key)
+	segment.lock.Lock()
+	defer segment.lock.Unlock()
+
+	segment.innerMap.Delete(key)
+}
+
+// Size ...
+func (hashMap *ShardedHashMap) Size() int {
+	var size int
+	for index := range hashMap.segments {
+		segment := &hashMap.segments[index]
+		segment.lock.RLock()
+		size += segment.innerMap.Size()
+		segment.lock.RUnlock()
+	}
+
+	return size
+}
+
+func (hashMap *ShardedHashMap) update(key Key, handler updateHandler) {
+	segment := hashMap.selectSegment(key)
+	segment.lock.Lock()
+	defer segment.lock.Unlock()
+
+	segment.innerMap.update(key, handler)
+}
+
+func (hashMap *ShardedHashMap) selectSegment(key Key) *shardedSegment {
+	index := segmentIndex(hashKey(hashMap.hasher, key), len(hashMap.segments))
+	return &hashMap.segments[index]
+}
+
+func (segment *shardedSegment) iterate(handler Handler) bool {
+	segment.lock.RLock()
+	defer segment.lock.RUnlock()
+
+	return segment.innerMap.Iterate(func(key Key, value interface{}) bool {
+		segment.lock.RUnlock()
+		defer segment.lock.RLock()
+
+		return handler(key, value)
+	})
+}
@@ -0,0 +1,183 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewShardedHashMap(test *testing.T) {
+	type args struct {
+		options []ConcurrentOption
+	}
+
+	for _, data := range []struct {
+		name             string
+		args             args
+		wantSegmentCount int
+		wantCapacity     int
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			wantSegmentCount: defaultConcurrentConfig.concurrencyLevel,
+			wantCapacity:     defaultConfig.initialCapacity,
+		},
+		{
+			name: "with the set config",
+			args: args{
+				options: []ConcurrentOption{
+					WithConcurrencyLevel(23),
+					WithSegmentOptions(WithInitialCapacity(42)),
+				},
+			},
+			wantSegmentCount: 23,
+			wantCapacity:     42,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewShardedHashMap(data.args.options...)
+
+			assert.Len(test, got.segments, data.wantSegmentCount)
+			for index := range got.segments {
+				innerMap := got.segments[index].innerMap
+				assert.Len(test, innerMap.buckets, data.wantCapacity)
+				assert.NotZero(test, innerMap.seed)
+			}
+		})
+	}
+}
+
+func TestShardedHashMap_padding(test *testing.T) {
+	var segments [2]shardedSegment
+	firstLock := int(uintptr(unsafe.Pointer(&segments[0].lock)))
+	secondLock := int(uintptr(unsafe.Pointer(&segments[1].lock)))
+
+	assert.Zero(test, unsafe.Sizeof(shardedSegment{})%cacheLineSize)
+	assert.True(test, secondLock-firstLock >= cacheLineSize)
+}
+
+func TestShardedHashMap(test *testing.T) {
+	hashMap := NewShardedHashMap(WithConcurrencyLevel(4))
+	for i := 0; i < 100; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+	hashMap.Set(IntKey(0), "updated")
+	for i := 50; i < 110; i++ {
+		hashMap.Delete(IntKey(i))
+	}
+
+	assert.Equal(test, 50, hashMap.Size())
+
+	value, ok := hashMap.Get(IntKey(0))
+	assert.Equal(test, "updated", value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get(IntKey(23))
+	assert.Equal(test, 23, value)
+	assert.True(test, ok)
+
+	value, ok = hashMap.Get(IntKey(75))
+	assert.Nil(test, value)
+	assert.False(test, ok)
+}
+
+func TestShardedHashMap_withHasherAndEqualer(test *testing.T) {
+	hashMap := NewShardedHashMap(WithSegmentOptions(
+		WithHasher(caseInsensitiveHasher),
+		WithEqualer(caseInsensitiveEqualer),
+	))
+	hashMap.Set(StringKey("key"), "one")
+	hashMap.Set(StringKey("KEY"), "two")
+
+	gotValue, gotOk := hashMap.Get(StringKey("Key"))
+	assert.Equal(test, "two", gotValue)
+	assert.True(test, gotOk)
+	assert.Equal(test, 1, hashMap.Size())
+
+	hashMap.Delete(StringKey("kEY"))
+	assert.Equal(test, 0, hashMap.Size())
+}
+
+func TestShardedHashMap_Iterate(test *testing.T) {
+	test.Run("with full iteration", func(test *testing.T) {
+		hashMap := NewShardedHashMap(WithConcurrencyLevel(4))
+		for i := 0; i < 100; i++ {
+			hashMap.Set(IntKey(i), i*i)
+		}
+
+		items := make(map[Key]interface{})
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			items[key] = value
+			return true
+		})
+
+		assert.True(test, ok)
+		assert.Len(test, items, 100)
+		for i := 0; i < 100; i++ {
+			assert.Equal(test, i*i, items[IntKey(i)])
+		}
+	})
+
+	test.Run("with interrupted iteration", func(test *testing.T) {
+		hashMap := NewShardedHashMap(WithConcurrencyLevel(4))
+		for i := 0; i < 100; i++ {
+			hashMap.Set(IntKey(i), i*i)
+		}
+
+		var count int
+		ok := hashMap.Iterate(func(key Key, value interface{}) bool {
+			count++
+			return count < 23
+		})
+
+		assert.False(test, ok)
+		assert.Equal(test, 23, count)
+	})
+
+}
+
+func TestShardedHashMap_concurrency(test *testing.T) {
+	const writerCount = 10
+	const writesPerWriter = 100
+
+	hashMap := NewShardedHashMap(WithConcurrencyLevel(4))
+	hashMap.Set(IntKey(-1), 0)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < writerCount; i++ {
+		waitGroup.Add(2)
+
+		go func(writer int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Set(IntKey(writer*writesPerWriter+j), j)
+				updateStorage(
+					hashMap,
+					IntKey(-1),
+					func(value interface{}, ok bool) (interface{}, bool) {
+						return value.(int) + 1, true
+					},
+				)
+			}
+		}(i)
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < writesPerWriter; j++ {
+				hashMap.Get(IntKey(-1))
+				hashMap.Iterate(func(key Key, value interface{}) bool { return true })
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	counter, _ := hashMap.Get(IntKey(-1))
+	assert.Equal(test, writerCount*writesPerWriter, counter)
+	assert.Equal(test, writerCount*writesPerWriter+1, hashMap.Size())
+}
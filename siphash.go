@@ -0,0 +1,58 @@
+package hashmap
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// it implements the SipHash-2-4 function, see the "SipHash: a fast short-input
+// PRF" paper by J.-P. Aumasson and D. J. Bernstein
+func sipHash(key0 uint64, key1 uint64, data []byte) uint64 {
+	v0 := key0 ^ 0x736f6d6570736575
+	v1 := key1 ^ 0x646f72616e646f6d
+	v2 := key0 ^ 0x6c7967656e657261
+	v3 := key1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+
+	length := len(data)
+	for ; len(data) >= 8; data = data[8:] {
+		message := binary.LittleEndian.Uint64(data)
+		v3 ^= message
+		round()
+		round()
+		v0 ^= message
+	}
+
+	message := uint64(length) << 56
+	for index, value := range data {
+		message |= uint64(value) << (8 * uint(index))
+	}
+	v3 ^= message
+	round()
+	round()
+	v0 ^= message
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+
+	return v0 ^ v1 ^ v2 ^ v3
+}
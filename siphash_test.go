@@ -0,0 +1,55 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSipHash(test *testing.T) {
+	// test vectors from the "SipHash: a fast short-input PRF" paper
+	// and its reference implementation
+	const key0, key1 = 0x0706050403020100, 0x0f0e0d0c0b0a0908
+
+	for _, data := range []struct {
+		name string
+		data []byte
+		want uint64
+	}{
+		{
+			name: "with an empty message",
+			data: nil,
+			want: 0x726fdb47dd0e0e31,
+		},
+		{
+			name: "with a message shorter than a block",
+			data: makeSequence(7),
+			want: 0xab0200f58b01d137,
+		},
+		{
+			name: "with a message of a block",
+			data: makeSequence(8),
+			want: 0x93f5f5799a932462,
+		},
+		{
+			name: "with a message of the paper",
+			data: makeSequence(15),
+			want: 0xa129ca6149be45e5,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := sipHash(key0, key1, data.data)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func makeSequence(length int) []byte {
+	var sequence []byte
+	for i := 0; i < length; i++ {
+		sequence = append(sequence, byte(i))
+	}
+
+	return sequence
+}
@@ -0,0 +1,17 @@
+package hashmap
+
+// it returns a count of items of the storage; if the storage doesn't
+// implement the Sizer interface, its items are counted via iteration
+func storageSize(storage Storage) int {
+	if sizer, ok := storage.(Sizer); ok {
+		return sizer.Size()
+	}
+
+	var size int
+	storage.Iterate(func(key Key, value interface{}) bool {
+		size++
+		return true
+	})
+
+	return size
+}
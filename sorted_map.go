@@ -0,0 +1,294 @@
+package hashmap
+
+import (
+	"math/rand"
+)
+
+type skipListNode struct {
+	key      OrderedKey
+	value    interface{}
+	previous *skipListNode
+	next     []*skipListNode
+}
+
+// SortedMap ...
+//
+// It's a skip list ordered by keys. All its keys should implement
+// the OrderedKey interface, otherwise its methods panic.
+//
+// It's not safe for concurrent access.
+//
+type SortedMap struct {
+	config SortedConfig
+	// it's a sentinel node without a key
+	head  *skipListNode
+	tail  *skipListNode
+	level int
+	size  int
+}
+
+// NewSortedMap ...
+func NewSortedMap(options ...SortedOption) *SortedMap {
+	config := defaultSortedConfig
+	for _, option := range options {
+		option(&config)
+	}
+	config = config.normalize()
+
+	head := &skipListNode{next: make([]*skipListNode, config.maxLevel)}
+	return &SortedMap{config: config, head: head, tail: nil, level: 1, size: 0}
+}
+
+// Get ...
+func (sortedMap *SortedMap) Get(key Key) (value interface{}, ok bool) {
+	node, ok := sortedMap.find(key.(OrderedKey))
+	if !ok {
+		return nil, false
+	}
+
+	return node.value, true
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It iterates in ascending order of keys. The handler may delete
+// the current item.
+//
+func (sortedMap *SortedMap) Iterate(handler Handler) bool {
+	return sortedMap.iterateFrom(sortedMap.head.next[0], nil, handler)
+}
+
+// IterateReverse ...
+//
+// It's the same as the Iterate method, but it iterates in descending order
+// of keys.
+//
+func (sortedMap *SortedMap) IterateReverse(handler Handler) bool {
+	for node := sortedMap.tail; node != nil; node = node.previous {
+		if ok := handler(node.key, node.value); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Range ...
+//
+// It iterates in ascending order over keys from the first one (inclusive)
+// to the second one (exclusive). A nil bound means an unbounded range
+// on the corresponding side.
+//
+// If the handler returns false, iteration is broken.
+//
+func (sortedMap *SortedMap) Range(from Key, to Key, handler Handler) bool {
+	start := sortedMap.head.next[0]
+	if from != nil {
+		start = sortedMap.findPredecessor(from.(OrderedKey)).next[0]
+	}
+
+	var end OrderedKey
+	if to != nil {
+		end = to.(OrderedKey)
+	}
+
+	return sortedMap.iterateFrom(start, end, handler)
+}
+
+// Set ...
+func (sortedMap *SortedMap) Set(key Key, value interface{}) {
+	orderedKey := key.(OrderedKey)
+	predecessors := sortedMap.findPredecessors(orderedKey)
+	if candidate := predecessors[0].next[0]; candidate != nil &&
+		candidate.key.Equals(key) {
+		candidate.value = value
+		return
+	}
+
+	level := sortedMap.randomLevel()
+	if level > sortedMap.level {
+		for index := sortedMap.level; index < level; index++ {
+			predecessors[index] = sortedMap.head
+		}
+
+		sortedMap.level = level
+	}
+
+	node := &skipListNode{
+		key:   orderedKey,
+		value: value,
+		next:  make([]*skipListNode, level),
+	}
+	for index := 0; index < level; index++ {
+		node.next[index] = predecessors[index].next[index]
+		predecessors[index].next[index] = node
+	}
+
+	if predecessors[0] != sortedMap.head {
+		node.previous = predecessors[0]
+	}
+	if node.next[0] != nil {
+		node.next[0].previous = node
+	} else {
+		sortedMap.tail = node
+	}
+
+	sortedMap.size++
+}
+
+// Delete ...
+func (sortedMap *SortedMap) Delete(key Key) {
+	predecessors := sortedMap.findPredecessors(key.(OrderedKey))
+	node := predecessors[0].next[0]
+	if node == nil || !node.key.Equals(key) {
+		return
+	}
+
+	// links of the deleted node are kept, so iteration that stands on it
+	// is able to continue
+	for index := range node.next {
+		if predecessors[index].next[index] == node {
+			predecessors[index].next[index] = node.next[index]
+		}
+	}
+	if node.next[0] != nil {
+		node.next[0].previous = node.previous
+	} else {
+		sortedMap.tail = node.previous
+	}
+
+	for sortedMap.level > 1 && sortedMap.head.next[sortedMap.level-1] == nil {
+		sortedMap.level--
+	}
+
+	sortedMap.size--
+}
+
+// Size ...
+func (sortedMap *SortedMap) Size() int {
+	return sortedMap.size
+}
+
+// Min ...
+func (sortedMap *SortedMap) Min() (key Key, value interface{}, ok bool) {
+	return unpackSkipListNode(sortedMap.head.next[0])
+}
+
+// Max ...
+func (sortedMap *SortedMap) Max() (key Key, value interface{}, ok bool) {
+	return unpackSkipListNode(sortedMap.tail)
+}
+
+// Floor ...
+//
+// It returns the item with the greatest key less than or equal to the passed
+// one.
+//
+func (sortedMap *SortedMap) Floor(key Key) (
+	floorKey Key,
+	value interface{},
+	ok bool,
+) {
+	predecessor := sortedMap.findPredecessor(key.(OrderedKey))
+	if candidate := predecessor.next[0]; candidate != nil &&
+		candidate.key.Equals(key) {
+		return unpackSkipListNode(candidate)
+	}
+	if predecessor == sortedMap.head {
+		return nil, nil, false
+	}
+
+	return unpackSkipListNode(predecessor)
+}
+
+// Ceiling ...
+//
+// It returns the item with the least key greater than or equal to the passed
+// one.
+//
+func (sortedMap *SortedMap) Ceiling(key Key) (
+	ceilingKey Key,
+	value interface{},
+	ok bool,
+) {
+	predecessor := sortedMap.findPredecessor(key.(OrderedKey))
+	return unpackSkipListNode(predecessor.next[0])
+}
+
+func (sortedMap *SortedMap) find(key OrderedKey) (node *skipListNode, ok bool) {
+	node = sortedMap.findPredecessor(key).next[0]
+	if node == nil || !node.key.Equals(key) {
+		return nil, false
+	}
+
+	return node, true
+}
+
+// it returns the last node with a key less than the passed one on the lowest
+// level; unlike the SortedMap.findPredecessors() method, it doesn't allocate
+// memory, so it's used by reading methods
+func (sortedMap *SortedMap) findPredecessor(key OrderedKey) *skipListNode {
+	node := sortedMap.head
+	for level := sortedMap.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && node.next[level].key.Less(key) {
+			node = node.next[level]
+		}
+	}
+
+	return node
+}
+
+// it returns the last node with a key less than the passed one for each level
+func (sortedMap *SortedMap) findPredecessors(key OrderedKey) []*skipListNode {
+	predecessors := make([]*skipListNode, sortedMap.config.maxLevel)
+	node := sortedMap.head
+	for level := sortedMap.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && node.next[level].key.Less(key) {
+			node = node.next[level]
+		}
+
+		predecessors[level] = node
+	}
+
+	return predecessors
+}
+
+func (sortedMap *SortedMap) iterateFrom(
+	start *skipListNode,
+	end OrderedKey,
+	handler Handler,
+) bool {
+	for node := start; node != nil; node = node.next[0] {
+		if end != nil && !node.key.Less(end) {
+			break
+		}
+
+		if ok := handler(node.key, node.value); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (sortedMap *SortedMap) randomLevel() int {
+	level := 1
+	for level < sortedMap.config.maxLevel &&
+		rand.Float64() < sortedMap.config.levelProbability {
+		level++
+	}
+
+	return level
+}
+
+func unpackSkipListNode(
+	node *skipListNode,
+) (key Key, value interface{}, ok bool) {
+	if node == nil {
+		return nil, nil, false
+	}
+
+	return node.key, node.value, true
+}
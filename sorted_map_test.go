@@ -0,0 +1,380 @@
+package hashmap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSortedMap(test *testing.T) {
+	type args struct {
+		options []SortedOption
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want *SortedMap
+	}{
+		{
+			name: "with the default config",
+			args: args{
+				options: nil,
+			},
+			want: &SortedMap{
+				config: defaultSortedConfig,
+				head: &skipListNode{
+					next: make([]*skipListNode, defaultSortedConfig.maxLevel),
+				},
+				tail:  nil,
+				level: 1,
+				size:  0,
+			},
+		},
+		{
+			name: "with the set config",
+			args: args{
+				options: []SortedOption{WithMaxLevel(12), WithLevelProbability(0.5)},
+			},
+			want: &SortedMap{
+				config: SortedConfig{maxLevel: 12, levelProbability: 0.5},
+				head:   &skipListNode{next: make([]*skipListNode, 12)},
+				tail:   nil,
+				level:  1,
+				size:   0,
+			},
+		},
+		{
+			name: "with the zero max level",
+			args: args{
+				options: []SortedOption{WithMaxLevel(0)},
+			},
+			want: &SortedMap{
+				config: SortedConfig{maxLevel: 1, levelProbability: 0.25},
+				head:   &skipListNode{next: make([]*skipListNode, 1)},
+				tail:   nil,
+				level:  1,
+				size:   0,
+			},
+		},
+		{
+			name: "with the negative max level",
+			args: args{
+				options: []SortedOption{WithMaxLevel(-5)},
+			},
+			want: &SortedMap{
+				config: SortedConfig{maxLevel: 1, levelProbability: 0.25},
+				head:   &skipListNode{next: make([]*skipListNode, 1)},
+				tail:   nil,
+				level:  1,
+				size:   0,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewSortedMap(data.args.options...)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestSortedMap_invalidMaxLevel(test *testing.T) {
+	sortedMap := NewSortedMap(WithMaxLevel(0))
+	for _, key := range []int{3, 1, 2} {
+		sortedMap.Set(IntKey(key), key)
+	}
+
+	value, ok := sortedMap.Get(IntKey(2))
+	assert.Equal(test, 2, value)
+	assert.True(test, ok)
+
+	sortedMap.Delete(IntKey(1))
+	var gotKeys []Key
+	sortedMap.Iterate(func(key Key, value interface{}) bool {
+		gotKeys = append(gotKeys, key)
+		return true
+	})
+	assert.Equal(test, []Key{IntKey(2), IntKey(3)}, gotKeys)
+}
+
+func TestSortedMap_Get_allocations(test *testing.T) {
+	sortedMap := NewSortedMap()
+	for i := 0; i < 100; i++ {
+		sortedMap.Set(IntKey(i), i)
+	}
+
+	var key Key = IntKey(50)
+	allocations := testing.AllocsPerRun(100, func() { sortedMap.Get(key) })
+
+	assert.Zero(test, allocations)
+}
+
+func TestSortedMap(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		keys     []int
+		action   func(sortedMap *SortedMap)
+		wantKeys []Key
+	}{
+		{
+			name:     "without items",
+			keys:     nil,
+			action:   func(sortedMap *SortedMap) {},
+			wantKeys: nil,
+		},
+		{
+			name:     "with setting",
+			keys:     []int{5, 1, 4, 2, 3},
+			action:   func(sortedMap *SortedMap) {},
+			wantKeys: []Key{IntKey(1), IntKey(2), IntKey(3), IntKey(4), IntKey(5)},
+		},
+		{
+			name: "with setting by existing keys",
+			keys: []int{5, 1, 4, 2, 3},
+			action: func(sortedMap *SortedMap) {
+				sortedMap.Set(IntKey(1), 1)
+				sortedMap.Set(IntKey(5), 5)
+			},
+			wantKeys: []Key{IntKey(1), IntKey(2), IntKey(3), IntKey(4), IntKey(5)},
+		},
+		{
+			name: "with deleting",
+			keys: []int{5, 1, 4, 2, 3},
+			action: func(sortedMap *SortedMap) {
+				sortedMap.Delete(IntKey(1))
+				sortedMap.Delete(IntKey(3))
+				sortedMap.Delete(IntKey(5))
+				sortedMap.Delete(IntKey(6))
+			},
+			wantKeys: []Key{IntKey(2), IntKey(4)},
+		},
+		{
+			name: "with deleting during iteration",
+			keys: []int{5, 1, 4, 2, 3},
+			action: func(sortedMap *SortedMap) {
+				sortedMap.Iterate(func(key Key, value interface{}) bool {
+					if value.(int)%2 != 0 {
+						sortedMap.Delete(key)
+					}
+
+					return true
+				})
+			},
+			wantKeys: []Key{IntKey(2), IntKey(4)},
+		},
+		{
+			name: "with deleting of all items",
+			keys: []int{5, 1, 4, 2, 3},
+			action: func(sortedMap *SortedMap) {
+				for i := 1; i <= 5; i++ {
+					sortedMap.Delete(IntKey(i))
+				}
+			},
+			wantKeys: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			sortedMap := NewSortedMap()
+			for _, key := range data.keys {
+				sortedMap.Set(IntKey(key), key)
+			}
+
+			data.action(sortedMap)
+
+			var gotKeys []Key
+			sortedMap.Iterate(func(key Key, value interface{}) bool {
+				assert.Equal(test, int(key.(IntKey)), value)
+
+				gotKeys = append(gotKeys, key)
+				return true
+			})
+
+			var gotReversedKeys []Key
+			sortedMap.IterateReverse(func(key Key, value interface{}) bool {
+				gotReversedKeys = append([]Key{key}, gotReversedKeys...)
+				return true
+			})
+
+			gotMinKey, _, gotMinOk := sortedMap.Min()
+			gotMaxKey, _, gotMaxOk := sortedMap.Max()
+
+			assert.Equal(test, data.wantKeys, gotKeys)
+			assert.Equal(test, data.wantKeys, gotReversedKeys)
+			assert.Equal(test, len(data.wantKeys), sortedMap.Size())
+			if len(data.wantKeys) != 0 {
+				assert.Equal(test, data.wantKeys[0], gotMinKey)
+				assert.True(test, gotMinOk)
+				assert.Equal(test, data.wantKeys[len(data.wantKeys)-1], gotMaxKey)
+				assert.True(test, gotMaxOk)
+			} else {
+				assert.False(test, gotMinOk)
+				assert.False(test, gotMaxOk)
+			}
+			for _, key := range data.wantKeys {
+				gotValue, gotOk := sortedMap.Get(key)
+
+				assert.Equal(test, int(key.(IntKey)), gotValue)
+				assert.True(test, gotOk)
+			}
+		})
+	}
+}
+
+func TestSortedMap_Range(test *testing.T) {
+	type args struct {
+		from Key
+		to   Key
+	}
+
+	for _, data := range []struct {
+		name     string
+		args     args
+		wantKeys []Key
+	}{
+		{
+			name:     "with both bounds",
+			args:     args{from: IntKey(20), to: IntKey(40)},
+			wantKeys: []Key{IntKey(20), IntKey(30)},
+		},
+		{
+			name:     "with both bounds between keys",
+			args:     args{from: IntKey(15), to: IntKey(45)},
+			wantKeys: []Key{IntKey(20), IntKey(30), IntKey(40)},
+		},
+		{
+			name:     "with an unbounded start",
+			args:     args{from: nil, to: IntKey(30)},
+			wantKeys: []Key{IntKey(10), IntKey(20)},
+		},
+		{
+			name:     "with an unbounded end",
+			args:     args{from: IntKey(35), to: nil},
+			wantKeys: []Key{IntKey(40), IntKey(50)},
+		},
+		{
+			name: "without bounds",
+			args: args{from: nil, to: nil},
+			wantKeys: []Key{
+				IntKey(10),
+				IntKey(20),
+				IntKey(30),
+				IntKey(40),
+				IntKey(50),
+			},
+		},
+		{
+			name:     "with an empty range",
+			args:     args{from: IntKey(30), to: IntKey(30)},
+			wantKeys: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			sortedMap := NewSortedMap()
+			for i := 5; i >= 1; i-- {
+				sortedMap.Set(IntKey(i*10), i*10)
+			}
+
+			var gotKeys []Key
+			gotOk := sortedMap.Range(
+				data.args.from,
+				data.args.to,
+				func(key Key, value interface{}) bool {
+					gotKeys = append(gotKeys, key)
+					return true
+				},
+			)
+
+			assert.Equal(test, data.wantKeys, gotKeys)
+			assert.True(test, gotOk)
+		})
+	}
+}
+
+func TestSortedMap_FloorAndCeiling(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		key           Key
+		wantFloor     Key
+		wantFloorOk   bool
+		wantCeiling   Key
+		wantCeilingOk bool
+	}{
+		{
+			name:          "with a key less than all",
+			key:           IntKey(5),
+			wantFloor:     nil,
+			wantFloorOk:   false,
+			wantCeiling:   IntKey(10),
+			wantCeilingOk: true,
+		},
+		{
+			name:          "with an existing key",
+			key:           IntKey(20),
+			wantFloor:     IntKey(20),
+			wantFloorOk:   true,
+			wantCeiling:   IntKey(20),
+			wantCeilingOk: true,
+		},
+		{
+			name:          "with a key between others",
+			key:           IntKey(25),
+			wantFloor:     IntKey(20),
+			wantFloorOk:   true,
+			wantCeiling:   IntKey(30),
+			wantCeilingOk: true,
+		},
+		{
+			name:          "with a key greater than all",
+			key:           IntKey(35),
+			wantFloor:     IntKey(30),
+			wantFloorOk:   true,
+			wantCeiling:   nil,
+			wantCeilingOk: false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			sortedMap := NewSortedMap()
+			for i := 1; i <= 3; i++ {
+				sortedMap.Set(IntKey(i*10), i*10)
+			}
+
+			gotFloor, _, gotFloorOk := sortedMap.Floor(data.key)
+			gotCeiling, _, gotCeilingOk := sortedMap.Ceiling(data.key)
+
+			assert.Equal(test, data.wantFloor, gotFloor)
+			assert.Equal(test, data.wantFloorOk, gotFloorOk)
+			assert.Equal(test, data.wantCeiling, gotCeiling)
+			assert.Equal(test, data.wantCeilingOk, gotCeilingOk)
+		})
+	}
+}
+
+func TestSortedMap_randomized(test *testing.T) {
+	rand.Seed(1)
+
+	sortedMap := NewSortedMap(WithMaxLevel(8), WithLevelProbability(0.5))
+	builtinMap := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		key := rand.Intn(100)
+		if rand.Intn(3) == 0 {
+			sortedMap.Delete(IntKey(key))
+			delete(builtinMap, key)
+		} else {
+			sortedMap.Set(IntKey(key), i)
+			builtinMap[key] = i
+		}
+	}
+
+	previousKey := -1
+	sortedMap.Iterate(func(key Key, value interface{}) bool {
+		intKey := int(key.(IntKey))
+		assert.True(test, previousKey < intKey)
+		assert.Equal(test, builtinMap[intKey], value)
+
+		previousKey = intKey
+		return true
+	})
+	assert.Equal(test, len(builtinMap), sortedMap.Size())
+}
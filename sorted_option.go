@@ -0,0 +1,55 @@
+package hashmap
+
+// SortedConfig ...
+type SortedConfig struct {
+	maxLevel         int
+	levelProbability float64
+}
+
+// nolint: gochecknoglobals
+var (
+	defaultSortedConfig = SortedConfig{
+		maxLevel:         32,
+		levelProbability: 0.25,
+	}
+)
+
+// SortedOption ...
+type SortedOption func(options *SortedConfig)
+
+// WithMaxLevel ...
+//
+// It's a maximal count of levels of the skip list. It should be about
+// log(1/p) of an expected maximal count of items, where p is the level
+// probability. A non-positive value is replaced by 1.
+//
+// Default: 32.
+//
+func WithMaxLevel(maxLevel int) SortedOption {
+	return func(options *SortedConfig) {
+		options.maxLevel = maxLevel
+	}
+}
+
+// WithLevelProbability ...
+//
+// It's a probability that an item of the skip list is promoted to the next
+// level.
+//
+// Default: 0.25.
+//
+func WithLevelProbability(levelProbability float64) SortedOption {
+	return func(options *SortedConfig) {
+		options.levelProbability = levelProbability
+	}
+}
+
+// it replaces invalid values by the closest valid ones
+// (see the Config.normalize() method)
+func (config SortedConfig) normalize() SortedConfig {
+	if config.maxLevel <= 0 {
+		config.maxLevel = 1
+	}
+
+	return config
+}
@@ -0,0 +1,157 @@
+package hashmap
+
+// Stats ...
+type Stats struct {
+	Size     int
+	Capacity int
+	// a count of items by a length of the probe sequence that is required
+	// to find them; the shortest length is 1
+	ProbeLengths   map[int]int
+	MaxProbeLength int
+	// a cluster is a sequence of adjacent busy buckets
+	Clusters       int
+	ClusterLengths map[int]int
+	EmptySlotRatio float64
+}
+
+// ConcurrentStats ...
+type ConcurrentStats struct {
+	// stats of all segments together
+	Stats
+
+	Segments []Stats
+	// a ratio of the maximal segment size to the mean one;
+	// it's 1 for evenly loaded segments
+	SegmentSizeSkew float64
+}
+
+// StatsProvider ...
+//
+// It's an optional interface of a storage that is able to report its stats.
+//
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// HashScore ...
+type HashScore struct {
+	Keys           int
+	DistinctHashes int
+	Buckets        int
+	UsedBuckets    int
+	MaxBucketLoad  int
+	// it's about 1 for a uniform distribution of keys over buckets,
+	// greater values mean a worse distribution
+	Quality float64
+}
+
+// ScoreHashDistribution ...
+//
+// It distributes the keys over the specified count of buckets in the same way
+// as the HashMap structure does (i.e. by seeded and mixed hashes of the keys),
+// but without probing. The seed is fixed, so the score is reproducible.
+// A non-positive count of buckets is treated as one bucket.
+//
+func ScoreHashDistribution(keys []Key, buckets int) HashScore {
+	if buckets < 1 {
+		buckets = 1
+	}
+
+	hashMap := newHashMapWithCapacity(defaultConfig, buckets)
+	// the seed is arbitrary, but it's fixed, so the score is reproducible
+	hashMap.seed = goldenRatio64
+	hashes := make(map[uint64]struct{})
+	bucketLoads := make([]int, buckets)
+	for _, key := range keys {
+		hashes[hashKey(nil, key)] = struct{}{}
+		bucketLoads[hashMap.homeIndex(key)]++
+	}
+
+	score := HashScore{
+		Keys:           len(keys),
+		DistinctHashes: len(hashes),
+		Buckets:        buckets,
+	}
+	// see the "Compilers: Principles, Techniques, and Tools" book
+	// by A. V. Aho et al., section 7.6
+	var chainCost float64
+	for _, bucketLoad := range bucketLoads {
+		if bucketLoad == 0 {
+			continue
+		}
+
+		score.UsedBuckets++
+		if bucketLoad > score.MaxBucketLoad {
+			score.MaxBucketLoad = bucketLoad
+		}
+
+		chainCost += float64(bucketLoad*(bucketLoad+1)) / 2
+	}
+	if score.Keys != 0 {
+		keyCount, bucketCount := float64(score.Keys), float64(buckets)
+		score.Quality = chainCost /
+			(keyCount / (2 * bucketCount) * (keyCount + 2*bucketCount - 1))
+	}
+
+	return score
+}
+
+func (stats *Stats) addProbeLength(probeLength int) {
+	stats.ProbeLengths[probeLength]++
+	if probeLength > stats.MaxProbeLength {
+		stats.MaxProbeLength = probeLength
+	}
+}
+
+func (stats *Stats) addClusterLength(clusterLength int) {
+	if clusterLength == 0 {
+		return
+	}
+
+	stats.Clusters++
+	stats.ClusterLengths[clusterLength]++
+}
+
+func mergeStats(statsGroup []Stats) Stats {
+	total := Stats{
+		ProbeLengths:   make(map[int]int),
+		ClusterLengths: make(map[int]int),
+	}
+	var emptySlots float64
+	for _, stats := range statsGroup {
+		total.Size += stats.Size
+		total.Capacity += stats.Capacity
+		for probeLength, count := range stats.ProbeLengths {
+			total.ProbeLengths[probeLength] += count
+		}
+		if stats.MaxProbeLength > total.MaxProbeLength {
+			total.MaxProbeLength = stats.MaxProbeLength
+		}
+
+		total.Clusters += stats.Clusters
+		for clusterLength, count := range stats.ClusterLengths {
+			total.ClusterLengths[clusterLength] += count
+		}
+
+		emptySlots += stats.EmptySlotRatio * float64(stats.Capacity)
+	}
+	if total.Capacity != 0 {
+		total.EmptySlotRatio = emptySlots / float64(total.Capacity)
+	}
+
+	return total
+}
+
+// it returns stats of the storage; if the storage doesn't implement
+// the StatsProvider interface, only its size is reported
+func storageStats(storage Storage) Stats {
+	if provider, ok := storage.(StatsProvider); ok {
+		return provider.Stats()
+	}
+
+	return Stats{
+		Size:           storageSize(storage),
+		ProbeLengths:   make(map[int]int),
+		ClusterLengths: make(map[int]int),
+	}
+}
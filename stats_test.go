@@ -0,0 +1,239 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashMap_Stats(test *testing.T) {
+	type fields struct {
+		buckets []bucket
+		size    int
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		want   Stats
+	}{
+		{
+			name: "without buckets",
+			fields: fields{
+				buckets: make([]bucket, 5),
+				size:    0,
+			},
+			want: Stats{
+				Size:           0,
+				Capacity:       5,
+				ProbeLengths:   map[int]int{},
+				MaxProbeLength: 0,
+				Clusters:       0,
+				ClusterLengths: map[int]int{},
+				EmptySlotRatio: 1,
+			},
+		},
+		{
+			name: "with few buckets",
+			fields: fields{
+				buckets: []bucket{
+					1: {key: collidingKey{id: 1, hash: 1}, hash: 1, occupied: true},
+					2: {key: collidingKey{id: 2, hash: 1}, hash: 1, occupied: true},
+					3: {key: collidingKey{id: 3, hash: 1}, hash: 1, occupied: true},
+				},
+				size: 3,
+			},
+			want: Stats{
+				Size:           3,
+				Capacity:       4,
+				ProbeLengths:   map[int]int{1: 1, 2: 1, 3: 1},
+				MaxProbeLength: 3,
+				Clusters:       1,
+				ClusterLengths: map[int]int{3: 1},
+				EmptySlotRatio: 0.25,
+			},
+		},
+		{
+			name: "with few buckets and a cluster wrapped around",
+			fields: fields{
+				buckets: []bucket{
+					0: {key: collidingKey{id: 1, hash: 4}, hash: 4, occupied: true},
+					2: {key: collidingKey{id: 2, hash: 2}, hash: 2, occupied: true},
+					4: {key: collidingKey{id: 3, hash: 4}, hash: 4, occupied: true},
+				},
+				size: 3,
+			},
+			want: Stats{
+				Size:           3,
+				Capacity:       5,
+				ProbeLengths:   map[int]int{1: 2, 2: 1},
+				MaxProbeLength: 2,
+				Clusters:       2,
+				ClusterLengths: map[int]int{1: 1, 2: 1},
+				EmptySlotRatio: 0.4,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := HashMap{buckets: data.fields.buckets, size: data.fields.size}
+			got := hashMap.Stats()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestConcurrentHashMap_Stats(test *testing.T) {
+	hashMap := NewConcurrentHashMap(
+		WithConcurrencyLevel(2),
+		WithSegmentFactory(func() Storage {
+			innerMap := NewHashMap(WithInitialCapacity(4))
+			// positions of keys should be predictable
+			innerMap.seed = 0
+
+			return NewSynchronizedHashMap(WithInnerMap(innerMap))
+		}),
+	)
+	// all keys are in the first segment
+	hashMap.Set(collidingKey{id: 1, hash: 3}, "one")
+	hashMap.Set(collidingKey{id: 2, hash: 7}, "two")
+	hashMap.Set(collidingKey{id: 3, hash: 1}, "three")
+
+	got := hashMap.Stats()
+
+	want := ConcurrentStats{
+		Stats: Stats{
+			Size:           3,
+			Capacity:       8,
+			ProbeLengths:   map[int]int{1: 2, 2: 1},
+			MaxProbeLength: 2,
+			Clusters:       1,
+			ClusterLengths: map[int]int{3: 1},
+			EmptySlotRatio: 0.625,
+		},
+		Segments: []Stats{
+			{
+				Size:           3,
+				Capacity:       4,
+				ProbeLengths:   map[int]int{1: 2, 2: 1},
+				MaxProbeLength: 2,
+				Clusters:       1,
+				ClusterLengths: map[int]int{3: 1},
+				EmptySlotRatio: 0.25,
+			},
+			{
+				Size:           0,
+				Capacity:       4,
+				ProbeLengths:   map[int]int{},
+				MaxProbeLength: 0,
+				Clusters:       0,
+				ClusterLengths: map[int]int{},
+				EmptySlotRatio: 1,
+			},
+		},
+		SegmentSizeSkew: 2,
+	}
+	assert.Equal(test, want, got)
+}
+
+func TestScoreHashDistribution(test *testing.T) {
+	type args struct {
+		keys    []Key
+		buckets int
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want HashScore
+	}{
+		{
+			name: "without keys",
+			args: args{
+				keys:    nil,
+				buckets: 4,
+			},
+			want: HashScore{Buckets: 4},
+		},
+		{
+			name: "with the same hash",
+			args: args{
+				keys: []Key{
+					collidingKey{id: 1, hash: 2},
+					collidingKey{id: 2, hash: 2},
+					collidingKey{id: 3, hash: 2},
+					collidingKey{id: 4, hash: 2},
+				},
+				buckets: 4,
+			},
+			want: HashScore{
+				Keys:           4,
+				DistinctHashes: 1,
+				Buckets:        4,
+				UsedBuckets:    1,
+				MaxBucketLoad:  4,
+				Quality:        10.0 / 5.5,
+			},
+		},
+		{
+			name: "with zero buckets",
+			args: args{
+				keys: []Key{
+					collidingKey{id: 1, hash: 0},
+					collidingKey{id: 2, hash: 3},
+				},
+				buckets: 0,
+			},
+			want: HashScore{
+				Keys:           2,
+				DistinctHashes: 2,
+				Buckets:        1,
+				UsedBuckets:    1,
+				MaxBucketLoad:  2,
+				Quality:        1,
+			},
+		},
+		{
+			name: "with a negative count of buckets",
+			args: args{
+				keys: []Key{
+					collidingKey{id: 1, hash: 0},
+					collidingKey{id: 2, hash: 3},
+				},
+				buckets: -4,
+			},
+			want: HashScore{
+				Keys:           2,
+				DistinctHashes: 2,
+				Buckets:        1,
+				UsedBuckets:    1,
+				MaxBucketLoad:  2,
+				Quality:        1,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := ScoreHashDistribution(data.args.keys, data.args.buckets)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestScoreHashDistribution_mixedHashes(test *testing.T) {
+	const buckets = 1024
+
+	// the raw hashes of the keys are multiples of the count of buckets,
+	// so without mixing they all fall into the same bucket
+	var keys []Key
+	for i := 0; i < buckets; i++ {
+		keys = append(keys, collidingKey{id: i, hash: i * buckets})
+	}
+
+	got := ScoreHashDistribution(keys, buckets)
+
+	assert.Equal(test, buckets, got.DistinctHashes)
+	assert.True(test, got.UsedBuckets > buckets/2)
+	assert.True(test, got.MaxBucketLoad < 10)
+	assert.InDelta(test, 1, got.Quality, 0.1)
+}
@@ -0,0 +1,114 @@
+package hashmap
+
+import (
+	"sync"
+)
+
+// SynchronizedBiMap ...
+//
+// It's safe for concurrent access because it uses a mutex lock to access
+// the inner bidirectional map. Both directions are modified under one lock,
+// so they stay consistent.
+//
+type SynchronizedBiMap struct {
+	// it's shared with inverse views
+	lock     *sync.RWMutex
+	innerMap *BiMap
+}
+
+// NewSynchronizedBiMap ...
+//
+// The options are applied to both inner maps: by keys and by values.
+//
+func NewSynchronizedBiMap(options ...Option) *SynchronizedBiMap {
+	return &SynchronizedBiMap{
+		lock:     new(sync.RWMutex),
+		innerMap: NewBiMap(options...),
+	}
+}
+
+// Get ...
+func (biMap *SynchronizedBiMap) Get(key Key) (value Key, ok bool) {
+	biMap.lock.RLock()
+	defer biMap.lock.RUnlock()
+
+	return biMap.innerMap.Get(key)
+}
+
+// GetByValue ...
+func (biMap *SynchronizedBiMap) GetByValue(value Key) (key Key, ok bool) {
+	biMap.lock.RLock()
+	defer biMap.lock.RUnlock()
+
+	return biMap.innerMap.GetByValue(value)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// Values passed to the handler implement the Key interface.
+//
+// It randomizes of iteration order.
+//
+// A mutex lock is using only for iteration, not for handling (the handler
+// is called out of lock).
+//
+func (biMap *SynchronizedBiMap) Iterate(handler Handler) bool {
+	biMap.lock.RLock()
+	defer biMap.lock.RUnlock()
+
+	return biMap.innerMap.Iterate(func(key Key, value interface{}) bool {
+		biMap.lock.RUnlock()
+		defer biMap.lock.RLock()
+
+		return handler(key, value)
+	})
+}
+
+// Set ...
+//
+// See the BiMap.Set() method.
+//
+func (biMap *SynchronizedBiMap) Set(key Key, value Key) {
+	biMap.lock.Lock()
+	defer biMap.lock.Unlock()
+
+	biMap.innerMap.Set(key, value)
+}
+
+// Delete ...
+func (biMap *SynchronizedBiMap) Delete(key Key) {
+	biMap.lock.Lock()
+	defer biMap.lock.Unlock()
+
+	biMap.innerMap.Delete(key)
+}
+
+// DeleteByValue ...
+func (biMap *SynchronizedBiMap) DeleteByValue(value Key) {
+	biMap.lock.Lock()
+	defer biMap.lock.Unlock()
+
+	biMap.innerMap.DeleteByValue(value)
+}
+
+// Size ...
+func (biMap *SynchronizedBiMap) Size() int {
+	biMap.lock.RLock()
+	defer biMap.lock.RUnlock()
+
+	return biMap.innerMap.Size()
+}
+
+// Inverse ...
+//
+// It returns a view of the map with keys and values swapped. The view shares
+// data and the lock with the original map.
+//
+func (biMap *SynchronizedBiMap) Inverse() *SynchronizedBiMap {
+	return &SynchronizedBiMap{
+		lock:     biMap.lock,
+		innerMap: biMap.innerMap.Inverse(),
+	}
+}
@@ -1,7 +1,8 @@
 package hashmap
 
 import (
-	"sync"
+	"context"
+	"time"
 )
 
 // SynchronizedHashMap ...
@@ -10,8 +11,18 @@ import (
 // the inner map.
 //
 type SynchronizedHashMap struct {
-	lock     sync.RWMutex
-	innerMap Storage
+	lock            contextRWMutex
+	innerMap        Storage
+	instrumentation Instrumentation
+	// it's set if the getting modifies the inner map, so it requires
+	// the exclusive lock
+	exclusiveGet bool
+}
+
+// it's implemented by storages, whose getting may modify them (e.g. see
+// the LinkedHashMap structure in access order)
+type mutatingGetter interface {
+	mutatesOnGet() bool
 }
 
 // NewSynchronizedHashMap ...
@@ -25,13 +36,23 @@ func NewSynchronizedHashMap(
 		option(&config)
 	}
 
-	return &SynchronizedHashMap{innerMap: config.innerMap}
+	getter, ok := config.innerMap.(mutatingGetter)
+	return &SynchronizedHashMap{
+		innerMap:        config.innerMap,
+		instrumentation: config.instrumentation,
+		exclusiveGet:    ok && getter.mutatesOnGet(),
+	}
 }
 
 // Get ...
+//
+// If the getting modifies the inner map (e.g. see the LinkedHashMap structure
+// in access order), the exclusive lock is used instead of the shared one.
+//
 func (hashMap *SynchronizedHashMap) Get(key Key) (value interface{}, ok bool) {
-	hashMap.lock.RLock()
-	defer hashMap.lock.RUnlock()
+	locker := hashMap.getLocker()
+	hashMap.acquire(locker)
+	defer locker.Unlock()
 
 	return hashMap.innerMap.Get(key)
 }
@@ -43,15 +64,16 @@ func (hashMap *SynchronizedHashMap) Get(key Key) (value interface{}, ok bool) {
 // It randomizes of iteration order.
 //
 // A mutex lock is using only for iteration, not for handling (the handler
-// is called out of lock).
+// is called out of lock). The handler shouldn't modify the map (see
+// the SynchronizedHashMap.IterateMutable() method).
 //
 func (hashMap *SynchronizedHashMap) Iterate(handler Handler) bool {
-	hashMap.lock.RLock()
+	hashMap.acquire(hashMap.lock.RLocker())
 	defer hashMap.lock.RUnlock()
 
 	return hashMap.innerMap.Iterate(func(key Key, value interface{}) bool {
 		hashMap.lock.RUnlock()
-		defer hashMap.lock.RLock()
+		defer hashMap.acquire(hashMap.lock.RLocker())
 
 		return handler(key, value)
 	})
@@ -59,7 +81,7 @@ func (hashMap *SynchronizedHashMap) Iterate(handler Handler) bool {
 
 // Set ...
 func (hashMap *SynchronizedHashMap) Set(key Key, value interface{}) {
-	hashMap.lock.Lock()
+	hashMap.acquire(&hashMap.lock)
 	defer hashMap.lock.Unlock()
 
 	hashMap.innerMap.Set(key, value)
@@ -67,8 +89,251 @@ func (hashMap *SynchronizedHashMap) Set(key Key, value interface{}) {
 
 // Delete ...
 func (hashMap *SynchronizedHashMap) Delete(key Key) {
-	hashMap.lock.Lock()
+	hashMap.acquire(&hashMap.lock)
 	defer hashMap.lock.Unlock()
 
 	hashMap.innerMap.Delete(key)
 }
+
+// IterateMutable ...
+//
+// It's the same as the HashMap.IterateMutable() method, but it holds
+// the write lock during the whole iteration, so the mutation is atomic.
+// The handler is called under the lock, so it shouldn't access the map.
+//
+// If the inner map doesn't implement the IterateMutable() method, actions
+// are applied via its Set() and Delete() methods after iteration.
+//
+func (hashMap *SynchronizedHashMap) IterateMutable(handler MutableHandler) {
+	hashMap.acquire(&hashMap.lock)
+	defer hashMap.lock.Unlock()
+
+	iterateStorageMutable(hashMap.innerMap, handler)
+}
+
+// DeleteIf ...
+//
+// See the HashMap.DeleteIf() and SynchronizedHashMap.IterateMutable()
+// methods.
+//
+func (hashMap *SynchronizedHashMap) DeleteIf(
+	predicate func(key Key, value interface{}) bool,
+) {
+	hashMap.IterateMutable(deleteIfHandler(predicate))
+}
+
+// UpdateAll ...
+//
+// See the HashMap.UpdateAll() and SynchronizedHashMap.IterateMutable()
+// methods.
+//
+func (hashMap *SynchronizedHashMap) UpdateAll(
+	update func(key Key, value interface{}) interface{},
+) {
+	hashMap.IterateMutable(updateAllHandler(update))
+}
+
+// WithEntry ...
+//
+// It calls the handler with an entry by the key (see the Entry structure)
+// under the write lock, so operations via the entry are atomic. The entry
+// shouldn't be used after the handler returns.
+//
+// If the inner map doesn't provide entries, the entry uses its Get(), Set()
+// and Delete() methods.
+//
+func (hashMap *SynchronizedHashMap) WithEntry(
+	key Key,
+	handler EntryHandler,
+) {
+	hashMap.acquire(&hashMap.lock)
+	defer hashMap.lock.Unlock()
+
+	withStorageEntry(hashMap.innerMap, key, handler)
+}
+
+// Scan ...
+//
+// It's the same as the HashMap.Scan() method, but it holds the read lock
+// during the call.
+//
+// If the inner map doesn't implement the Scan() method, all its items
+// are returned at once, so the scanning is finished.
+//
+func (hashMap *SynchronizedHashMap) Scan(cursor uint64, count int) (
+	items []ScanItem,
+	nextCursor uint64,
+) {
+	hashMap.acquire(hashMap.lock.RLocker())
+	defer hashMap.lock.RUnlock()
+
+	return scanStorage(hashMap.innerMap, cursor, count)
+}
+
+// GetContext ...
+//
+// It's the same as the Get method, but it abandons lock acquisition
+// when the context is done and returns its error.
+//
+func (hashMap *SynchronizedHashMap) GetContext(ctx context.Context, key Key) (
+	value interface{},
+	ok bool,
+	err error,
+) {
+	locker := hashMap.getLocker()
+	if err = hashMap.acquireWithContext(ctx, locker); err != nil {
+		return nil, false, err
+	}
+	defer locker.Unlock()
+
+	value, ok = hashMap.innerMap.Get(key)
+	return value, ok, nil
+}
+
+// IterateContext ...
+//
+// It's the same as the Iterate method, but it abandons lock acquisition
+// when the context is done and returns its error.
+//
+// If the handler returns false, iteration is broken without an error.
+//
+// If the context is done, iteration is broken with its error
+// (see the WithInterruption() function).
+//
+func (hashMap *SynchronizedHashMap) IterateContext(
+	ctx context.Context,
+	handler Handler,
+) (ok bool, err error) {
+	err = hashMap.acquireWithContext(ctx, hashMap.lock.RLocker())
+	if err != nil {
+		return false, err
+	}
+
+	locked := true
+	defer func() {
+		if locked {
+			hashMap.lock.RUnlock()
+		}
+	}()
+
+	handler = WithInterruption(ctx, handler)
+	ok = hashMap.innerMap.Iterate(func(key Key, value interface{}) bool {
+		hashMap.lock.RUnlock()
+		locked = false
+
+		ok := handler(key, value)
+		err = hashMap.acquireWithContext(ctx, hashMap.lock.RLocker())
+		if err != nil {
+			return false
+		}
+		locked = true
+
+		return ok
+	})
+	if !ok && err == nil {
+		err = ctx.Err()
+	}
+
+	return ok, err
+}
+
+// SetContext ...
+//
+// It's the same as the Set method, but it abandons lock acquisition
+// when the context is done and returns its error.
+//
+func (hashMap *SynchronizedHashMap) SetContext(
+	ctx context.Context,
+	key Key,
+	value interface{},
+) error {
+	if err := hashMap.acquireWithContext(ctx, &hashMap.lock); err != nil {
+		return err
+	}
+	defer hashMap.lock.Unlock()
+
+	hashMap.innerMap.Set(key, value)
+	return nil
+}
+
+// DeleteContext ...
+//
+// It's the same as the Delete method, but it abandons lock acquisition
+// when the context is done and returns its error.
+//
+func (hashMap *SynchronizedHashMap) DeleteContext(
+	ctx context.Context,
+	key Key,
+) error {
+	if err := hashMap.acquireWithContext(ctx, &hashMap.lock); err != nil {
+		return err
+	}
+	defer hashMap.lock.Unlock()
+
+	hashMap.innerMap.Delete(key)
+	return nil
+}
+
+// Size ...
+//
+// If the inner map doesn't implement the Sizer interface, its items are
+// counted via iteration.
+//
+func (hashMap *SynchronizedHashMap) Size() int {
+	hashMap.acquire(hashMap.lock.RLocker())
+	defer hashMap.lock.RUnlock()
+
+	return storageSize(hashMap.innerMap)
+}
+
+// Stats ...
+//
+// If the inner map doesn't implement the StatsProvider interface, only its
+// size is reported.
+//
+func (hashMap *SynchronizedHashMap) Stats() Stats {
+	hashMap.acquire(hashMap.lock.RLocker())
+	defer hashMap.lock.RUnlock()
+
+	return storageStats(hashMap.innerMap)
+}
+
+func (hashMap *SynchronizedHashMap) update(key Key, handler updateHandler) {
+	hashMap.acquire(&hashMap.lock)
+	defer hashMap.lock.Unlock()
+
+	updateStorage(hashMap.innerMap, key, handler)
+}
+
+func (hashMap *SynchronizedHashMap) getLocker() contextLocker {
+	if hashMap.exclusiveGet {
+		return &hashMap.lock
+	}
+
+	return hashMap.lock.RLocker()
+}
+
+func (hashMap *SynchronizedHashMap) acquire(locker contextLocker) {
+	if hashMap.instrumentation == nil {
+		locker.Lock()
+		return
+	}
+
+	// the background context is never done, so the acquisition can't fail
+	hashMap.acquireWithContext(context.Background(), locker) // nolint: errcheck
+}
+
+func (hashMap *SynchronizedHashMap) acquireWithContext(
+	ctx context.Context,
+	locker contextLocker,
+) error {
+	if hashMap.instrumentation == nil {
+		return locker.LockContext(ctx)
+	}
+
+	startTime := time.Now()
+	err := locker.LockContext(ctx)
+	hashMap.instrumentation.OnLockContention(time.Since(startTime))
+
+	return err
+}
@@ -1,8 +1,11 @@
 package hashmap
 
 import (
+	"context"
 	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,7 +29,7 @@ func TestNewSynchronizedHashMap(test *testing.T) {
 			want: &SynchronizedHashMap{
 				innerMap: &HashMap{
 					config:  defaultConfig,
-					buckets: make([]*bucket, defaultConfig.initialCapacity),
+					buckets: make([]bucket, defaultConfig.initialCapacity),
 					size:    0,
 				},
 			},
@@ -43,6 +46,7 @@ func TestNewSynchronizedHashMap(test *testing.T) {
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			got := NewSynchronizedHashMap(data.args.options...)
+			resetSeeds(test, got)
 
 			_, ok := got.innerMap.(interface {
 				AssertExpectations(assert.TestingT) bool // nolint: staticcheck
@@ -168,7 +172,7 @@ func TestSynchronizedHashMap(test *testing.T) {
 			gotValue, gotOk := hashMap.Get(key)
 
 			for _, bucket := range hashMap.innerMap.(*HashMap).buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -181,7 +185,7 @@ func TestSynchronizedHashMap(test *testing.T) {
 
 func TestSynchronizedHashMap_Iterate(test *testing.T) {
 	type fields struct {
-		buckets []*bucket
+		buckets []bucket
 	}
 
 	for _, data := range []struct {
@@ -194,7 +198,7 @@ func TestSynchronizedHashMap_Iterate(test *testing.T) {
 		{
 			name: "without buckets",
 			fields: fields{
-				buckets: make([]*bucket, defaultConfig.initialCapacity),
+				buckets: make([]bucket, defaultConfig.initialCapacity),
 			},
 			interruptOnCount: 10,
 			wantBuckets:      nil,
@@ -203,10 +207,10 @@ func TestSynchronizedHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets and without an interrupt",
 			fields: fields{
-				buckets: []*bucket{
-					5: {key: new(MockKey), value: "five"},
-					6: {key: new(MockKey), value: "six"},
-					7: {key: new(MockKey), value: "seven"},
+				buckets: []bucket{
+					5: {key: new(MockKey), value: "five", occupied: true},
+					6: {key: new(MockKey), value: "six", occupied: true},
+					7: {key: new(MockKey), value: "seven", occupied: true},
 				},
 			},
 			interruptOnCount: 10,
@@ -220,10 +224,10 @@ func TestSynchronizedHashMap_Iterate(test *testing.T) {
 		{
 			name: "with few buckets and with an interrupt",
 			fields: fields{
-				buckets: []*bucket{
-					5: {key: new(MockKey), value: "five"},
-					6: {key: new(MockKey), value: "six"},
-					7: {key: new(MockKey), value: "seven"},
+				buckets: []bucket{
+					5: {key: new(MockKey), value: "five", occupied: true},
+					6: {key: new(MockKey), value: "six", occupied: true},
+					7: {key: new(MockKey), value: "seven", occupied: true},
 				},
 			},
 			interruptOnCount: 2,
@@ -242,13 +246,13 @@ func TestSynchronizedHashMap_Iterate(test *testing.T) {
 			innerMap := HashMap{buckets: data.fields.buckets}
 			hashMap := SynchronizedHashMap{innerMap: &innerMap}
 			gotOk := hashMap.Iterate(func(key Key, value interface{}) bool {
-				gotBuckets = append(gotBuckets, bucket{key, value})
+				gotBuckets = append(gotBuckets, bucket{key: key, value: value})
 				// interrupt after a specified count of got buckets
 				return len(gotBuckets) < data.interruptOnCount
 			})
 
 			for _, bucket := range data.fields.buckets {
-				if bucket != nil {
+				if bucket.occupied {
 					mock.AssertExpectationsForObjects(test, bucket.key)
 				}
 			}
@@ -260,10 +264,10 @@ func TestSynchronizedHashMap_Iterate(test *testing.T) {
 
 func TestSynchronizedHashMap_Iterate_order(test *testing.T) {
 	innerMap := HashMap{
-		buckets: []*bucket{
-			5: {key: new(MockKey), value: "five"},
-			6: {key: new(MockKey), value: "six"},
-			7: {key: new(MockKey), value: "seven"},
+		buckets: []bucket{
+			5: {key: new(MockKey), value: "five", occupied: true},
+			6: {key: new(MockKey), value: "six", occupied: true},
+			7: {key: new(MockKey), value: "seven", occupied: true},
 		},
 	}
 	hashMap := SynchronizedHashMap{innerMap: &innerMap}
@@ -271,19 +275,19 @@ func TestSynchronizedHashMap_Iterate_order(test *testing.T) {
 	var gotBucketsOne []bucket
 	rand.Seed(1)
 	gotOkOne := hashMap.Iterate(func(key Key, value interface{}) bool {
-		gotBucketsOne = append(gotBucketsOne, bucket{key, value})
+		gotBucketsOne = append(gotBucketsOne, bucket{key: key, value: value})
 		return true
 	})
 
 	var gotBucketsTwo []bucket
 	rand.Seed(2)
 	gotOkTwo := hashMap.Iterate(func(key Key, value interface{}) bool {
-		gotBucketsTwo = append(gotBucketsTwo, bucket{key, value})
+		gotBucketsTwo = append(gotBucketsTwo, bucket{key: key, value: value})
 		return true
 	})
 
 	for _, bucket := range hashMap.innerMap.(*HashMap).buckets {
-		if bucket != nil {
+		if bucket.occupied {
 			mock.AssertExpectationsForObjects(test, bucket.key)
 		}
 	}
@@ -304,3 +308,341 @@ func TestSynchronizedHashMap_Iterate_order(test *testing.T) {
 	assert.Equal(test, wantBucketsTwo, gotBucketsTwo)
 	assert.True(test, gotOkTwo)
 }
+
+func TestSynchronizedHashMap_Get_accessOrder(test *testing.T) {
+	const readerCount = 10
+	const readsPerReader = 100
+
+	innerMap := NewLinkedHashMap(WithAccessOrder(true))
+	for i := 0; i < readerCount; i++ {
+		innerMap.Set(IntKey(i), i)
+	}
+
+	hashMap := NewSynchronizedHashMap(WithInnerMap(innerMap))
+	assert.True(test, hashMap.exclusiveGet)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < readerCount; i++ {
+		waitGroup.Add(1)
+
+		go func(reader int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < readsPerReader; j++ {
+				hashMap.Get(IntKey(reader))
+				hashMap.GetContext(context.Background(), IntKey(reader)) // nolint: errcheck
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, readerCount, hashMap.Size())
+	assert.Zero(test, readLockCount(&hashMap.lock))
+	assert.False(test, isWriteLocked(&hashMap.lock))
+}
+
+func TestSynchronizedHashMap_Get_insertionOrder(test *testing.T) {
+	innerMap := NewLinkedHashMap()
+	hashMap := NewSynchronizedHashMap(WithInnerMap(innerMap))
+
+	assert.False(test, hashMap.exclusiveGet)
+}
+
+func TestSynchronizedHashMap_withContext(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		makeCtx  func() (context.Context, context.CancelFunc)
+		lockType string
+		run      func(ctx context.Context, hashMap *SynchronizedHashMap) error
+		wantErr  error
+	}{
+		{
+			name: "getting without a lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			lockType: "",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				_, _, err := hashMap.GetContext(ctx, IntKey(5))
+				return err
+			},
+			wantErr: nil,
+		},
+		{
+			name: "getting with a read lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			lockType: "read",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				_, _, err := hashMap.GetContext(ctx, IntKey(5))
+				return err
+			},
+			wantErr: nil,
+		},
+		{
+			name: "getting with a write lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockType: "write",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				_, _, err := hashMap.GetContext(ctx, IntKey(5))
+				return err
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "iteration with a write lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockType: "write",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				_, err := hashMap.IterateContext(
+					ctx,
+					func(key Key, value interface{}) bool { return true },
+				)
+				return err
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "setting without a lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			lockType: "",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				return hashMap.SetContext(ctx, IntKey(5), "five")
+			},
+			wantErr: nil,
+		},
+		{
+			name: "setting with a read lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockType: "read",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				return hashMap.SetContext(ctx, IntKey(5), "five")
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "deleting with a read lock",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond)
+			},
+			lockType: "read",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				return hashMap.DeleteContext(ctx, IntKey(5))
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "deleting with the cancelled context",
+			makeCtx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx, cancel
+			},
+			lockType: "",
+			run: func(ctx context.Context, hashMap *SynchronizedHashMap) error {
+				return hashMap.DeleteContext(ctx, IntKey(5))
+			},
+			wantErr: context.Canceled,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			hashMap := NewSynchronizedHashMap()
+			switch data.lockType {
+			case "read":
+				hashMap.lock.RLock()
+			case "write":
+				hashMap.lock.Lock()
+			}
+
+			ctx, cancel := data.makeCtx()
+			defer cancel()
+
+			gotErr := data.run(ctx, hashMap)
+			switch data.lockType {
+			case "read":
+				hashMap.lock.RUnlock()
+			case "write":
+				hashMap.lock.Unlock()
+			}
+
+			// the abandoned lock acquisition shouldn't hang the map
+			hashMap.Set(IntKey(5), "five")
+
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestSynchronizedHashMap_IterateContext(test *testing.T) {
+	hashMap := NewSynchronizedHashMap()
+	for i := 0; i < 10; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotCount int
+	gotOk, gotErr := hashMap.IterateContext(
+		ctx,
+		func(key Key, value interface{}) bool {
+			// the lock should be released during handling
+			hashMap.Set(key, value)
+
+			gotCount++
+			if gotCount == 5 {
+				cancel()
+			}
+
+			return true
+		},
+	)
+
+	assert.Equal(test, 5, gotCount)
+	assert.False(test, gotOk)
+	assert.Equal(test, context.Canceled, gotErr)
+}
+
+func TestSynchronizedHashMap_instrumentation(test *testing.T) {
+	instrumentation := new(MockInstrumentation)
+	instrumentation.
+		On("OnLockContention", mock.AnythingOfType("time.Duration")).
+		Times(5)
+
+	hashMap := NewSynchronizedHashMap(
+		WithSynchronizedInstrumentation(instrumentation),
+	)
+	hashMap.Set(IntKey(5), "five")
+	hashMap.Get(IntKey(5))
+	hashMap.Delete(IntKey(5))
+	hashMap.SetContext(context.Background(), IntKey(5), "five") // nolint: errcheck
+	hashMap.Size()
+
+	mock.AssertExpectationsForObjects(test, instrumentation)
+}
+
+func TestSynchronizedHashMap_WithEntry(test *testing.T) {
+	test.Run("with concurrent handlers", func(test *testing.T) {
+		const handlerCount = 10
+		const callsPerHandler = 100
+
+		hashMap := NewSynchronizedHashMap()
+
+		var waitGroup sync.WaitGroup
+		for i := 0; i < handlerCount; i++ {
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+
+				for j := 0; j < callsPerHandler; j++ {
+					hashMap.WithEntry(IntKey(23), func(entry *Entry) {
+						entry.
+							AndModify(func(value interface{}) interface{} {
+								return value.(int) + 1
+							}).
+							OrInsert(1)
+					})
+				}
+			}()
+		}
+		waitGroup.Wait()
+
+		counter, _ := hashMap.Get(IntKey(23))
+		assert.Equal(test, handlerCount*callsPerHandler, counter)
+	})
+
+	test.Run("with an inner map without entries", func(test *testing.T) {
+		innerMap := new(MockStorage)
+		innerMap.On("Get", IntKey(23)).Return(5, true).Once()
+		innerMap.On("Set", IntKey(23), 6).Return().Once()
+
+		hashMap := NewSynchronizedHashMap(WithInnerMap(innerMap))
+		var gotValue interface{}
+		hashMap.WithEntry(IntKey(23), func(entry *Entry) {
+			gotValue = entry.
+				AndModify(func(value interface{}) interface{} {
+					return value.(int) + 1
+				}).
+				OrInsert(1)
+		})
+
+		mock.AssertExpectationsForObjects(test, innerMap)
+		assert.Equal(test, 6, gotValue)
+	})
+}
+
+func TestSynchronizedHashMap_Scan(test *testing.T) {
+	hashMap := NewSynchronizedHashMap()
+	for i := 0; i < 10; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	var gotItems []ScanItem
+	var cursor uint64
+	var callCount int
+	for {
+		var items []ScanItem
+		items, cursor = hashMap.Scan(cursor, 3)
+		callCount++
+
+		gotItems = append(gotItems, items...)
+		if cursor == 0 {
+			break
+		}
+	}
+
+	assert.True(test, callCount > 1)
+	assert.Len(test, gotItems, 10)
+	for i := 0; i < 10; i++ {
+		assert.Contains(test, gotItems, ScanItem{Key: IntKey(i), Value: i})
+	}
+}
+
+func TestSynchronizedHashMap_IterateMutable(test *testing.T) {
+	const keyCount = 10
+	const updaterCount = 10
+
+	hashMap := NewSynchronizedHashMap()
+	for i := 0; i < keyCount; i++ {
+		hashMap.Set(IntKey(i), 0)
+	}
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < updaterCount; i++ {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			hashMap.UpdateAll(func(key Key, value interface{}) interface{} {
+				return value.(int) + 1
+			})
+		}()
+		go func(updater int) {
+			defer waitGroup.Done()
+
+			hashMap.Set(IntKey(keyCount+updater), 0)
+			hashMap.DeleteIf(func(key Key, value interface{}) bool {
+				return int(key.(IntKey)) == keyCount+updater
+			})
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, keyCount, hashMap.Size())
+	for i := 0; i < keyCount; i++ {
+		counter, _ := hashMap.Get(IntKey(i))
+		assert.Equal(test, updaterCount, counter)
+	}
+}
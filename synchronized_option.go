@@ -2,7 +2,9 @@ package hashmap
 
 // SynchronizedConfig ...
 type SynchronizedConfig struct {
-	innerMap Storage
+	innerMap         Storage
+	fallibleInnerMap FallibleStorage
+	instrumentation  Instrumentation
 }
 
 // SynchronizedOption ...
@@ -17,3 +19,30 @@ func WithInnerMap(innerMap Storage) SynchronizedOption {
 		options.innerMap = innerMap
 	}
 }
+
+// WithFallibleInnerMap ...
+//
+// It's used only by the FallibleSynchronizedHashMap structure.
+//
+// Default: the inner map adapted via the FallibleAdapter structure.
+//
+func WithFallibleInnerMap(innerMap FallibleStorage) SynchronizedOption {
+	return func(options *SynchronizedConfig) {
+		options.fallibleInnerMap = innerMap
+	}
+}
+
+// WithSynchronizedInstrumentation ...
+//
+// It's used only by the SynchronizedHashMap structure. The inner map should
+// be configured separately.
+//
+// Default: nil (events aren't reported).
+//
+func WithSynchronizedInstrumentation(
+	instrumentation Instrumentation,
+) SynchronizedOption {
+	return func(options *SynchronizedConfig) {
+		options.instrumentation = instrumentation
+	}
+}
@@ -0,0 +1,179 @@
+package hashmap
+
+import (
+	"sync"
+)
+
+// SynchronizedSortedMap ...
+//
+// It's safe for concurrent access because it uses a mutex lock to access
+// the inner sorted map. It can be used as a segment of the ConcurrentHashMap
+// structure.
+//
+type SynchronizedSortedMap struct {
+	lock     sync.RWMutex
+	innerMap *SortedMap
+}
+
+// NewSynchronizedSortedMap ...
+func NewSynchronizedSortedMap(
+	options ...SortedOption,
+) *SynchronizedSortedMap {
+	return &SynchronizedSortedMap{innerMap: NewSortedMap(options...)}
+}
+
+// Get ...
+func (sortedMap *SynchronizedSortedMap) Get(key Key) (
+	value interface{},
+	ok bool,
+) {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Get(key)
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+// It iterates in ascending order of keys.
+//
+// A mutex lock is using only for iteration, not for handling (the handler
+// is called out of lock).
+//
+func (sortedMap *SynchronizedSortedMap) Iterate(handler Handler) bool {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Iterate(sortedMap.unlockedHandler(handler))
+}
+
+// IterateReverse ...
+//
+// It's the same as the Iterate method, but it iterates in descending order
+// of keys.
+//
+func (sortedMap *SynchronizedSortedMap) IterateReverse(handler Handler) bool {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.IterateReverse(sortedMap.unlockedHandler(handler))
+}
+
+// Range ...
+//
+// See the SortedMap.Range() method.
+//
+// A mutex lock is using only for iteration, not for handling (the handler
+// is called out of lock).
+//
+func (sortedMap *SynchronizedSortedMap) Range(
+	from Key,
+	to Key,
+	handler Handler,
+) bool {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Range(from, to, sortedMap.unlockedHandler(handler))
+}
+
+// Set ...
+func (sortedMap *SynchronizedSortedMap) Set(key Key, value interface{}) {
+	sortedMap.lock.Lock()
+	defer sortedMap.lock.Unlock()
+
+	sortedMap.innerMap.Set(key, value)
+}
+
+// Delete ...
+func (sortedMap *SynchronizedSortedMap) Delete(key Key) {
+	sortedMap.lock.Lock()
+	defer sortedMap.lock.Unlock()
+
+	sortedMap.innerMap.Delete(key)
+}
+
+// Size ...
+func (sortedMap *SynchronizedSortedMap) Size() int {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Size()
+}
+
+// Min ...
+func (sortedMap *SynchronizedSortedMap) Min() (
+	key Key,
+	value interface{},
+	ok bool,
+) {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Min()
+}
+
+// Max ...
+func (sortedMap *SynchronizedSortedMap) Max() (
+	key Key,
+	value interface{},
+	ok bool,
+) {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Max()
+}
+
+// Floor ...
+//
+// See the SortedMap.Floor() method.
+//
+func (sortedMap *SynchronizedSortedMap) Floor(key Key) (
+	floorKey Key,
+	value interface{},
+	ok bool,
+) {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Floor(key)
+}
+
+// Ceiling ...
+//
+// See the SortedMap.Ceiling() method.
+//
+func (sortedMap *SynchronizedSortedMap) Ceiling(key Key) (
+	ceilingKey Key,
+	value interface{},
+	ok bool,
+) {
+	sortedMap.lock.RLock()
+	defer sortedMap.lock.RUnlock()
+
+	return sortedMap.innerMap.Ceiling(key)
+}
+
+func (sortedMap *SynchronizedSortedMap) update(
+	key Key,
+	handler updateHandler,
+) {
+	sortedMap.lock.Lock()
+	defer sortedMap.lock.Unlock()
+
+	updateStorage(sortedMap.innerMap, key, handler)
+}
+
+func (sortedMap *SynchronizedSortedMap) unlockedHandler(
+	handler Handler,
+) Handler {
+	return func(key Key, value interface{}) bool {
+		sortedMap.lock.RUnlock()
+		defer sortedMap.lock.RLock()
+
+		return handler(key, value)
+	}
+}
@@ -0,0 +1,81 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynchronizedSortedMap(test *testing.T) {
+	sortedMap := NewSynchronizedSortedMap()
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			for j := 0; j < 10; j++ {
+				key := i*10 + j
+				sortedMap.Set(IntKey(key), key)
+				sortedMap.Get(IntKey(key))
+				sortedMap.Floor(IntKey(key))
+				sortedMap.Ceiling(IntKey(key))
+				sortedMap.Iterate(func(key Key, value interface{}) bool {
+					return true
+				})
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	var gotKeys []Key
+	gotOk := sortedMap.Range(
+		IntKey(10),
+		IntKey(13),
+		func(key Key, value interface{}) bool {
+			gotKeys = append(gotKeys, key)
+			return true
+		},
+	)
+
+	var gotReversedKeys []Key
+	sortedMap.IterateReverse(func(key Key, value interface{}) bool {
+		gotReversedKeys = append(gotReversedKeys, key)
+		return len(gotReversedKeys) < 2
+	})
+
+	gotMinKey, _, _ := sortedMap.Min()
+	gotMaxKey, _, _ := sortedMap.Max()
+
+	assert.Equal(test, []Key{IntKey(10), IntKey(11), IntKey(12)}, gotKeys)
+	assert.True(test, gotOk)
+	assert.Equal(test, []Key{IntKey(99), IntKey(98)}, gotReversedKeys)
+	assert.Equal(test, IntKey(0), gotMinKey)
+	assert.Equal(test, IntKey(99), gotMaxKey)
+	assert.Equal(test, 100, sortedMap.Size())
+
+	sortedMap.Delete(IntKey(0))
+	_, gotOk = sortedMap.Get(IntKey(0))
+	assert.False(test, gotOk)
+}
+
+func TestSynchronizedSortedMap_asSegment(test *testing.T) {
+	hashMap := NewConcurrentHashMap(
+		WithConcurrencyLevel(4),
+		WithSegmentFactory(func() Storage { return NewSynchronizedSortedMap() }),
+	)
+	for i := 0; i < 100; i++ {
+		hashMap.Set(IntKey(i), i)
+	}
+
+	for i := 0; i < 100; i++ {
+		gotValue, gotOk := hashMap.Get(IntKey(i))
+
+		assert.Equal(test, i, gotValue)
+		assert.True(test, gotOk)
+	}
+	assert.Equal(test, 100, hashMap.Size())
+}
@@ -0,0 +1,31 @@
+package hashmap
+
+// it receives a current value by a key and returns a new one;
+// if the keep flag is false, the item is deleted
+type updateHandler func(value interface{}, ok bool) (
+	newValue interface{},
+	keep bool,
+)
+
+// it's implemented by storages that are able to read and modify an item
+// atomically
+type updater interface {
+	update(key Key, handler updateHandler)
+}
+
+// if the storage doesn't implement the updater interface, it's updated via
+// its Get(), Set() and Delete() methods, i.e. not atomically
+func updateStorage(storage Storage, key Key, handler updateHandler) {
+	if updater, ok := storage.(updater); ok {
+		updater.update(key, handler)
+		return
+	}
+
+	value, ok := storage.Get(key)
+	newValue, keep := handler(value, ok)
+	if keep {
+		storage.Set(key, newValue)
+	} else if ok {
+		storage.Delete(key)
+	}
+}
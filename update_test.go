@@ -0,0 +1,151 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateStorage(test *testing.T) {
+	type args struct {
+		key     Key
+		handler updateHandler
+	}
+
+	for _, data := range []struct {
+		name        string
+		makeStorage func() Storage
+		args        args
+	}{
+		{
+			name: "without the updater interface and with setting",
+			makeStorage: func() Storage {
+				storage := new(MockStorage)
+				storage.On("Get", IntKey(23)).Return(1, true)
+				storage.On("Set", IntKey(23), 2).Return()
+
+				return storage
+			},
+			args: args{
+				key: IntKey(23),
+				handler: func(value interface{}, ok bool) (interface{}, bool) {
+					return value.(int) + 1, true
+				},
+			},
+		},
+		{
+			name: "without the updater interface and with deleting",
+			makeStorage: func() Storage {
+				storage := new(MockStorage)
+				storage.On("Get", IntKey(23)).Return(1, true)
+				storage.On("Delete", IntKey(23)).Return()
+
+				return storage
+			},
+			args: args{
+				key: IntKey(23),
+				handler: func(value interface{}, ok bool) (interface{}, bool) {
+					return nil, false
+				},
+			},
+		},
+		{
+			name: "without the updater interface and without an item",
+			makeStorage: func() Storage {
+				storage := new(MockStorage)
+				storage.On("Get", IntKey(23)).Return(nil, false)
+
+				return storage
+			},
+			args: args{
+				key: IntKey(23),
+				handler: func(value interface{}, ok bool) (interface{}, bool) {
+					return nil, false
+				},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := data.makeStorage()
+			updateStorage(storage, data.args.key, data.args.handler)
+
+			mock.AssertExpectationsForObjects(test, storage)
+		})
+	}
+}
+
+func TestUpdateStorage_withUpdater(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		makeStorage func() Storage
+		concurrent  bool
+	}{
+		{
+			name:        "with the HashMap structure",
+			makeStorage: func() Storage { return NewHashMap() },
+			concurrent:  false,
+		},
+		{
+			name:        "with the SynchronizedHashMap structure",
+			makeStorage: func() Storage { return NewSynchronizedHashMap() },
+			concurrent:  true,
+		},
+		{
+			name:        "with the SynchronizedSortedMap structure",
+			makeStorage: func() Storage { return NewSynchronizedSortedMap() },
+			concurrent:  true,
+		},
+		{
+			name:        "with the ConcurrentHashMap structure",
+			makeStorage: func() Storage { return NewConcurrentHashMap() },
+			concurrent:  true,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := data.makeStorage()
+			increment := func(value interface{}, ok bool) (interface{}, bool) {
+				if !ok {
+					return 1, true
+				}
+
+				return value.(int) + 1, true
+			}
+
+			var waitGroup sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				waitGroup.Add(1)
+
+				update := func() {
+					defer waitGroup.Done()
+
+					for j := 0; j < 10; j++ {
+						updateStorage(storage, IntKey(23), increment)
+					}
+				}
+				if data.concurrent {
+					go update()
+				} else {
+					update()
+				}
+			}
+			waitGroup.Wait()
+
+			gotValue, gotOk := storage.Get(IntKey(23))
+			assert.Equal(test, 100, gotValue)
+			assert.True(test, gotOk)
+
+			updateStorage(
+				storage,
+				IntKey(23),
+				func(value interface{}, ok bool) (interface{}, bool) {
+					return nil, false
+				},
+			)
+
+			_, gotOk = storage.Get(IntKey(23))
+			assert.False(test, gotOk)
+		})
+	}
+}